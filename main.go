@@ -3,15 +3,22 @@ package main
 
 import (
 	"embed"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
 
 	"github.com/stashapp/stash-box/pkg/api"
 	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/dataset"
 	"github.com/stashapp/stash-box/pkg/manager"
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/manager/cron"
+	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/sqlx"
 	"github.com/stashapp/stash-box/pkg/sqlx/postgres"
 	"github.com/stashapp/stash-box/pkg/user"
+	"github.com/stashapp/stash-box/pkg/webhook"
 )
 
 //nolint
@@ -24,12 +31,38 @@ func main() {
 	const databaseProvider = "postgres"
 	db := database.Initialize(databaseProvider, config.GetDatabasePath())
 	txnMgr := sqlx.NewTxnMgr(db, &postgres.Dialect{})
+
+	if pflag.NArg() > 0 {
+		runCommand(txnMgr.Repo(), pflag.Args())
+		return
+	}
+
 	user.CreateRoot(txnMgr.Repo())
 	api.Start(txnMgr, ui)
 	cron.Init(txnMgr)
+	webhook.Start(txnMgr)
 	blockForever()
 }
 
+// runCommand runs a command-line subcommand instead of starting the server.
+func runCommand(fac models.Repo, args []string) {
+	var err error
+	switch {
+	case len(args) == 2 && args[0] == "export":
+		err = dataset.Export(fac, args[1])
+	case len(args) == 2 && args[0] == "import":
+		err = dataset.Import(fac, args[1])
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [export|import] <directory>\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %s\n", args[0], err.Error())
+		os.Exit(1)
+	}
+}
+
 func blockForever() {
 	c := make(chan struct{})
 	<-c
@@ -0,0 +1,187 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type apiKeyTestRunner struct {
+	testRunner
+}
+
+func createAPIKeyTestRunner(t *testing.T) *apiKeyTestRunner {
+	return &apiKeyTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *apiKeyTestRunner) createTestAPIKey(u *models.User, scope models.APIKeyScopeEnum, expires *time.Time) (*models.APIKeyCreateResult, error) {
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, user.ContextUser, u)
+
+	ret, err := s.resolver.Mutation().APIKeyCreate(ctx, models.APIKeyCreateInput{
+		Name:    "test key",
+		Scope:   scope,
+		Expires: expires,
+	})
+	if err != nil {
+		s.t.Errorf("Error creating api key: %s", err.Error())
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *apiKeyTestRunner) testCreateAPIKey() {
+	createdUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+
+	expires := time.Now().Add(time.Hour)
+	created, err := s.createTestAPIKey(createdUser, models.APIKeyScopeEnumRead, &expires)
+	if err != nil {
+		return
+	}
+
+	if created.Key == "" {
+		s.t.Error("API key value is empty")
+	}
+	if created.Key == createdUser.APIKey {
+		s.t.Error("API key value is the same as the primary api key")
+	}
+
+	key, err := user.ValidateAPIKey(dbtest.Repo(), createdUser, created.Key)
+	if err != nil {
+		s.t.Errorf("Error validating api key: %s", err.Error())
+		return
+	}
+	if key == nil || key.ID != created.APIKey.ID {
+		s.t.Error("Validated api key does not match created key")
+	}
+
+	// the primary api key is still valid
+	key, err = user.ValidateAPIKey(dbtest.Repo(), createdUser, createdUser.APIKey)
+	if err != nil || key != nil {
+		s.t.Errorf("Error validating primary api key: %v", err)
+	}
+
+	keys, err := s.resolver.User().APIKeys(s.ctx, createdUser)
+	if err != nil {
+		s.t.Errorf("Error listing api keys: %s", err.Error())
+		return
+	}
+	if len(keys) != 1 || keys[0].ID != created.APIKey.ID {
+		s.t.Errorf("Expected created api key to be listed, got %d keys", len(keys))
+	}
+
+	past := time.Now().Add(-time.Hour)
+	_, err = s.resolver.Mutation().APIKeyCreate(context.WithValue(context.TODO(), user.ContextUser, createdUser), models.APIKeyCreateInput{
+		Name:    "expired key",
+		Scope:   models.APIKeyScopeEnumRead,
+		Expires: &past,
+	})
+	if err == nil {
+		s.t.Error("Expected error creating api key with expiry in the past")
+	}
+}
+
+func (s *apiKeyTestRunner) testRevokeAPIKey() {
+	createdUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+
+	created, err := s.createTestAPIKey(createdUser, models.APIKeyScopeEnumEdit, nil)
+	if err != nil {
+		return
+	}
+	other, err := s.createTestAPIKey(createdUser, models.APIKeyScopeEnumRead, nil)
+	if err != nil {
+		return
+	}
+
+	// other users may not revoke the key
+	otherUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+	ctx := context.WithValue(context.TODO(), user.ContextUser, otherUser)
+	input := models.APIKeyRevokeInput{
+		ID: created.APIKey.ID.String(),
+	}
+	if _, err := s.resolver.Mutation().APIKeyRevoke(ctx, input); err == nil {
+		s.t.Error("Expected error revoking other user's api key")
+	}
+
+	ctx = context.WithValue(context.TODO(), user.ContextUser, createdUser)
+	if _, err := s.resolver.Mutation().APIKeyRevoke(ctx, input); err != nil {
+		s.t.Errorf("Error revoking api key: %s", err.Error())
+		return
+	}
+
+	if _, err := user.ValidateAPIKey(dbtest.Repo(), createdUser, created.Key); err == nil {
+		s.t.Error("Expected error validating revoked api key")
+	}
+
+	// other keys are unaffected
+	if _, err := user.ValidateAPIKey(dbtest.Repo(), createdUser, other.Key); err != nil {
+		s.t.Errorf("Error validating unrevoked api key: %s", err.Error())
+	}
+}
+
+func (s *apiKeyTestRunner) testScopedAPIKey() {
+	createdUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+
+	created, err := s.createTestAPIKey(createdUser, models.APIKeyScopeEnumRead, nil)
+	if err != nil {
+		return
+	}
+
+	// replicate what the server.go code does for scoped keys
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, user.ContextUser, createdUser)
+	ctx = context.WithValue(ctx, user.ContextRoles, user.ScopeRoles([]models.RoleEnum{models.RoleEnumEdit}, models.APIKeyScopeEnumRead))
+	ctx = context.WithValue(ctx, user.ContextAPIKey, created.APIKey)
+
+	if _, err := s.resolver.Mutation().RegenerateAPIKey(ctx, nil); err != user.ErrUnauthorized {
+		s.t.Errorf("RegenerateAPIKey: got %v, want %v", err, user.ErrUnauthorized)
+	}
+
+	if _, err := s.resolver.Mutation().APIKeyCreate(ctx, models.APIKeyCreateInput{
+		Name:  "escalated key",
+		Scope: models.APIKeyScopeEnumEdit,
+	}); err != user.ErrUnauthorized {
+		s.t.Errorf("APIKeyCreate: got %v, want %v", err, user.ErrUnauthorized)
+	}
+
+	if _, err := s.resolver.Mutation().SubmitFingerprint(ctx, models.FingerprintSubmission{}); err != user.ErrUnauthorized {
+		s.t.Errorf("SubmitFingerprint: got %v, want %v", err, user.ErrUnauthorized)
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	pt := createAPIKeyTestRunner(t)
+	pt.testCreateAPIKey()
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	pt := createAPIKeyTestRunner(t)
+	pt.testRevokeAPIKey()
+}
+
+func TestScopedAPIKey(t *testing.T) {
+	pt := createAPIKeyTestRunner(t)
+	pt.testScopedAPIKey()
+}
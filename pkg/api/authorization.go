@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"strings"
 
 	"github.com/gofrs/uuid"
 
+	"github.com/stashapp/stash-box/pkg/manager/edit"
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/user"
 )
@@ -29,6 +31,18 @@ func validateVote(ctx context.Context) error {
 	return user.ValidateRole(ctx, models.RoleEnumVote)
 }
 
+// validateImmediateVote returns an error if the current user has none of
+// the roles allowed to cast immediate votes on the edit by its voting policy.
+func validateImmediateVote(ctx context.Context, e *models.Edit) error {
+	for _, role := range edit.GetVotingPolicy(e).ImmediateVoteRoles {
+		if user.IsRole(ctx, models.RoleEnum(strings.ToUpper(role))) {
+			return nil
+		}
+	}
+
+	return user.ErrUnauthorized
+}
+
 func validateInvite(ctx context.Context) error {
 	return user.ValidateRole(ctx, models.RoleEnumInvite)
 }
@@ -51,3 +65,11 @@ func validateUserOrAdmin(ctx context.Context, userID uuid.UUID) error {
 	}
 	return user.ValidateRole(ctx, models.RoleEnumAdmin)
 }
+
+func validateScope(ctx context.Context, scope models.APIKeyScopeEnum) error {
+	return user.ValidateScope(ctx, scope)
+}
+
+func validateUnscoped(ctx context.Context) error {
+	return user.ValidateUnscoped(ctx)
+}
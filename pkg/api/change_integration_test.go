@@ -0,0 +1,201 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type changeTestRunner struct {
+	testRunner
+}
+
+func createChangeTestRunner(t *testing.T) *changeTestRunner {
+	return &changeTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+// latestCursor pages through the change feed and returns the cursor after
+// the last change.
+func (s *changeTestRunner) latestCursor() string {
+	s.t.Helper()
+	var cursor *string
+	for {
+		result, err := s.resolver.Query().ChangesSince(s.ctx, cursor, nil, nil)
+		if err != nil {
+			s.t.Errorf("Error querying changes: %s", err.Error())
+			return ""
+		}
+		cursor = &result.Cursor
+		if !result.HasMore {
+			return result.Cursor
+		}
+	}
+}
+
+func (s *changeTestRunner) testChangesSince() {
+	cursor := s.latestCursor()
+
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdEdit.ID.String())
+	if err != nil {
+		return
+	}
+	target := s.getEditTagTarget(appliedEdit)
+
+	source, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	targetID := target.ID.String()
+	mergeEdit, err := s.createTestTagEdit(models.OperationEnumMerge, nil, &models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &targetID,
+		MergeSourceIds: []string{source.ID},
+	})
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+
+	limit := 10
+	result, err := s.resolver.Query().ChangesSince(s.ctx, &cursor, nil, &limit)
+	if err != nil {
+		s.t.Errorf("Error querying changes: %s", err.Error())
+		return
+	}
+
+	type expected struct {
+		operation models.OperationEnum
+		targetID  string
+	}
+	want := []expected{
+		{models.OperationEnumCreate, target.ID.String()},
+		{models.OperationEnumCreate, source.ID},
+		{models.OperationEnumModify, target.ID.String()},
+		{models.OperationEnumMerge, source.ID},
+	}
+
+	if len(result.Changes) != len(want) {
+		s.t.Errorf("Expected %d changes, got %d", len(want), len(result.Changes))
+		return
+	}
+
+	r := s.resolver.Change()
+	for i, w := range want {
+		change := result.Changes[i]
+		operation, _ := r.Operation(s.ctx, change)
+		if operation != w.operation {
+			s.fieldMismatch(w.operation, operation, "Operation")
+		}
+		changeTargetID, _ := r.TargetID(s.ctx, change)
+		if changeTargetID != w.targetID {
+			s.fieldMismatch(w.targetID, changeTargetID, "TargetID")
+		}
+	}
+
+	mergedIntoID, _ := r.MergedIntoID(s.ctx, result.Changes[3])
+	if mergedIntoID == nil || *mergedIntoID != target.ID.String() {
+		s.fieldMismatch(target.ID.String(), mergedIntoID, "MergedIntoID")
+	}
+
+	if result.HasMore {
+		s.t.Error("Expected no more changes")
+	}
+
+	// resuming from the returned cursor should return nothing new
+	resumed, err := s.resolver.Query().ChangesSince(s.ctx, &result.Cursor, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error querying changes: %s", err.Error())
+		return
+	}
+	if len(resumed.Changes) != 0 || resumed.Cursor != result.Cursor {
+		s.t.Errorf("Expected no changes after cursor %s, got %d", result.Cursor, len(resumed.Changes))
+	}
+}
+
+func (s *changeTestRunner) testAdminChanges() {
+	cursor := s.latestCursor()
+
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	name := s.generatePerformerName()
+	if _, err := s.resolver.Mutation().PerformerUpdate(s.ctx, models.PerformerUpdateInput{
+		ID:   performer.ID,
+		Name: &name,
+	}); err != nil {
+		s.t.Errorf("Error updating performer: %s", err.Error())
+		return
+	}
+
+	if _, err := s.resolver.Mutation().PerformerDestroy(s.ctx, models.PerformerDestroyInput{
+		ID: performer.ID,
+	}); err != nil {
+		s.t.Errorf("Error destroying performer: %s", err.Error())
+		return
+	}
+
+	result, err := s.resolver.Query().ChangesSince(s.ctx, &cursor, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error querying changes: %s", err.Error())
+		return
+	}
+
+	want := []models.OperationEnum{
+		models.OperationEnumCreate,
+		models.OperationEnumModify,
+		models.OperationEnumDestroy,
+	}
+	if len(result.Changes) != len(want) {
+		s.t.Errorf("Expected %d changes, got %d", len(want), len(result.Changes))
+		return
+	}
+
+	r := s.resolver.Change()
+	for i, w := range want {
+		change := result.Changes[i]
+		operation, _ := r.Operation(s.ctx, change)
+		if operation != w {
+			s.fieldMismatch(w, operation, "Operation")
+		}
+		changeTargetID, _ := r.TargetID(s.ctx, change)
+		if changeTargetID != performer.ID {
+			s.fieldMismatch(performer.ID, changeTargetID, "TargetID")
+		}
+	}
+}
+
+func (s *changeTestRunner) testInvalidCursor() {
+	cursor := "invalid"
+	if _, err := s.resolver.Query().ChangesSince(s.ctx, &cursor, nil, nil); err == nil {
+		s.t.Error("Expected error for invalid cursor")
+	}
+}
+
+func TestChangesSince(t *testing.T) {
+	pt := createChangeTestRunner(t)
+	pt.testChangesSince()
+}
+
+func TestChangesSinceInvalidCursor(t *testing.T) {
+	pt := createChangeTestRunner(t)
+	pt.testInvalidCursor()
+}
+
+func TestChangesSinceAdminChanges(t *testing.T) {
+	pt := createChangeTestRunner(t)
+	pt.testAdminChanges()
+}
@@ -295,6 +295,158 @@ func (s *editTestRunner) testVoteOwnedEditsDisallowed() {
 	}
 }
 
+func (s *editTestRunner) testAmendEdit() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	tagID := createdTag.ID
+	input := models.EditInput{
+		ID:        &tagID,
+		Operation: models.OperationEnumModify,
+	}
+	name := s.generateTagName()
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name}, &input)
+	if err != nil {
+		return
+	}
+
+	ownerCtx := s.ctx
+	voter, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumVote, models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+	voterCtx := context.WithValue(s.ctx, user.ContextUser, voter)
+	if _, err := s.resolver.Mutation().EditVote(voterCtx, models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Vote: models.VoteTypeEnumAccept,
+	}); err != nil {
+		s.t.Errorf("Error voting on edit: %s", err.Error())
+		return
+	}
+
+	editID := createdEdit.ID.String()
+	amendedName := s.generateTagName()
+	amendInput := models.EditInput{
+		ID:        &tagID,
+		Operation: models.OperationEnumModify,
+		EditID:    &editID,
+	}
+	amendedEdit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &amendedName}, &amendInput)
+	if err != nil {
+		return
+	}
+
+	if amendedEdit.ID != createdEdit.ID {
+		s.fieldMismatch(createdEdit.ID, amendedEdit.ID, "ID")
+	}
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), amendedEdit)
+
+	details := s.getEditTagDetails(amendedEdit)
+	if details.Name == nil || *details.Name != amendedName {
+		s.fieldMismatch(amendedName, details.Name, "Name")
+	}
+
+	// votes were cast on the previous details
+	if amendedEdit.VoteCount != 0 {
+		s.fieldMismatch(0, amendedEdit.VoteCount, "VoteCount")
+	}
+	votes, _ := s.resolver.Edit().Votes(s.ctx, amendedEdit)
+	if len(votes) != 0 {
+		s.fieldMismatch(0, len(votes), "Votes")
+	}
+
+	amendments, _ := s.resolver.Edit().Amendments(s.ctx, amendedEdit)
+	if len(amendments) != 1 {
+		s.fieldMismatch(1, len(amendments), "Amendments")
+		return
+	}
+	oldDetails, _ := s.resolver.EditAmendment().OldDetails(s.ctx, amendments[0])
+	if tagDetails, ok := oldDetails.(*models.TagEdit); !ok || tagDetails.Name == nil || *tagDetails.Name != name {
+		s.fieldMismatch(name, oldDetails, "Amendment OldDetails")
+	}
+	newDetails, _ := s.resolver.EditAmendment().Details(s.ctx, amendments[0])
+	if tagDetails, ok := newDetails.(*models.TagEdit); !ok || tagDetails.Name == nil || *tagDetails.Name != amendedName {
+		s.fieldMismatch(amendedName, newDetails, "Amendment Details")
+	}
+
+	// only the submitter can amend the edit
+	s.ctx = voterCtx
+	_, err = s.resolver.Mutation().TagEdit(s.ctx, models.TagEditInput{
+		Edit:    &amendInput,
+		Details: &models.TagEditDetailsInput{Name: &name},
+	})
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("Amending edit: got %v want %v", err, user.ErrUnauthorized)
+	}
+	s.ctx = ownerCtx
+}
+
+func (s *editTestRunner) testImmediateVotePermissions() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	ownerCtx := s.ctx
+	voter, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumVote})
+	if err != nil {
+		return
+	}
+	s.ctx = context.WithValue(s.ctx, user.ContextUser, voter)
+	s.ctx = context.WithValue(s.ctx, user.ContextRoles, []models.RoleEnum{models.RoleEnumVote})
+
+	// only admins may cast immediate votes by default
+	_, err = s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Vote: models.VoteTypeEnumImmediateAccept,
+	})
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("Immediate vote: got %v want %v", err, user.ErrUnauthorized)
+	}
+
+	admin, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumAdmin})
+	if err != nil {
+		return
+	}
+	s.ctx = context.WithValue(ownerCtx, user.ContextUser, admin)
+	votedEdit, err := s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Vote: models.VoteTypeEnumImmediateAccept,
+	})
+	if err != nil {
+		s.t.Errorf("Error casting immediate vote: %s", err.Error())
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), votedEdit)
+	s.verifyEditApplication(true, votedEdit)
+	s.ctx = ownerCtx
+}
+
+func (s *editTestRunner) testVotingPolicies() {
+	cfg, err := s.resolver.Query().GetConfig(s.ctx)
+	if err != nil {
+		s.t.Errorf("Error getting config: %s", err.Error())
+		return
+	}
+
+	expected := len(models.AllTargetTypeEnum) * len(models.AllOperationEnum)
+	if len(cfg.VotingPolicies) != expected {
+		s.fieldMismatch(expected, len(cfg.VotingPolicies), "VotingPolicies")
+		return
+	}
+
+	for _, policy := range cfg.VotingPolicies {
+		destructive := policy.Operation == models.OperationEnumDestroy || policy.Operation == models.OperationEnumMerge
+		if destructive && policy.ApprovalThreshold != 1 {
+			s.fieldMismatch(1, policy.ApprovalThreshold, "ApprovalThreshold")
+		}
+		if len(policy.ImmediateVoteRoles) != 1 || policy.ImmediateVoteRoles[0] != models.RoleEnumAdmin {
+			s.fieldMismatch([]models.RoleEnum{models.RoleEnumAdmin}, policy.ImmediateVoteRoles, "ImmediateVoteRoles")
+		}
+	}
+}
+
 func TestUnauthorisedApplyEditAdmin(t *testing.T) {
 	pt := &editTestRunner{
 		testRunner: *asModify(t),
@@ -350,3 +502,18 @@ func TestVoteOwnedEditsDisallowed(t *testing.T) {
 	pt := createEditTestRunner(t)
 	pt.testVoteOwnedEditsDisallowed()
 }
+
+func TestAmendEdit(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testAmendEdit()
+}
+
+func TestImmediateVotePermissions(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testImmediateVotePermissions()
+}
+
+func TestVotingPolicies(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testVotingPolicies()
+}
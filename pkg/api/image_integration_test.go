@@ -0,0 +1,162 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type imageTestRunner struct {
+	testRunner
+}
+
+func createImageTestRunner(t *testing.T) *imageTestRunner {
+	return &imageTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+// createTestImage stores an image row without a file.
+func (s *imageTestRunner) createTestImage() *models.Image {
+	s.t.Helper()
+
+	id := uuid.Must(uuid.NewV4())
+	var ret *models.Image
+	fac := dbtest.Repo()
+	if err := fac.WithTxn(func() error {
+		var err error
+		ret, err = fac.Image().Create(models.Image{
+			ID:       id,
+			Checksum: id.String(),
+			Width:    10,
+			Height:   10,
+		})
+		return err
+	}); err != nil {
+		s.t.Fatalf("Error creating image: %s", err.Error())
+	}
+	return ret
+}
+
+func (s *imageTestRunner) isUnused(image *models.Image) bool {
+	s.t.Helper()
+
+	var unused bool
+	fac := dbtest.Repo()
+	if err := fac.WithTxn(func() error {
+		var err error
+		unused, err = fac.Image().IsUnused(image.ID)
+		return err
+	}); err != nil {
+		s.t.Fatalf("Error checking image use: %s", err.Error())
+	}
+	return unused
+}
+
+func (s *imageTestRunner) testPendingEditImageIsUsed() {
+	unused := s.createTestImage()
+	if !s.isUnused(unused) {
+		s.t.Error("IsUnused: expected unreferenced image to be unused")
+	}
+
+	added := s.createTestImage()
+	name := s.generatePerformerName()
+	if _, err := s.createTestPerformerEdit(models.OperationEnumCreate, &models.PerformerEditDetailsInput{
+		Name:     &name,
+		ImageIds: []string{added.ID.String()},
+	}, nil, nil); err != nil {
+		return
+	}
+
+	if s.isUnused(added) {
+		s.t.Error("IsUnused: expected image added by pending edit to be used")
+	}
+}
+
+func TestPendingEditImageIsUsed(t *testing.T) {
+	pt := createImageTestRunner(t)
+	pt.testPendingEditImageIsUsed()
+}
+
+func (s *imageTestRunner) testAppliedEditRemovedImageIsUsed() {
+	removed := s.createTestImage()
+	performer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:     s.generatePerformerName(),
+		ImageIds: []string{removed.ID.String()},
+	})
+	if err != nil {
+		return
+	}
+
+	// the image is restored if the edit is reverted
+	name := s.generatePerformerName()
+	id := performer.ID
+	edit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Name: &name,
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}, nil)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	if s.isUnused(removed) {
+		s.t.Error("IsUnused: expected image removed by applied edit to be used")
+	}
+}
+
+func TestAppliedEditRemovedImageIsUsed(t *testing.T) {
+	pt := createImageTestRunner(t)
+	pt.testAppliedEditRemovedImageIsUsed()
+}
+
+func (s *imageTestRunner) testFindSimilarReturnsClosest() {
+	const phash = 0x5a5a5a5a5a5a5a5a
+
+	fac := dbtest.Repo()
+	create := func(imagePHash int64) *models.Image {
+		img := s.createTestImage()
+		img.PHash = sql.NullInt64{Int64: imagePHash, Valid: true}
+		if err := fac.WithTxn(func() error {
+			_, err := fac.Image().Update(*img)
+			return err
+		}); err != nil {
+			s.t.Fatalf("Error updating image: %s", err.Error())
+		}
+		return img
+	}
+
+	// ids are random, so only ordering by distance finds the closest image
+	create(phash ^ 0x7)
+	closest := create(phash ^ 0x1)
+	create(phash ^ 0x3)
+
+	var images models.Images
+	if err := fac.WithTxn(func() error {
+		var err error
+		images, err = fac.Image().FindSimilar(phash, 4, nil, nil, 1)
+		return err
+	}); err != nil {
+		s.t.Fatalf("Error finding similar images: %s", err.Error())
+	}
+
+	if len(images) != 1 || images[0].ID != closest.ID {
+		s.t.Errorf("FindSimilar: expected closest image %s, got %v", closest.ID, images)
+	}
+}
+
+func TestFindSimilarReturnsClosest(t *testing.T) {
+	pt := createImageTestRunner(t)
+	pt.testFindSimilarReturnsClosest()
+}
@@ -556,6 +556,22 @@ func (s *testRunner) applyEdit(id string) (*models.Edit, error) {
 	return appliedEdit, nil
 }
 
+func (s *testRunner) revertEdit(id string) (*models.Edit, error) {
+	s.t.Helper()
+
+	input := models.RevertEditInput{
+		ID: id,
+	}
+	revertEdit, err := s.resolver.Mutation().RevertEdit(s.ctx, input)
+
+	if err != nil {
+		s.t.Errorf("Error reverting edit: %s", err.Error())
+		return nil, err
+	}
+
+	return revertEdit, nil
+}
+
 func (s *testRunner) getEditTagDetails(input *models.Edit) *models.TagEdit {
 	s.t.Helper()
 	r := s.resolver.Edit()
@@ -0,0 +1,51 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/manager/cron"
+)
+
+type jobTestRunner struct {
+	testRunner
+}
+
+func createJobTestRunner(t *testing.T) *jobTestRunner {
+	return &jobTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *jobTestRunner) testQueryJobRuns() {
+	job := cron.ProcessEditsJob
+	if _, err := s.resolver.Query().QueryJobRuns(s.ctx, &job, nil); err != nil {
+		s.t.Errorf("Error querying job runs: %s", err.Error())
+	}
+
+	if _, err := s.resolver.Mutation().JobTrigger(s.ctx, "missing"); err != cron.ErrJobNotFound {
+		s.t.Errorf("Triggering missing job: expected %v, got %v", cron.ErrJobNotFound, err)
+	}
+}
+
+func (s *jobTestRunner) testJobsAdminOnly() {
+	r := asModify(s.t)
+	if _, err := r.resolver.Query().QueryJobs(r.ctx); err == nil {
+		s.t.Error("Expected error querying jobs as non-admin")
+	}
+	if _, err := r.resolver.Mutation().JobTrigger(r.ctx, cron.ProcessEditsJob); err == nil {
+		s.t.Error("Expected error triggering job as non-admin")
+	}
+}
+
+func TestQueryJobRuns(t *testing.T) {
+	pt := createJobTestRunner(t)
+	pt.testQueryJobRuns()
+}
+
+func TestJobsAdminOnly(t *testing.T) {
+	pt := createJobTestRunner(t)
+	pt.testJobsAdminOnly()
+}
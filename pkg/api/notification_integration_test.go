@@ -0,0 +1,155 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type notificationTestRunner struct {
+	testRunner
+}
+
+func createNotificationTestRunner(t *testing.T) *notificationTestRunner {
+	return &notificationTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *notificationTestRunner) notificationTypes(ctx context.Context, u *models.User, unreadOnly bool) []models.NotificationTypeEnum {
+	s.t.Helper()
+
+	notifications, err := s.resolver.User().Notifications(ctx, u, &unreadOnly, nil)
+	if err != nil {
+		s.t.Errorf("Error finding notifications: %s", err.Error())
+		return nil
+	}
+
+	var ret []models.NotificationTypeEnum
+	for _, n := range notifications {
+		t, _ := s.resolver.Notification().Type(ctx, n)
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+func (s *notificationTestRunner) unreadCount(ctx context.Context, u *models.User) int {
+	s.t.Helper()
+
+	count, err := s.resolver.User().UnreadNotificationCount(ctx, u)
+	if err != nil {
+		s.t.Errorf("Error counting unread notifications: %s", err.Error())
+		return -1
+	}
+	return *count
+}
+
+func (s *notificationTestRunner) testEditNotifications() {
+	submitter, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+	submitterCtx := context.WithValue(s.ctx, user.ContextUser, submitter)
+
+	name := s.generateTagName()
+	createdEdit, err := s.resolver.Mutation().TagEdit(submitterCtx, models.TagEditInput{
+		Edit:    &models.EditInput{Operation: models.OperationEnumCreate},
+		Details: &models.TagEditDetailsInput{Name: &name},
+	})
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return
+	}
+
+	voter, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumVote, models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+	voterCtx := context.WithValue(s.ctx, user.ContextUser, voter)
+	if _, err := s.resolver.Mutation().EditVote(voterCtx, models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Vote: models.VoteTypeEnumReject,
+	}); err != nil {
+		s.t.Errorf("Error voting on edit: %s", err.Error())
+		return
+	}
+	if _, err := s.resolver.Mutation().EditComment(voterCtx, models.EditCommentInput{
+		ID:      createdEdit.ID.String(),
+		Comment: "comment",
+	}); err != nil {
+		s.t.Errorf("Error commenting on edit: %s", err.Error())
+		return
+	}
+
+	// notifications are newest first
+	types := s.notificationTypes(submitterCtx, submitter, true)
+	expected := []models.NotificationTypeEnum{models.NotificationTypeEnumEditComment, models.NotificationTypeEnumEditDownVote}
+	assertEnumSlice(s.t, expected, types, "submitter notifications")
+
+	// users are not notified of their own actions
+	if types := s.notificationTypes(voterCtx, voter, false); len(types) != 0 {
+		s.fieldMismatch(0, len(types), "voter notifications")
+	}
+
+	if _, err := s.applyEdit(createdEdit.ID.String()); err != nil {
+		return
+	}
+
+	expected = []models.NotificationTypeEnum{models.NotificationTypeEnumEditApplied}
+	assertEnumSlice(s.t, expected, s.notificationTypes(voterCtx, voter, false), "voter notifications")
+	if count := s.unreadCount(submitterCtx, submitter); count != 3 {
+		s.fieldMismatch(3, count, "UnreadNotificationCount")
+	}
+
+	notifications, err := s.resolver.User().Notifications(submitterCtx, submitter, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error finding notifications: %s", err.Error())
+		return
+	}
+	if _, err := s.resolver.Mutation().MarkNotificationsRead(submitterCtx, models.MarkNotificationsReadInput{
+		Ids: []string{notifications[0].ID.String()},
+	}); err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+	if count := s.unreadCount(submitterCtx, submitter); count != 2 {
+		s.fieldMismatch(2, count, "UnreadNotificationCount")
+	}
+
+	// notifications of other users are not marked
+	if _, err := s.resolver.Mutation().MarkNotificationsRead(submitterCtx, models.MarkNotificationsReadInput{}); err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+	if count := s.unreadCount(submitterCtx, submitter); count != 0 {
+		s.fieldMismatch(0, count, "UnreadNotificationCount")
+	}
+	if count := s.unreadCount(voterCtx, voter); count != 1 {
+		s.fieldMismatch(1, count, "voter UnreadNotificationCount")
+	}
+}
+
+func assertEnumSlice(t *testing.T, expected, actual []models.NotificationTypeEnum, field string) {
+	t.Helper()
+
+	if len(expected) != len(actual) {
+		t.Errorf("%s: expected %v, got %v", field, expected, actual)
+		return
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("%s: expected %v, got %v", field, expected, actual)
+			return
+		}
+	}
+}
+
+func TestEditNotifications(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testEditNotifications()
+}
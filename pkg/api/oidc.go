@@ -0,0 +1,312 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/oidc"
+	"github.com/stashapp/stash-box/pkg/user"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+const (
+	oidcStateKey = "oidcState"
+	oidcNonceKey = "oidcNonce"
+	// set to the id of the user to link the identity to
+	oidcLinkKey = "oidcLink"
+	// set to the id of a user that logged in with the provider and must
+	// still enter a two-factor code, and the time the login expires
+	oidcPendingUserKey   = "oidcPendingUser"
+	oidcPendingExpiryKey = "oidcPendingExpiry"
+
+	oidcCallbackPath = "/oidc/callback"
+	oidcStateLength  = 32
+
+	// page the user is sent to to enter their two-factor code
+	oidcSecondFactorPage = "/login?second_factor=oidc"
+	// time allowed to enter the two-factor code
+	oidcSecondFactorTimeout = 5 * time.Minute
+)
+
+var (
+	oidcMutex    sync.Mutex
+	oidcProvider *oidc.Provider
+)
+
+// getOIDCProvider returns the configured provider, discovering it on first
+// use. Discovery is retried on the next login if it fails.
+func getOIDCProvider(ctx context.Context) (*oidc.Provider, error) {
+	oidcMutex.Lock()
+	defer oidcMutex.Unlock()
+
+	if oidcProvider != nil {
+		return oidcProvider, nil
+	}
+
+	c := config.GetOIDCConfig()
+	if c == nil {
+		return nil, errors.New("oidc login is not configured")
+	}
+
+	p, err := oidc.Discover(ctx, oidc.Config{
+		Issuer:       c.Issuer,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Scopes:       c.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProvider = p
+	return p, nil
+}
+
+func getOIDCRedirectURL(r *http.Request) string {
+	baseURL := config.GetHostURL()
+	if baseURL == "" {
+		baseURL, _ = r.Context().Value(BaseURLCtxKey).(string)
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + oidcCallbackPath
+}
+
+// handleOIDCLogin redirects to the identity provider to log in.
+func handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	startOIDCLogin(w, r, "")
+}
+
+// handleOIDCLink redirects to the identity provider to link an identity to
+// the current user.
+func handleOIDCLink(w http.ResponseWriter, r *http.Request) {
+	currentUser := getCurrentUser(r.Context())
+	if currentUser == nil {
+		http.Error(w, user.ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	startOIDCLogin(w, r, currentUser.ID.String())
+}
+
+func startOIDCLogin(w http.ResponseWriter, r *http.Request, linkUserID string) {
+	provider, err := getOIDCProvider(r.Context())
+	if err != nil {
+		logger.Errorf("Error discovering oidc provider: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	session, err := sessionStore.Get(r, cookieName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	state, err := utils.GenerateRandomKey(oidcStateLength)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	nonce, err := utils.GenerateRandomKey(oidcStateLength)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	session.Values[oidcStateKey] = state
+	session.Values[oidcNonceKey] = nonce
+	if linkUserID != "" {
+		session.Values[oidcLinkKey] = linkUserID
+	} else {
+		delete(session.Values, oidcLinkKey)
+	}
+	session.Options.MaxAge = maxCookieAge
+
+	if err := session.Save(r, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, provider.AuthCodeURL(getOIDCRedirectURL(r), state, nonce), http.StatusFound)
+}
+
+// handleOIDCCallback completes a login or link started by startOIDCLogin.
+func handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	session, err := sessionStore.Get(r, cookieName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	state, _ := session.Values[oidcStateKey].(string)
+	nonce, _ := session.Values[oidcNonceKey].(string)
+	linkUserID, _ := session.Values[oidcLinkKey].(string)
+	delete(session.Values, oidcStateKey)
+	delete(session.Values, oidcNonceKey)
+	delete(session.Values, oidcLinkKey)
+
+	if state == "" || r.FormValue("state") != state {
+		http.Error(w, "invalid oidc state", http.StatusBadRequest)
+		return
+	}
+
+	if providerErr := r.FormValue("error"); providerErr != "" {
+		http.Error(w, providerErr, http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := getOIDCProvider(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	claims, err := provider.Exchange(ctx, r.FormValue("code"), getOIDCRedirectURL(r), nonce)
+	if err != nil {
+		logger.Errorf("Error exchanging oidc code: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	c := config.GetOIDCConfig()
+	identity := user.ExternalIdentity{
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Username: claims.String(c.UsernameClaim),
+	}
+	if claims.EmailVerified {
+		identity.Email = claims.Email
+	}
+
+	fac := getRepo(ctx)
+	if linkUserID != "" {
+		// the user must still be logged in
+		currentUser := getCurrentUser(ctx)
+		if currentUser == nil || currentUser.ID.String() != linkUserID {
+			http.Error(w, user.ErrUnauthorized.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		err = fac.WithTxn(func() error {
+			return user.LinkIdentity(fac, currentUser.ID, identity)
+		})
+	} else {
+		var userID string
+		err = fac.WithTxn(func() error {
+			var txnErr error
+			userID, txnErr = user.AuthenticateExternal(fac, identity, c.AutoProvision)
+			return txnErr
+		})
+
+		// the provider replaces the password, not the second factor
+		if err == nil && !c.SkipTOTP {
+			err = fac.WithTxn(func() error {
+				return user.AuthenticateSecondFactor(fac, userID, "")
+			})
+			if errors.Is(err, user.ErrTOTPRequired) {
+				session.Values[oidcPendingUserKey] = userID
+				session.Values[oidcPendingExpiryKey] = time.Now().Add(oidcSecondFactorTimeout).Unix()
+				session.Options.MaxAge = maxCookieAge
+
+				if err := session.Save(r, w); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				http.Redirect(w, r, oidcSecondFactorPage, http.StatusFound)
+				return
+			}
+		}
+
+		if err == nil {
+			session.Values[userIDKey] = userID
+			session.Options.MaxAge = maxCookieAge
+		}
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), getIdentityErrorStatus(err))
+		return
+	}
+
+	if err := session.Save(r, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// handleOIDCSecondFactor completes a provider login of a user with
+// two-factor authentication enabled, using the code in the request.
+func handleOIDCSecondFactor(w http.ResponseWriter, r *http.Request) {
+	session, err := sessionStore.Get(r, cookieName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	userID, _ := session.Values[oidcPendingUserKey].(string)
+	expiry, _ := session.Values[oidcPendingExpiryKey].(int64)
+	if userID == "" || time.Now().Unix() > expiry {
+		http.Error(w, "no pending login", http.StatusUnauthorized)
+		return
+	}
+
+	fac := getRepo(r.Context())
+	err = fac.WithTxn(func() error {
+		return user.AuthenticateSecondFactor(fac, userID, r.FormValue(codeFormKey))
+	})
+
+	switch {
+	case errors.Is(err, user.ErrTOTPRequired) || errors.Is(err, user.ErrTOTPInvalidCode):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, user.ErrTOTPTooManyAttempts):
+		// the user must log in with the provider again
+		delete(session.Values, oidcPendingUserKey)
+		delete(session.Values, oidcPendingExpiryKey)
+		_ = session.Save(r, w)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	delete(session.Values, oidcPendingUserKey)
+	delete(session.Values, oidcPendingExpiryKey)
+	session.Values[userIDKey] = userID
+	session.Options.MaxAge = maxCookieAge
+
+	if err := session.Save(r, w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func getIdentityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, user.ErrIdentityNotLinked):
+		return http.StatusUnauthorized
+	case errors.Is(err, user.ErrIdentityAlreadyLinked),
+		errors.Is(err, user.ErrIdentityEmailUsed),
+		errors.Is(err, user.ErrIdentityNameUsed):
+		return http.StatusConflict
+	case errors.Is(err, user.ErrEmptyUsername),
+		errors.Is(err, user.ErrUsernameHasWhitespace),
+		errors.Is(err, user.ErrInvalidEmail),
+		errors.Is(err, user.ErrEmailHasWhitespace):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
@@ -940,6 +940,92 @@ func (s *performerEditTestRunner) testApplyMergePerformerEditWithoutAlias() {
 	s.verifyPerformanceAlias(scene, nil)
 }
 
+func (s *performerEditTestRunner) testApplyPerformerRelationshipEdit() {
+	group, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+	member, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+	alternate, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	name := s.generatePerformerName()
+	details := models.PerformerEditDetailsInput{
+		Name: &name,
+		Relationships: []*models.PerformerRelationshipInput{
+			{PerformerID: group.ID, Type: models.PerformerRelationshipTypeMemberOf},
+			{PerformerID: alternate.ID, Type: models.PerformerRelationshipTypeSamePerson},
+		},
+	}
+	id := member.ID
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	edit, err := s.createTestPerformerEdit(models.OperationEnumModify, &details, &editInput, nil)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	groupPerformer, err := s.resolver.Query().FindPerformer(s.ctx, group.ID)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+	relationships, err := s.resolver.Performer().Relationships(s.ctx, groupPerformer)
+	if err != nil {
+		s.t.Errorf("Error getting relationships: %s", err.Error())
+		return
+	}
+	if len(relationships) != 1 || relationships[0].Performer.ID.String() != member.ID || relationships[0].Type != models.PerformerRelationshipTypeGroupMember {
+		s.t.Errorf("Group relationships: got %v, want member %s", relationships, member.ID)
+	}
+
+	alternateID := alternate.ID
+	sameType := models.PerformerRelationshipTypeSamePerson
+	result, err := s.resolver.Query().QueryPerformers(s.ctx, &models.PerformerFilterType{
+		Relationship: &models.PerformerRelationshipCriterionInput{
+			PerformerID: &alternateID,
+			Type:        &sameType,
+		},
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying performers: %s", err.Error())
+		return
+	}
+	if result.Count != 1 || result.Performers[0].ID.String() != member.ID {
+		s.t.Errorf("Relationship filter: got %d performers, want %s", result.Count, member.ID)
+	}
+
+	// removing the relationships from the member removes them from the group
+	details.Relationships = nil
+	edit, err = s.createTestPerformerEdit(models.OperationEnumModify, &details, &editInput, nil)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	relationships, err = s.resolver.Performer().Relationships(s.ctx, groupPerformer)
+	if err != nil {
+		s.t.Errorf("Error getting relationships: %s", err.Error())
+		return
+	}
+	if len(relationships) != 0 {
+		s.t.Errorf("Group relationships: got %d, want 0", len(relationships))
+	}
+}
+
 func TestCreatePerformerEdit(t *testing.T) {
 	pt := createPerformerEditTestRunner(t)
 	pt.testCreatePerformerEdit()
@@ -995,3 +1081,8 @@ func TestApplyMergePerformerEdit(t *testing.T) {
 	pt := createPerformerEditTestRunner(t)
 	pt.testApplyMergePerformerEdit()
 }
+
+func TestApplyPerformerRelationshipEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testApplyPerformerRelationshipEdit()
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"strings"
 
 	"github.com/99designs/gqlgen/graphql"
 
@@ -26,12 +27,21 @@ func (r *Resolver) Mutation() models.MutationResolver {
 func (r *Resolver) Edit() models.EditResolver {
 	return &editResolver{r}
 }
+func (r *Resolver) EditAmendment() models.EditAmendmentResolver {
+	return &editAmendmentResolver{r}
+}
 func (r *Resolver) EditComment() models.EditCommentResolver {
 	return &editCommentResolver{r}
 }
 func (r *Resolver) EditVote() models.EditVoteResolver {
 	return &editVoteResolver{r}
 }
+func (r *Resolver) JobRun() models.JobRunResolver {
+	return &jobRunResolver{r}
+}
+func (r *Resolver) Notification() models.NotificationResolver {
+	return &notificationResolver{r}
+}
 func (r *Resolver) Performer() models.PerformerResolver {
 	return &performerResolver{r}
 }
@@ -59,12 +69,36 @@ func (r *Resolver) Studio() models.StudioResolver {
 func (r *Resolver) Scene() models.SceneResolver {
 	return &sceneResolver{r}
 }
+func (r *Resolver) SceneMarker() models.SceneMarkerResolver {
+	return &sceneMarkerResolver{r}
+}
 func (r *Resolver) User() models.UserResolver {
 	return &userResolver{r}
 }
+func (r *Resolver) Watch() models.WatchResolver {
+	return &watchResolver{r}
+}
+func (r *Resolver) Webhook() models.WebhookResolver {
+	return &webhookResolver{r}
+}
+func (r *Resolver) WebhookDelivery() models.WebhookDeliveryResolver {
+	return &webhookDeliveryResolver{r}
+}
+func (r *Resolver) APIKey() models.APIKeyResolver {
+	return &apiKeyResolver{r}
+}
+func (r *Resolver) Change() models.ChangeResolver {
+	return &changeResolver{r}
+}
+func (r *Resolver) Revision() models.RevisionResolver {
+	return &revisionResolver{r}
+}
 func (r *Resolver) Query() models.QueryResolver {
 	return &queryResolver{r}
 }
+func (r *Resolver) Subscription() models.SubscriptionResolver {
+	return &subscriptionResolver{r}
+}
 
 type mutationResolver struct{ *Resolver }
 
@@ -90,9 +124,41 @@ func (r *queryResolver) GetConfig(ctx context.Context) (*models.StashBoxConfig,
 		VotingPeriod:               config.GetVotingPeriod(),
 		MinDestructiveVotingPeriod: config.GetMinDestructiveVotingPeriod(),
 		VoteCronInterval:           config.GetVoteCronInterval(),
+		OidcLogin:                  config.GetOIDCConfig() != nil,
+		WeightedVoting:             config.GetWeightedVoting(),
+		VotingPolicies:             getVotingPolicies(),
 	}, nil
 }
 
+func getVotingPolicies() []*models.VotingPolicy {
+	var ret []*models.VotingPolicy
+	for _, targetType := range models.AllTargetTypeEnum {
+		for _, operation := range models.AllOperationEnum {
+			policy := config.GetVotingPolicy(targetType.String(), operation.String())
+
+			var roles []models.RoleEnum
+			for _, role := range policy.ImmediateVoteRoles {
+				var r models.RoleEnum
+				if utils.ResolveEnumString(strings.ToUpper(role), &r) {
+					roles = append(roles, r)
+				}
+			}
+
+			ret = append(ret, &models.VotingPolicy{
+				TargetType:               targetType,
+				Operation:                operation,
+				MinVotingPeriod:          policy.MinVotingPeriod,
+				VoteApplicationThreshold: policy.VoteApplicationThreshold,
+				ApprovalThreshold:        policy.ApprovalThreshold,
+				Quorum:                   policy.Quorum,
+				ImmediateVoteRoles:       roles,
+			})
+		}
+	}
+
+	return ret
+}
+
 // wasFieldIncluded returns true if the given field was included in the request.
 // Slices are unmarshalled to empty slices even if the field was omitted. This
 // method determines if it was omitted altogether.
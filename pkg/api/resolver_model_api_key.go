@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type apiKeyResolver struct{ *Resolver }
+
+func (r *apiKeyResolver) ID(ctx context.Context, obj *models.APIKey) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *apiKeyResolver) Scope(ctx context.Context, obj *models.APIKey) (models.APIKeyScopeEnum, error) {
+	var ret models.APIKeyScopeEnum
+	if !utils.ResolveEnumString(obj.Scope, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *apiKeyResolver) Expires(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	if !obj.ExpiresAt.Valid {
+		return nil, nil
+	}
+
+	return &obj.ExpiresAt.Time, nil
+}
+
+func (r *apiKeyResolver) LastUsed(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	if !obj.LastUsedAt.Valid {
+		return nil, nil
+	}
+
+	return &obj.LastUsedAt.Time, nil
+}
+
+func (r *apiKeyResolver) Created(ctx context.Context, obj *models.APIKey) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
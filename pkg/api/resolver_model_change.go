@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type changeResolver struct{ *Resolver }
+
+func (r *changeResolver) TargetType(ctx context.Context, obj *models.Change) (models.TargetTypeEnum, error) {
+	var ret models.TargetTypeEnum
+	if !utils.ResolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *changeResolver) Operation(ctx context.Context, obj *models.Change) (models.OperationEnum, error) {
+	var ret models.OperationEnum
+	if !utils.ResolveEnumString(obj.Operation, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *changeResolver) TargetID(ctx context.Context, obj *models.Change) (string, error) {
+	return obj.TargetID.String(), nil
+}
+
+func (r *changeResolver) MergedIntoID(ctx context.Context, obj *models.Change) (*string, error) {
+	if !obj.MergedIntoID.Valid {
+		return nil, nil
+	}
+
+	ret := obj.MergedIntoID.UUID.String()
+	return &ret, nil
+}
+
+func (r *changeResolver) Target(ctx context.Context, obj *models.Change) (models.EditTarget, error) {
+	fac := r.getRepoFactory(ctx)
+
+	var targetType models.TargetTypeEnum
+	utils.ResolveEnumString(obj.TargetType, &targetType)
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		target, err := fac.Tag().Find(obj.TargetID)
+		if err != nil || target == nil {
+			return nil, err
+		}
+		return target, nil
+	case models.TargetTypeEnumPerformer:
+		target, err := fac.Performer().Find(obj.TargetID)
+		if err != nil || target == nil {
+			return nil, err
+		}
+		return target, nil
+	case models.TargetTypeEnumStudio:
+		target, err := fac.Studio().Find(obj.TargetID)
+		if err != nil || target == nil {
+			return nil, err
+		}
+		return target, nil
+	case models.TargetTypeEnumScene:
+		target, err := fac.Scene().Find(obj.TargetID)
+		if err != nil || target == nil {
+			return nil, err
+		}
+		return target, nil
+	}
+
+	return nil, nil
+}
+
+func (r *changeResolver) Created(ctx context.Context, obj *models.Change) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
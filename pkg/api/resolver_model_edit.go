@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"errors"
+	"math"
 	"sort"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/manager/edit"
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/utils"
 )
@@ -284,6 +286,16 @@ func (r *editResolver) Votes(ctx context.Context, obj *models.Edit) ([]*models.E
 	return ret, nil
 }
 
+func (r *editResolver) VoteCount(ctx context.Context, obj *models.Edit) (int, error) {
+	fac := r.getRepoFactory(ctx)
+	count, err := edit.GetVoteCount(fac, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(count)), nil
+}
+
 func (r *editResolver) Status(ctx context.Context, obj *models.Edit) (models.VoteStatusEnum, error) {
 	var ret models.VoteStatusEnum
 	if !utils.ResolveEnumString(obj.Status, &ret) {
@@ -293,6 +305,20 @@ func (r *editResolver) Status(ctx context.Context, obj *models.Edit) (models.Vot
 	return ret, nil
 }
 
+func (r *editResolver) Reverts(ctx context.Context, obj *models.Edit) (*models.Edit, error) {
+	if !obj.RevertsID.Valid {
+		return nil, nil
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return fac.Edit().Find(obj.RevertsID.UUID)
+}
+
+func (r *editResolver) RevertedBy(ctx context.Context, obj *models.Edit) ([]*models.Edit, error) {
+	fac := r.getRepoFactory(ctx)
+	return fac.Edit().FindReverts(obj.ID)
+}
+
 func (r *editResolver) Options(ctx context.Context, obj *models.Edit) (*models.PerformerEditOptions, error) {
 	if obj.TargetType == models.TargetTypeEnumPerformer.String() {
 		data, err := obj.GetPerformerData()
@@ -308,3 +334,13 @@ func (r *editResolver) Options(ctx context.Context, obj *models.Edit) (*models.P
 	}
 	return nil, nil
 }
+
+func (r *editResolver) ConflictingEdits(ctx context.Context, obj *models.Edit) ([]*models.Edit, error) {
+	fac := r.getRepoFactory(ctx)
+	return edit.FindConflicts(fac, obj)
+}
+
+func (r *editResolver) Amendments(ctx context.Context, obj *models.Edit) ([]*models.EditAmendment, error) {
+	fac := r.getRepoFactory(ctx)
+	return fac.Edit().GetAmendments(obj.ID)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/manager/edit"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type editAmendmentResolver struct{ *Resolver }
+
+func (r *editAmendmentResolver) ID(ctx context.Context, obj *models.EditAmendment) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *editAmendmentResolver) User(ctx context.Context, obj *models.EditAmendment) (*models.User, error) {
+	if !obj.UserID.Valid {
+		return nil, nil
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return fac.User().Find(obj.UserID.UUID)
+}
+
+func (r *editAmendmentResolver) Date(ctx context.Context, obj *models.EditAmendment) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *editAmendmentResolver) OldDetails(ctx context.Context, obj *models.EditAmendment) (models.EditDetails, error) {
+	old, _, err := r.diff(ctx, obj)
+	return old, err
+}
+
+func (r *editAmendmentResolver) Details(ctx context.Context, obj *models.EditAmendment) (models.EditDetails, error) {
+	_, new, err := r.diff(ctx, obj)
+	return new, err
+}
+
+func (r *editAmendmentResolver) diff(ctx context.Context, obj *models.EditAmendment) (models.EditDetails, models.EditDetails, error) {
+	fac := r.getRepoFactory(ctx)
+	amended, err := fac.Edit().Find(obj.EditID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if amended == nil {
+		return nil, nil, errors.New("edit not found")
+	}
+
+	var targetType models.TargetTypeEnum
+	utils.ResolveEnumString(amended.TargetType, &targetType)
+	return edit.AmendmentDiff(targetType, obj)
+}
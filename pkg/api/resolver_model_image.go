@@ -15,13 +15,33 @@ type imageResolver struct{ *Resolver }
 func (r *imageResolver) ID(ctx context.Context, obj *models.Image) (string, error) {
 	return obj.ID.String(), nil
 }
-func (r *imageResolver) URL(ctx context.Context, obj *models.Image) (string, error) {
+func (r *imageResolver) URL(ctx context.Context, obj *models.Image, size *string) (string, error) {
+	// images that were not fetched are only available remotely
+	if obj.Checksum == "" {
+		return obj.RemoteURL.String, nil
+	}
+
+	var variant *models.ImageVariant
+	if size != nil && *size != "" {
+		var err error
+		variant, err = r.getRepoFactory(ctx).Image().FindVariant(obj.ID, *size)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	if config.GetImageBackend() == config.FileBackend {
 		baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 		builder := urlbuilders.NewImageURLBuilder(baseURL, obj.Checksum)
+		if variant != nil {
+			return builder.GetVariantURL(variant.Name), nil
+		}
 		return builder.GetImageURL(), nil
 	} else if config.GetImageBackend() == config.S3Backend {
 		builder := urlbuilders.NewS3ImageURLBuilder(obj)
+		if variant != nil {
+			return builder.GetVariantURL(variant.Name), nil
+		}
 		return builder.GetImageURL(), nil
 	}
 
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/manager/cron"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type jobRunResolver struct{ *Resolver }
+
+func (r *jobRunResolver) ID(ctx context.Context, obj *models.JobRun) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *jobRunResolver) Status(ctx context.Context, obj *models.JobRun) (models.JobStatusEnum, error) {
+	var ret models.JobStatusEnum
+	if !utils.ResolveEnumString(obj.Status, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *jobRunResolver) Progress(ctx context.Context, obj *models.JobRun) (*float64, error) {
+	// progress of running jobs is only held in memory
+	if obj.Status == models.JobStatusEnumRunning.String() {
+		return cron.RunProgress(obj.ID), nil
+	}
+
+	return resolveNullFloat64(obj.Progress), nil
+}
+
+func (r *jobRunResolver) Error(ctx context.Context, obj *models.JobRun) (*string, error) {
+	return resolveNullString(obj.Error), nil
+}
+
+func (r *jobRunResolver) TriggeredBy(ctx context.Context, obj *models.JobRun) (*models.User, error) {
+	if !obj.UserID.Valid {
+		return nil, nil
+	}
+
+	return r.getRepoFactory(ctx).User().Find(obj.UserID.UUID)
+}
+
+func (r *jobRunResolver) Started(ctx context.Context, obj *models.JobRun) (*time.Time, error) {
+	return &obj.StartedAt.Timestamp, nil
+}
+
+func (r *jobRunResolver) Finished(ctx context.Context, obj *models.JobRun) (*time.Time, error) {
+	if !obj.FinishedAt.Valid {
+		return nil, nil
+	}
+
+	return &obj.FinishedAt.Time, nil
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type notificationResolver struct{ *Resolver }
+
+func (r *notificationResolver) ID(ctx context.Context, obj *models.Notification) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *notificationResolver) Type(ctx context.Context, obj *models.Notification) (models.NotificationTypeEnum, error) {
+	var ret models.NotificationTypeEnum
+	if !utils.ResolveEnumString(obj.Type, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *notificationResolver) Edit(ctx context.Context, obj *models.Notification) (*models.Edit, error) {
+	return r.getRepoFactory(ctx).Edit().Find(obj.EditID)
+}
+
+func (r *notificationResolver) Created(ctx context.Context, obj *models.Notification) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
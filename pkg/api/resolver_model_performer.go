@@ -135,6 +135,15 @@ func (r *performerResolver) Piercings(ctx context.Context, obj *models.Performer
 	return dataloader.For(ctx).PerformerPiercingsByID.Load(obj.ID)
 }
 
+func (r *performerResolver) Relationships(ctx context.Context, obj *models.Performer) ([]*models.PerformerRelationship, error) {
+	relations, err := dataloader.For(ctx).PerformerRelationsByID.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolvePerformerRelationships(ctx, models.PerformerRelations(relations).ToInputs(obj.ID))
+}
+
 func (r *performerResolver) Images(ctx context.Context, obj *models.Performer) ([]*models.Image, error) {
 	imageIDs, err := dataloader.For(ctx).PerformerImageIDsByID.Load(obj.ID)
 	if err != nil {
@@ -174,3 +183,7 @@ func (r *performerResolver) Studios(ctx context.Context, obj *models.Performer)
 	sqb := r.getRepoFactory(ctx).Studio()
 	return sqb.CountByPerformer(obj.ID)
 }
+
+func (r *performerResolver) History(ctx context.Context, obj *models.Performer) ([]*models.Revision, error) {
+	return r.findHistory(ctx, models.TargetTypeEnumPerformer, obj.ID)
+}
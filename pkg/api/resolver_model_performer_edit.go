@@ -94,3 +94,41 @@ func (r *performerEditResolver) RemovedImages(ctx context.Context, obj *models.P
 	}
 	return images, nil
 }
+
+func (r *performerEditResolver) AddedRelationships(ctx context.Context, obj *models.PerformerEdit) ([]*models.PerformerRelationship, error) {
+	return resolvePerformerRelationships(ctx, obj.AddedRelationships)
+}
+
+func (r *performerEditResolver) RemovedRelationships(ctx context.Context, obj *models.PerformerEdit) ([]*models.PerformerRelationship, error) {
+	return resolvePerformerRelationships(ctx, obj.RemovedRelationships)
+}
+
+func resolvePerformerRelationships(ctx context.Context, inputs []*models.PerformerRelationshipInput) ([]*models.PerformerRelationship, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	var uuids []uuid.UUID
+	for _, input := range inputs {
+		performerID, _ := uuid.FromString(input.PerformerID)
+		uuids = append(uuids, performerID)
+	}
+	performers, errors := dataloader.For(ctx).PerformerByID.LoadAll(uuids)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret []*models.PerformerRelationship
+	for i, performer := range performers {
+		if performer == nil {
+			continue
+		}
+		ret = append(ret, &models.PerformerRelationship{
+			Performer: performer,
+			Type:      inputs[i].Type,
+		})
+	}
+	return ret, nil
+}
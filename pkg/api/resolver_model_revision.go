@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/revision"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type revisionResolver struct{ *Resolver }
+
+func (r *revisionResolver) ID(ctx context.Context, obj *models.Revision) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *revisionResolver) TargetType(ctx context.Context, obj *models.Revision) (models.TargetTypeEnum, error) {
+	var ret models.TargetTypeEnum
+	if !utils.ResolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *revisionResolver) TargetID(ctx context.Context, obj *models.Revision) (string, error) {
+	return obj.TargetID.String(), nil
+}
+
+func (r *revisionResolver) Operation(ctx context.Context, obj *models.Revision) (models.OperationEnum, error) {
+	var ret models.OperationEnum
+	if !utils.ResolveEnumString(obj.Operation, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *revisionResolver) Details(ctx context.Context, obj *models.Revision) (models.EditDetails, error) {
+	return obj.GetDetails()
+}
+
+func (r *revisionResolver) User(ctx context.Context, obj *models.Revision) (*models.User, error) {
+	if !obj.UserID.Valid {
+		return nil, nil
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return fac.User().Find(obj.UserID.UUID)
+}
+
+func (r *revisionResolver) Edit(ctx context.Context, obj *models.Revision) (*models.Edit, error) {
+	if !obj.EditID.Valid {
+		return nil, nil
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return fac.Edit().Find(obj.EditID.UUID)
+}
+
+func (r *revisionResolver) Created(ctx context.Context, obj *models.Revision) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+// recordRevision records a revision for a change made directly by a
+// mutation rather than by an applied edit.
+func recordRevision(ctx context.Context, fac models.Repo, targetType models.TargetTypeEnum, operation models.OperationEnum, targetID uuid.UUID) error {
+	event := revision.Event{
+		TargetType: targetType,
+		Operation:  operation,
+		TargetID:   targetID,
+	}
+	if currentUser := getCurrentUser(ctx); currentUser != nil {
+		event.UserID = &currentUser.ID
+	}
+
+	return revision.Record(fac, event)
+}
+
+func (r *Resolver) findHistory(ctx context.Context, targetType models.TargetTypeEnum, targetID uuid.UUID) ([]*models.Revision, error) {
+	fac := r.getRepoFactory(ctx)
+	return fac.Revision().FindByTarget(targetType, targetID)
+}
@@ -105,6 +105,10 @@ func (r *sceneResolver) Fingerprints(ctx context.Context, obj *models.Scene) ([]
 	return dataloader.For(ctx).SceneFingerprintsByID.Load(obj.ID)
 }
 
+func (r *sceneResolver) Markers(ctx context.Context, obj *models.Scene) ([]*models.SceneMarker, error) {
+	return dataloader.For(ctx).SceneMarkersByID.Load(obj.ID)
+}
+
 func (r *sceneResolver) Urls(ctx context.Context, obj *models.Scene) ([]*models.URL, error) {
 	return dataloader.For(ctx).SceneUrlsByID.Load(obj.ID)
 }
@@ -113,3 +117,7 @@ func (r *sceneResolver) Edits(ctx context.Context, obj *models.Scene) ([]*models
 	eqb := r.getRepoFactory(ctx).Edit()
 	return eqb.FindBySceneID(obj.ID)
 }
+
+func (r *sceneResolver) History(ctx context.Context, obj *models.Scene) ([]*models.Revision, error) {
+	return r.findHistory(ctx, models.TargetTypeEnumScene, obj.ID)
+}
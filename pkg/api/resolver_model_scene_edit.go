@@ -97,3 +97,44 @@ func (r *sceneEditResolver) AddedImages(ctx context.Context, obj *models.SceneEd
 func (r *sceneEditResolver) RemovedImages(ctx context.Context, obj *models.SceneEdit) ([]*models.Image, error) {
 	return imageList(ctx, obj.RemovedImages)
 }
+
+func (r *sceneEditResolver) markerList(ctx context.Context, markers []*models.SceneMarkerInput) ([]*models.SceneMarkerDetails, error) {
+	var ret []*models.SceneMarkerDetails
+	for _, m := range markers {
+		tagID, _ := uuid.FromString(m.TagID)
+		tag, err := dataloader.For(ctx).TagByID.Load(tagID)
+		if err != nil {
+			return nil, err
+		}
+
+		var performerIDs []uuid.UUID
+		for _, id := range m.PerformerIds {
+			performerID, _ := uuid.FromString(id)
+			performerIDs = append(performerIDs, performerID)
+		}
+		performers, errors := dataloader.For(ctx).PerformerByID.LoadAll(performerIDs)
+		for _, err := range errors {
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ret = append(ret, &models.SceneMarkerDetails{
+			Title:      m.Title,
+			Start:      m.Start,
+			End:        m.End,
+			Tag:        tag,
+			Performers: performers,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *sceneEditResolver) AddedMarkers(ctx context.Context, obj *models.SceneEdit) ([]*models.SceneMarkerDetails, error) {
+	return r.markerList(ctx, obj.AddedMarkers)
+}
+
+func (r *sceneEditResolver) RemovedMarkers(ctx context.Context, obj *models.SceneEdit) ([]*models.SceneMarkerDetails, error) {
+	return r.markerList(ctx, obj.RemovedMarkers)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/dataloader"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type sceneMarkerResolver struct{ *Resolver }
+
+func (r *sceneMarkerResolver) ID(ctx context.Context, obj *models.SceneMarker) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *sceneMarkerResolver) Title(ctx context.Context, obj *models.SceneMarker) (*string, error) {
+	return resolveNullString(obj.Title), nil
+}
+
+func (r *sceneMarkerResolver) Start(ctx context.Context, obj *models.SceneMarker) (int, error) {
+	return obj.StartSeconds, nil
+}
+
+func (r *sceneMarkerResolver) End(ctx context.Context, obj *models.SceneMarker) (*int, error) {
+	return resolveNullInt64(obj.EndSeconds)
+}
+
+func (r *sceneMarkerResolver) Tag(ctx context.Context, obj *models.SceneMarker) (*models.Tag, error) {
+	return dataloader.For(ctx).TagByID.Load(obj.TagID)
+}
+
+func (r *sceneMarkerResolver) Performers(ctx context.Context, obj *models.SceneMarker) ([]*models.Performer, error) {
+	performerIDs, err := dataloader.For(ctx).SceneMarkerPerformerIDsByID.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	performers, errors := dataloader.For(ctx).PerformerByID.LoadAll(performerIDs)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return performers, nil
+}
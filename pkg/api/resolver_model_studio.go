@@ -55,3 +55,7 @@ func (r *studioResolver) Images(ctx context.Context, obj *models.Studio) ([]*mod
 	}
 	return images, nil
 }
+
+func (r *studioResolver) History(ctx context.Context, obj *models.Studio) ([]*models.Revision, error) {
+	return r.findHistory(ctx, models.TargetTypeEnumStudio, obj.ID)
+}
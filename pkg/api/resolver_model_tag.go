@@ -40,3 +40,7 @@ func (r *tagResolver) Category(ctx context.Context, obj *models.Tag) (*models.Ta
 	}
 	return nil, nil
 }
+
+func (r *tagResolver) History(ctx context.Context, obj *models.Tag) ([]*models.Revision, error) {
+	return r.findHistory(ctx, models.TargetTypeEnumTag, obj.ID)
+}
@@ -5,8 +5,13 @@ import (
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
 )
 
+// defaultNotificationLimit is the number of notifications returned when no
+// limit is requested.
+const defaultNotificationLimit = 50
+
 type userResolver struct{ *Resolver }
 
 func (r *userResolver) ID(ctx context.Context, user *models.User) (string, error) {
@@ -41,6 +46,23 @@ func (r *userResolver) EditCount(ctx context.Context, obj *models.User) (*models
 	return qb.CountEditsByStatus(obj.ID)
 }
 
+func (r *userResolver) Reputation(ctx context.Context, obj *models.User) (*models.UserReputation, error) {
+	fac := r.getRepoFactory(ctx)
+	reputation, err := user.GetReputation(fac, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserReputation{
+		Score:         reputation.Score(),
+		Weight:        reputation.Weight(),
+		MatchingVotes: reputation.MatchingVotes,
+		OpposingVotes: reputation.OpposingVotes,
+		AcceptedEdits: reputation.AcceptedEdits,
+		RejectedEdits: reputation.RejectedEdits,
+	}, nil
+}
+
 func (r *userResolver) InvitedBy(ctx context.Context, user *models.User) (*models.User, error) {
 	invitedBy := user.InvitedByID
 	if invitedBy.Valid {
@@ -73,3 +95,58 @@ func (r *userResolver) ActiveInviteCodes(ctx context.Context, user *models.User)
 	}
 	return ret, nil
 }
+
+func (r *userResolver) APIKeys(ctx context.Context, user *models.User) ([]*models.APIKey, error) {
+	// only visible to admins and the user themself
+	if err := validateUserOrAdmin(ctx, user.ID); err != nil {
+		return nil, nil
+	}
+
+	return r.getRepoFactory(ctx).APIKey().FindByUserID(user.ID)
+}
+
+func (r *userResolver) Notifications(ctx context.Context, user *models.User, unreadOnly *bool, limit *int) ([]*models.Notification, error) {
+	// only visible to admins and the user themself
+	if err := validateUserOrAdmin(ctx, user.ID); err != nil {
+		return nil, nil
+	}
+
+	count := defaultNotificationLimit
+	if limit != nil && *limit > 0 {
+		count = *limit
+	}
+
+	return r.getRepoFactory(ctx).Notification().FindByUser(user.ID, unreadOnly != nil && *unreadOnly, count)
+}
+
+func (r *userResolver) UnreadNotificationCount(ctx context.Context, user *models.User) (*int, error) {
+	// only visible to admins and the user themself
+	if err := validateUserOrAdmin(ctx, user.ID); err != nil {
+		return nil, nil
+	}
+
+	count, err := r.getRepoFactory(ctx).Notification().CountUnread(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &count, nil
+}
+
+func (r *userResolver) Watchlist(ctx context.Context, user *models.User) ([]*models.Watch, error) {
+	// only visible to admins and the user themself
+	if err := validateUserOrAdmin(ctx, user.ID); err != nil {
+		return nil, nil
+	}
+
+	return r.getRepoFactory(ctx).Watch().FindByUser(user.ID)
+}
+
+func (r *userResolver) TotpEnabled(ctx context.Context, user *models.User) (*bool, error) {
+	// only visible to admins and the user themself
+	if err := validateUserOrAdmin(ctx, user.ID); err != nil {
+		return nil, nil
+	}
+
+	return &user.TOTPEnabled, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type watchResolver struct{ *Resolver }
+
+func (r *watchResolver) TargetType(ctx context.Context, obj *models.Watch) (models.TargetTypeEnum, error) {
+	var ret models.TargetTypeEnum
+	if !utils.ResolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *watchResolver) Target(ctx context.Context, obj *models.Watch) (models.EditTarget, error) {
+	var targetType models.TargetTypeEnum
+	utils.ResolveEnumString(obj.TargetType, &targetType)
+	return findEditTarget(r.getRepoFactory(ctx), targetType, obj.TargetID)
+}
+
+func (r *watchResolver) Created(ctx context.Context, obj *models.Watch) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+// findEditTarget returns the entity of the provided type, or nil if it does
+// not exist.
+func findEditTarget(fac models.Repo, targetType models.TargetTypeEnum, id uuid.UUID) (models.EditTarget, error) {
+	switch targetType {
+	case models.TargetTypeEnumPerformer:
+		performer, err := fac.Performer().Find(id)
+		if err != nil || performer == nil {
+			return nil, err
+		}
+		return performer, nil
+	case models.TargetTypeEnumStudio:
+		studio, err := fac.Studio().Find(id)
+		if err != nil || studio == nil {
+			return nil, err
+		}
+		return studio, nil
+	case models.TargetTypeEnumTag:
+		tag, err := fac.Tag().Find(id)
+		if err != nil || tag == nil {
+			return nil, err
+		}
+		return tag, nil
+	case models.TargetTypeEnumScene:
+		scene, err := fac.Scene().Find(id)
+		if err != nil || scene == nil {
+			return nil, err
+		}
+		return scene, nil
+	}
+
+	return nil, nil
+}
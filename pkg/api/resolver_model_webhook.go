@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+type webhookResolver struct{ *Resolver }
+
+func (r *webhookResolver) ID(ctx context.Context, obj *models.Webhook) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *webhookResolver) TargetTypes(ctx context.Context, obj *models.Webhook) ([]models.TargetTypeEnum, error) {
+	targetTypes, err := r.getRepoFactory(ctx).Webhook().GetTargetTypes(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetTypes.ToTargetTypes(), nil
+}
+
+func (r *webhookResolver) Operations(ctx context.Context, obj *models.Webhook) ([]models.OperationEnum, error) {
+	operations, err := r.getRepoFactory(ctx).Webhook().GetOperations(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return operations.ToOperations(), nil
+}
+
+func (r *webhookResolver) Created(ctx context.Context, obj *models.Webhook) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *webhookResolver) Updated(ctx context.Context, obj *models.Webhook) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
+
+type webhookDeliveryResolver struct{ *Resolver }
+
+func (r *webhookDeliveryResolver) ID(ctx context.Context, obj *models.WebhookDelivery) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *webhookDeliveryResolver) Webhook(ctx context.Context, obj *models.WebhookDelivery) (*models.Webhook, error) {
+	return r.getRepoFactory(ctx).Webhook().Find(obj.WebhookID)
+}
+
+func (r *webhookDeliveryResolver) TargetType(ctx context.Context, obj *models.WebhookDelivery) (models.TargetTypeEnum, error) {
+	var ret models.TargetTypeEnum
+	if !utils.ResolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *webhookDeliveryResolver) Operation(ctx context.Context, obj *models.WebhookDelivery) (models.OperationEnum, error) {
+	var ret models.OperationEnum
+	if !utils.ResolveEnumString(obj.Operation, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *webhookDeliveryResolver) TargetID(ctx context.Context, obj *models.WebhookDelivery) (string, error) {
+	return obj.TargetID.String(), nil
+}
+
+func (r *webhookDeliveryResolver) Edit(ctx context.Context, obj *models.WebhookDelivery) (*models.Edit, error) {
+	if !obj.EditID.Valid {
+		return nil, nil
+	}
+
+	return r.getRepoFactory(ctx).Edit().Find(obj.EditID.UUID)
+}
+
+func (r *webhookDeliveryResolver) Payload(ctx context.Context, obj *models.WebhookDelivery) (string, error) {
+	return obj.Payload.String(), nil
+}
+
+func (r *webhookDeliveryResolver) Status(ctx context.Context, obj *models.WebhookDelivery) (models.WebhookDeliveryStatusEnum, error) {
+	var ret models.WebhookDeliveryStatusEnum
+	if !utils.ResolveEnumString(obj.Status, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *webhookDeliveryResolver) ResponseCode(ctx context.Context, obj *models.WebhookDelivery) (*int, error) {
+	return resolveNullInt64(obj.ResponseCode)
+}
+
+func (r *webhookDeliveryResolver) Error(ctx context.Context, obj *models.WebhookDelivery) (*string, error) {
+	return resolveNullString(obj.Error), nil
+}
+
+func (r *webhookDeliveryResolver) NextAttempt(ctx context.Context, obj *models.WebhookDelivery) (*time.Time, error) {
+	return &obj.NextAttemptAt.Timestamp, nil
+}
+
+func (r *webhookDeliveryResolver) LastAttempt(ctx context.Context, obj *models.WebhookDelivery) (*time.Time, error) {
+	if !obj.LastAttemptAt.Valid {
+		return nil, nil
+	}
+
+	return &obj.LastAttemptAt.Time, nil
+}
+
+func (r *webhookDeliveryResolver) Created(ctx context.Context, obj *models.WebhookDelivery) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
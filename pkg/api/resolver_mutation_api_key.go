@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func (r *mutationResolver) APIKeyCreate(ctx context.Context, input models.APIKeyCreateInput) (*models.APIKeyCreateResult, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, user.ErrUnauthorized
+	}
+
+	if err := validateUnscoped(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret *models.APIKeyCreateResult
+	err := fac.WithTxn(func() error {
+		key, value, err := user.CreateAPIKey(fac, currentUser.ID, input)
+		if err != nil {
+			return err
+		}
+
+		ret = &models.APIKeyCreateResult{
+			APIKey: key,
+			Key:    value,
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) APIKeyRevoke(ctx context.Context, input models.APIKeyRevokeInput) (bool, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, user.ErrUnauthorized
+	}
+
+	if err := validateUnscoped(ctx); err != nil {
+		return false, err
+	}
+
+	keyID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err = fac.WithTxn(func() error {
+		qb := fac.APIKey()
+		key, err := qb.Find(keyID)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return errors.New("api key not found")
+		}
+
+		// revoking another user's api key must be admin
+		if err := validateUserOrAdmin(ctx, key.UserID); err != nil {
+			return err
+		}
+
+		return qb.Destroy(keyID)
+	})
+
+	return err == nil, err
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stashapp/stash-box/pkg/manager/edit"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/notification"
 	"github.com/stashapp/stash-box/pkg/user"
 )
 
@@ -15,7 +16,12 @@ func (r *mutationResolver) SceneEdit(ctx context.Context, input models.SceneEdit
 		return nil, err
 	}
 
-	// TODO - handle modification of existing edit
+	if input.Edit.EditID != nil {
+		fac := r.getRepoFactory(ctx)
+		return edit.AmendEdit(fac, getCurrentUser(ctx), models.TargetTypeEnumScene, input.Edit, func(e *models.Edit) error {
+			return edit.Scene(fac, e).Edit(input, wasFieldIncludedFunc(ctx))
+		})
+	}
 
 	UUID, err := uuid.NewV4()
 	if err != nil {
@@ -44,6 +50,10 @@ func (r *mutationResolver) SceneEdit(ctx context.Context, input models.SceneEdit
 			return err
 		}
 
+		if err := notification.OnEditCreated(fac, newEdit); err != nil {
+			return err
+		}
+
 		return p.CreateComment(currentUser, input.Edit.Comment)
 	})
 
@@ -58,7 +68,12 @@ func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEd
 		return nil, err
 	}
 
-	// TODO - handle modification of existing edit
+	if input.Edit.EditID != nil {
+		fac := r.getRepoFactory(ctx)
+		return edit.AmendEdit(fac, getCurrentUser(ctx), models.TargetTypeEnumStudio, input.Edit, func(e *models.Edit) error {
+			return edit.Studio(fac, e).Edit(input, wasFieldIncludedFunc(ctx))
+		})
+	}
 
 	UUID, err := uuid.NewV4()
 	if err != nil {
@@ -87,6 +102,10 @@ func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEd
 			return err
 		}
 
+		if err := notification.OnEditCreated(fac, newEdit); err != nil {
+			return err
+		}
+
 		return p.CreateComment(currentUser, input.Edit.Comment)
 	})
 
@@ -102,7 +121,12 @@ func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInpu
 		return nil, err
 	}
 
-	// TODO - handle modification of existing edit
+	if input.Edit.EditID != nil {
+		fac := r.getRepoFactory(ctx)
+		return edit.AmendEdit(fac, getCurrentUser(ctx), models.TargetTypeEnumTag, input.Edit, func(e *models.Edit) error {
+			return edit.Tag(fac, e).Edit(input, wasFieldIncludedFunc(ctx))
+		})
+	}
 
 	UUID, err := uuid.NewV4()
 	if err != nil {
@@ -131,6 +155,10 @@ func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInpu
 			return err
 		}
 
+		if err := notification.OnEditCreated(fac, newEdit); err != nil {
+			return err
+		}
+
 		return p.CreateComment(currentUser, input.Edit.Comment)
 	})
 
@@ -146,7 +174,12 @@ func (r *mutationResolver) PerformerEdit(ctx context.Context, input models.Perfo
 		return nil, err
 	}
 
-	// TODO - handle modification of existing edit
+	if input.Edit.EditID != nil {
+		fac := r.getRepoFactory(ctx)
+		return edit.AmendEdit(fac, getCurrentUser(ctx), models.TargetTypeEnumPerformer, input.Edit, func(e *models.Edit) error {
+			return edit.Performer(fac, e).Edit(input, wasFieldIncludedFunc(ctx))
+		})
+	}
 
 	UUID, err := uuid.NewV4()
 	if err != nil {
@@ -173,6 +206,10 @@ func (r *mutationResolver) PerformerEdit(ctx context.Context, input models.Perfo
 			return err
 		}
 
+		if err := notification.OnEditCreated(fac, newEdit); err != nil {
+			return err
+		}
+
 		return p.CreateComment(currentUser, input.Edit.Comment)
 	})
 
@@ -207,6 +244,12 @@ func (r *mutationResolver) EditVote(ctx context.Context, input models.EditVoteIn
 			return user.ErrUnauthorized
 		}
 
+		if input.Vote == models.VoteTypeEnumImmediateAccept || input.Vote == models.VoteTypeEnumImmediateReject {
+			if err := validateImmediateVote(ctx, voteEdit); err != nil {
+				return err
+			}
+		}
+
 		vote := models.NewEditVote(currentUser, voteEdit, input.Vote)
 		if err := eqb.CreateVote(*vote); err != nil {
 			return err
@@ -217,6 +260,19 @@ func (r *mutationResolver) EditVote(ctx context.Context, input models.EditVoteIn
 			return err
 		}
 
+		edit.PublishEvent(fac, edit.EventVoted, voteEdit)
+		if err := notification.OnEditVote(fac, voteEdit, vote); err != nil {
+			return err
+		}
+
+		if input.Vote == models.VoteTypeEnumImmediateAccept {
+			voteEdit, err = edit.ApplyEdit(fac, editID, true)
+			return err
+		} else if input.Vote == models.VoteTypeEnumImmediateReject {
+			voteEdit, err = edit.CloseEdit(fac, editID, models.VoteStatusEnumImmediateRejected)
+			return err
+		}
+
 		result, err := edit.ResolveVotingThreshold(fac, voteEdit)
 		if result == models.VoteStatusEnumAccepted {
 			voteEdit, err = edit.ApplyEdit(fac, editID, false)
@@ -242,7 +298,7 @@ func (r *mutationResolver) EditComment(ctx context.Context, input models.EditCom
 	}
 	fac := r.getRepoFactory(ctx)
 	currentUser := getCurrentUser(ctx)
-	var edit *models.Edit
+	var e *models.Edit
 	err := fac.WithTxn(func() error {
 		eqb := fac.Edit()
 
@@ -250,21 +306,26 @@ func (r *mutationResolver) EditComment(ctx context.Context, input models.EditCom
 		if err != nil {
 			return err
 		}
-		edit, err = eqb.Find(editID)
+		e, err = eqb.Find(editID)
 		if err != nil {
 			return err
 		}
 
 		commentID, _ := uuid.NewV4()
-		comment := models.NewEditComment(commentID, currentUser, edit, input.Comment)
-		return eqb.CreateComment(*comment)
+		comment := models.NewEditComment(commentID, currentUser, e, input.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			return err
+		}
+
+		edit.PublishEvent(fac, edit.EventCommented, e)
+		return notification.OnEditComment(fac, e, comment)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return edit, nil
+	return e, nil
 }
 
 func (r *mutationResolver) CancelEdit(ctx context.Context, input models.CancelEditInput) (*models.Edit, error) {
@@ -299,3 +360,17 @@ func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEdit
 
 	return edit.ApplyEdit(fac, editID, true)
 }
+
+func (r *mutationResolver) RevertEdit(ctx context.Context, input models.RevertEditInput) (*models.Edit, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	editID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	fac := r.getRepoFactory(ctx)
+
+	return edit.RevertEdit(fac, getCurrentUser(ctx), editID, input.Comment)
+}
@@ -2,8 +2,14 @@ package api
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gofrs/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 
 	"github.com/stashapp/stash-box/pkg/image"
+	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -20,8 +26,11 @@ func (r *mutationResolver) ImageCreate(ctx context.Context, input models.ImageCr
 		imageService := image.GetService(qb)
 		var txnErr error
 		ret, txnErr = imageService.Create(input)
+		if txnErr != nil {
+			return txnErr
+		}
 
-		return txnErr
+		return warnSimilarImages(ctx, qb, ret, input)
 	})
 
 	if err != nil {
@@ -31,6 +40,52 @@ func (r *mutationResolver) ImageCreate(ctx context.Context, input models.ImageCr
 	return ret, nil
 }
 
+// warnSimilarImages adds a warning to the response for each image of the
+// performer or studio the image was uploaded for that is similar to it.
+func warnSimilarImages(ctx context.Context, qb models.ImageRepo, img *models.Image, input models.ImageCreateInput) error {
+	if !img.PHash.Valid || (input.PerformerID == nil && input.StudioID == nil) {
+		return nil
+	}
+
+	var similar models.Images
+	if input.PerformerID != nil {
+		performerID, err := uuid.FromString(*input.PerformerID)
+		if err != nil {
+			return err
+		}
+		images, err := qb.FindSimilar(img.PHash.Int64, config.GetPHashDistance(), &performerID, nil, maxSimilarImages)
+		if err != nil {
+			return err
+		}
+		similar = append(similar, images...)
+	}
+	if input.StudioID != nil {
+		studioID, err := uuid.FromString(*input.StudioID)
+		if err != nil {
+			return err
+		}
+		images, err := qb.FindSimilar(img.PHash.Int64, config.GetPHashDistance(), nil, &studioID, maxSimilarImages)
+		if err != nil {
+			return err
+		}
+		similar = append(similar, images...)
+	}
+
+	for _, s := range similarImages(img, similar) {
+		graphql.AddError(ctx, &gqlerror.Error{
+			Path:    graphql.GetPath(ctx),
+			Message: fmt.Sprintf("image is similar to existing image %s", s.Image.ID),
+			Extensions: map[string]interface{}{
+				"code":     "SIMILAR_IMAGE",
+				"image_id": s.Image.ID.String(),
+				"distance": s.Distance,
+			},
+		})
+	}
+
+	return nil
+}
+
 func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageDestroyInput) (bool, error) {
 	if err := validateModify(ctx); err != nil {
 		return false, err
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/cron"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *mutationResolver) JobTrigger(ctx context.Context, name string) (*models.JobRun, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	return cron.Trigger(name, &currentUser.ID)
+}
+
+func (r *mutationResolver) JobCancel(ctx context.Context, id string) (bool, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	runID, err := uuid.FromString(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := cron.Cancel(runID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
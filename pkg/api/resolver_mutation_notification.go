@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, input models.MarkNotificationsReadInput) (bool, error) {
+	if err := validateRead(ctx); err != nil {
+		return false, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, user.ErrUnauthorized
+	}
+
+	var ids []uuid.UUID
+	if input.Ids != nil {
+		ids = []uuid.UUID{}
+		for _, id := range input.Ids {
+			notificationID, err := uuid.FromString(id)
+			if err != nil {
+				return false, err
+			}
+			ids = append(ids, notificationID)
+		}
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err := fac.WithTxn(func() error {
+		return fac.Notification().MarkRead(currentUser.ID, ids)
+	})
+
+	return err == nil, err
+}
@@ -71,8 +71,18 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 
 		// Save the images
 		performerImages := models.CreatePerformerImages(performer.ID, input.ImageIds)
+		if err := jqb.CreatePerformersImages(performerImages); err != nil {
+			return err
+		}
 
-		return jqb.CreatePerformersImages(performerImages)
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumPerformer, models.OperationEnumCreate, performer.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumPerformer, models.OperationEnumCreate, performer.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumPerformer, models.OperationEnumCreate, performer.ID)
 	})
 
 	// Commit
@@ -165,7 +175,14 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 			}
 		}
 
-		return nil
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumPerformer, models.OperationEnumModify, performer.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumPerformer, models.OperationEnumModify, performer.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumPerformer, models.OperationEnumModify, performer.ID)
 	})
 
 	// Commit
@@ -200,6 +217,14 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input models.Pe
 			return err
 		}
 
+		// record the final state before it is removed
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumPerformer, models.OperationEnumDestroy, performerID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumPerformer, models.OperationEnumDestroy, performerID, nil); err != nil {
+			return err
+		}
+
 		if err = qb.Destroy(performerID); err != nil {
 			return err
 		}
@@ -213,7 +238,7 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input models.Pe
 			}
 		}
 
-		return nil
+		return enqueueWebhooks(fac, models.TargetTypeEnumPerformer, models.OperationEnumDestroy, performerID)
 	})
 
 	if err != nil {
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/scene"
 )
@@ -18,7 +20,18 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 	if err := fac.WithTxn(func() error {
 		var err error
 		s, err = scene.Create(ctx, fac, input)
-		return err
+		if err != nil {
+			return err
+		}
+
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumScene, models.OperationEnumCreate, s.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumScene, models.OperationEnumCreate, s.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumScene, models.OperationEnumCreate, s.ID)
 	}); err != nil {
 		return nil, err
 	}
@@ -37,7 +50,18 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 	if err := fac.WithTxn(func() error {
 		var err error
 		s, err = scene.Update(ctx, fac, input)
-		return err
+		if err != nil {
+			return err
+		}
+
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumScene, models.OperationEnumModify, s.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumScene, models.OperationEnumModify, s.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumScene, models.OperationEnumModify, s.ID)
 	}); err != nil {
 		return nil, err
 	}
@@ -50,13 +74,30 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 		return false, err
 	}
 
+	sceneID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
 	fac := r.getRepoFactory(ctx)
 
 	var ret bool
 	if err := fac.WithTxn(func() error {
+		// record the final state before it is removed
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumScene, models.OperationEnumDestroy, sceneID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumScene, models.OperationEnumDestroy, sceneID, nil); err != nil {
+			return err
+		}
+
 		var err error
 		ret, err = scene.Destroy(fac, input)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumScene, models.OperationEnumDestroy, sceneID)
 	}); err != nil {
 		return false, err
 	}
@@ -65,6 +106,10 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 }
 
 func (r *mutationResolver) SubmitFingerprint(ctx context.Context, input models.FingerprintSubmission) (bool, error) {
+	if err := validateScope(ctx, models.APIKeyScopeEnumSubmitFingerprints); err != nil {
+		return false, err
+	}
+
 	fac := r.getRepoFactory(ctx)
 	var ret bool
 	if err := fac.WithTxn(func() error {
@@ -58,8 +58,18 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 
 		// Save the images
 		studioImages := models.CreateStudioImages(studio.ID, input.ImageIds)
+		if err := jqb.CreateStudiosImages(studioImages); err != nil {
+			return err
+		}
 
-		return jqb.CreateStudiosImages(studioImages)
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumStudio, models.OperationEnumCreate, studio.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumStudio, models.OperationEnumCreate, studio.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumStudio, models.OperationEnumCreate, studio.ID)
 	})
 
 	if err != nil {
@@ -131,7 +141,14 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 			}
 		}
 
-		return nil
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumStudio, models.OperationEnumModify, studio.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumStudio, models.OperationEnumModify, studio.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumStudio, models.OperationEnumModify, studio.ID)
 	})
 
 	if err != nil {
@@ -162,6 +179,14 @@ func (r *mutationResolver) StudioDestroy(ctx context.Context, input models.Studi
 			return err
 		}
 
+		// record the final state before it is removed
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumStudio, models.OperationEnumDestroy, studioID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumStudio, models.OperationEnumDestroy, studioID, nil); err != nil {
+			return err
+		}
+
 		// references have on delete cascade, so shouldn't be necessary
 		// to remove them explicitly
 		if err = qb.Destroy(studioID); err != nil {
@@ -177,7 +202,7 @@ func (r *mutationResolver) StudioDestroy(ctx context.Context, input models.Studi
 			}
 		}
 
-		return nil
+		return enqueueWebhooks(fac, models.TargetTypeEnumStudio, models.OperationEnumDestroy, studioID)
 	})
 
 	if err != nil {
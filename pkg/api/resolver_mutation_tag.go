@@ -47,7 +47,18 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input models.TagCreate
 
 		// Save the aliases
 		tagAliases := models.CreateTagAliases(tag.ID, input.Aliases)
-		return qb.CreateAliases(tagAliases)
+		if err := qb.CreateAliases(tagAliases); err != nil {
+			return err
+		}
+
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumTag, models.OperationEnumCreate, tag.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumTag, models.OperationEnumCreate, tag.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumTag, models.OperationEnumCreate, tag.ID)
 	})
 
 	if err != nil {
@@ -88,7 +99,18 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 		// Save the aliases
 		// TODO - only do this if provided
 		tagAliases := models.CreateTagAliases(tag.ID, input.Aliases)
-		return qb.UpdateAliases(tag.ID, tagAliases)
+		if err := qb.UpdateAliases(tag.ID, tagAliases); err != nil {
+			return err
+		}
+
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumTag, models.OperationEnumModify, tag.ID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumTag, models.OperationEnumModify, tag.ID, nil); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumTag, models.OperationEnumModify, tag.ID)
 	})
 
 	if err != nil {
@@ -114,7 +136,20 @@ func (r *mutationResolver) TagDestroy(ctx context.Context, input models.TagDestr
 		if err != nil {
 			return err
 		}
-		return qb.Destroy(tagID)
+
+		// record the final state before it is removed
+		if err := recordRevision(ctx, fac, models.TargetTypeEnumTag, models.OperationEnumDestroy, tagID); err != nil {
+			return err
+		}
+		if err := fac.Change().Log(models.TargetTypeEnumTag, models.OperationEnumDestroy, tagID, nil); err != nil {
+			return err
+		}
+
+		if err := qb.Destroy(tagID); err != nil {
+			return err
+		}
+
+		return enqueueWebhooks(fac, models.TargetTypeEnumTag, models.OperationEnumDestroy, tagID)
 	})
 
 	if err != nil {
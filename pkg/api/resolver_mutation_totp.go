@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+// validateAccountUser returns the current user if they may manage their
+// account.
+func validateAccountUser(ctx context.Context) (*models.User, error) {
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, user.ErrUnauthorized
+	}
+
+	if err := validateUnscoped(ctx); err != nil {
+		return nil, err
+	}
+
+	return currentUser, nil
+}
+
+func (r *mutationResolver) EnrollTotp(ctx context.Context) (*models.TOTPEnrollResult, error) {
+	currentUser, err := validateAccountUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret models.TOTPEnrollResult
+	err = fac.WithTxn(func() error {
+		var txnErr error
+		ret.Secret, ret.URI, txnErr = user.EnrollTOTP(fac, currentUser.ID)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (r *mutationResolver) VerifyTotp(ctx context.Context, input models.TOTPCodeInput) ([]string, error) {
+	currentUser, err := validateAccountUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret []string
+	err = fac.WithTxn(func() error {
+		var txnErr error
+		ret, txnErr = user.VerifyTOTP(fac, currentUser.ID, input.Code)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Userf(currentUser.Name, "VerifyTOTP", "%s", currentUser.ID.String())
+
+	return ret, nil
+}
+
+func (r *mutationResolver) DisableTotp(ctx context.Context, input models.TOTPDisableInput) (bool, error) {
+	currentUser, err := validateAccountUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	userID := currentUser.ID
+	requireCode := true
+	if input.UserID != nil && *input.UserID != currentUser.ID.String() {
+		// disabling another user's two-factor authentication
+		// must be admin
+		if err := validateAdmin(ctx); err != nil {
+			return false, err
+		}
+
+		userID, err = uuid.FromString(*input.UserID)
+		if err != nil {
+			return false, err
+		}
+		requireCode = false
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err = fac.WithTxn(func() error {
+		return user.DisableTOTP(fac, userID, input.Code, requireCode)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	logger.Userf(currentUser.Name, "DisableTOTP", "%s", userID.String())
+
+	return true, nil
+}
+
+func (r *mutationResolver) RegenerateRecoveryCodes(ctx context.Context, input models.TOTPCodeInput) ([]string, error) {
+	currentUser, err := validateAccountUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret []string
+	err = fac.WithTxn(func() error {
+		var txnErr error
+		ret, txnErr = user.RegenerateRecoveryCodes(fac, currentUser.ID, input.Code)
+		return txnErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
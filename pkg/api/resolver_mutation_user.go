@@ -133,6 +133,10 @@ func (r *mutationResolver) RegenerateAPIKey(ctx context.Context, userID *string)
 		return "", user.ErrUnauthorized
 	}
 
+	if err := validateUnscoped(ctx); err != nil {
+		return "", err
+	}
+
 	if userID != nil {
 		if currentUser.ID.String() != *userID {
 			// changing another user api key
@@ -201,6 +205,10 @@ func (r *mutationResolver) ChangePassword(ctx context.Context, input models.User
 		return false, user.ErrUnauthorized
 	}
 
+	if err := validateUnscoped(ctx); err != nil {
+		return false, err
+	}
+
 	if input.ExistingPassword == nil {
 		return false, user.ErrCurrentPasswordIncorrect
 	}
@@ -263,6 +271,10 @@ func (r *mutationResolver) ActivateNewUser(ctx context.Context, input models.Act
 }
 
 func (r *mutationResolver) GenerateInviteCode(ctx context.Context) (string, error) {
+	if err := validateUnscoped(ctx); err != nil {
+		return "", err
+	}
+
 	// INVITE role allows generating invite keys without tokens
 	requireToken := true
 	if err := validateInvite(ctx); err == nil {
@@ -296,6 +308,10 @@ func (r *mutationResolver) GenerateInviteCode(ctx context.Context) (string, erro
 }
 
 func (r *mutationResolver) RescindInviteCode(ctx context.Context, code string) (bool, error) {
+	if err := validateUnscoped(ctx); err != nil {
+		return false, err
+	}
+
 	// INVITE role allows generating invite keys without tokens
 	requireToken := true
 	if err := validateInvite(ctx); err == nil {
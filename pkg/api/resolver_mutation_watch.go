@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func (r *mutationResolver) Watch(ctx context.Context, input models.WatchInput) (bool, error) {
+	if err := validateRead(ctx); err != nil {
+		return false, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, user.ErrUnauthorized
+	}
+
+	targetID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err = fac.WithTxn(func() error {
+		target, err := findEditTarget(fac, input.TargetType, targetID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return errors.New("watched entity not found")
+		}
+
+		return fac.Watch().Create(*models.NewWatch(currentUser.ID, input.TargetType, targetID))
+	})
+
+	return err == nil, err
+}
+
+func (r *mutationResolver) Unwatch(ctx context.Context, input models.WatchInput) (bool, error) {
+	if err := validateRead(ctx); err != nil {
+		return false, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return false, user.ErrUnauthorized
+	}
+
+	targetID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err = fac.WithTxn(func() error {
+		return fac.Watch().Destroy(currentUser.ID, input.TargetType, targetID)
+	})
+
+	return err == nil, err
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/webhook"
+)
+
+func (r *mutationResolver) WebhookCreate(ctx context.Context, input models.WebhookCreateInput) (*models.Webhook, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := webhook.ValidateURL(input.URL); err != nil {
+		return nil, err
+	}
+	if input.Secret == "" {
+		return nil, errors.New("webhook secret is required")
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate a new webhook from the input
+	currentTime := time.Now()
+	newWebhook := models.Webhook{
+		ID:        UUID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	newWebhook.CopyFromCreateInput(input)
+
+	fac := r.getRepoFactory(ctx)
+	var ret *models.Webhook
+	err = fac.WithTxn(func() error {
+		qb := fac.Webhook()
+		ret, err = qb.Create(newWebhook)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.CreateTargetTypes(models.CreateWebhookTargetTypes(ret.ID, input.TargetTypes)); err != nil {
+			return err
+		}
+
+		return qb.CreateOperations(models.CreateWebhookOperations(ret.ID, input.Operations))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) WebhookUpdate(ctx context.Context, input models.WebhookUpdateInput) (*models.Webhook, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if err := webhook.ValidateURL(*input.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret *models.Webhook
+	err := fac.WithTxn(func() error {
+		qb := fac.Webhook()
+
+		// get the existing webhook and modify it
+		webhookID, _ := uuid.FromString(input.ID)
+		updatedWebhook, err := qb.Find(webhookID)
+		if err != nil {
+			return err
+		}
+
+		if updatedWebhook == nil {
+			return models.NotFoundError(webhookID)
+		}
+
+		updatedWebhook.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		// Populate webhook from the input
+		updatedWebhook.CopyFromUpdateInput(input)
+
+		ret, err = qb.Update(*updatedWebhook)
+		if err != nil {
+			return err
+		}
+
+		// filters are only replaced if provided
+		if input.TargetTypes != nil {
+			if err := qb.UpdateTargetTypes(ret.ID, models.CreateWebhookTargetTypes(ret.ID, input.TargetTypes)); err != nil {
+				return err
+			}
+		}
+
+		if input.Operations != nil {
+			if err := qb.UpdateOperations(ret.ID, models.CreateWebhookOperations(ret.ID, input.Operations)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) WebhookDestroy(ctx context.Context, input models.WebhookDestroyInput) (bool, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	webhookID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	err = fac.WithTxn(func() error {
+		// deliveries are removed by cascade
+		return fac.Webhook().Destroy(webhookID)
+	})
+
+	return err == nil, err
+}
+
+func (r *mutationResolver) WebhookRedeliver(ctx context.Context, input models.WebhookRedeliverInput) (*models.WebhookDelivery, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	deliveryID, err := uuid.FromString(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	var ret *models.WebhookDelivery
+	err = fac.WithTxn(func() error {
+		qb := fac.Webhook()
+		delivery, err := qb.FindDelivery(deliveryID)
+		if err != nil {
+			return err
+		}
+
+		if delivery == nil {
+			return models.NotFoundError(deliveryID)
+		}
+
+		delivery.Reset()
+		ret, err = qb.UpdateDelivery(*delivery)
+		if err != nil {
+			return err
+		}
+
+		webhook.Trigger(fac)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// enqueueWebhooks queues webhook deliveries for a change made directly by
+// a mutation rather than by an applied edit.
+func enqueueWebhooks(fac models.Repo, targetType models.TargetTypeEnum, operation models.OperationEnum, targetID uuid.UUID) error {
+	return webhook.Enqueue(fac, webhook.Event{
+		TargetType: targetType,
+		Operation:  operation,
+		TargetID:   targetID,
+	})
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+)
+
+func (r *queryResolver) ChangesSince(ctx context.Context, cursor *string, since *time.Time, limit *int) (*models.ChangesSinceResultType, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	var afterID int64
+	if cursor != nil && *cursor != "" {
+		var err error
+		afterID, err = strconv.ParseInt(*cursor, 10, 64)
+		if err != nil || afterID < 0 {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+
+	count := defaultChangesLimit
+	if limit != nil && *limit > 0 {
+		count = *limit
+	}
+	if count > maxChangesLimit {
+		count = maxChangesLimit
+	}
+
+	fac := r.getRepoFactory(ctx)
+	changes, err := fac.Change().FindAfter(afterID, since, count)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		afterID = changes[len(changes)-1].ID
+	}
+
+	return &models.ChangesSinceResultType{
+		Changes: changes,
+		Cursor:  strconv.FormatInt(afterID, 10),
+		HasMore: len(changes) == count,
+	}, nil
+}
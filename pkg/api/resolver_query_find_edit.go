@@ -32,3 +32,31 @@ func (r *queryResolver) QueryEdits(ctx context.Context, editFilter *models.EditF
 		Count: count,
 	}, err
 }
+
+func (r *queryResolver) EditsConnection(ctx context.Context, editFilter *models.EditFilterType, filter *models.CursorQuerySpec) (*models.EditConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Edit()
+
+	edits, page, err := qb.QueryPage(editFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []*models.EditEdge{}
+	for i, edit := range edits {
+		edges = append(edges, &models.EditEdge{
+			Cursor: page.Cursors[i],
+			Node:   edit,
+		})
+	}
+
+	return &models.EditConnection{
+		Edges:      edges,
+		PageInfo:   page.PageInfo(),
+		TotalCount: page.Count,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/image"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const maxSimilarImages = 100
+
+func (r *queryResolver) FindSimilarImages(ctx context.Context, imageID string, distance *int) ([]*models.SimilarImage, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	d := config.GetPHashDistance()
+	if distance != nil {
+		d = *distance
+	}
+	if d < 0 || d > 16 {
+		return nil, errors.New("distance must be between 0 and 16")
+	}
+
+	id, err := uuid.FromString(imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Image()
+
+	img, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil || !img.PHash.Valid {
+		return nil, nil
+	}
+
+	// the image itself is always matched
+	similar, err := qb.FindSimilar(img.PHash.Int64, d, nil, nil, maxSimilarImages+1)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := similarImages(img, similar)
+	if len(ret) > maxSimilarImages {
+		ret = ret[:maxSimilarImages]
+	}
+	return ret, nil
+}
+
+// similarImages returns the images other than img, with their distance from
+// it, closest first.
+func similarImages(img *models.Image, images models.Images) []*models.SimilarImage {
+	var ret []*models.SimilarImage
+	for _, i := range images {
+		if i.ID == img.ID {
+			continue
+		}
+		ret = append(ret, &models.SimilarImage{
+			Image:    i,
+			Distance: image.HashDistance(uint64(img.PHash.Int64), uint64(i.PHash.Int64)),
+		})
+	}
+
+	sort.SliceStable(ret, func(a, b int) bool {
+		return ret[a].Distance < ret[b].Distance
+	})
+	return ret
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/cron"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const (
+	defaultJobRunsLimit = 25
+	maxJobRunsLimit     = 100
+)
+
+func (r *queryResolver) QueryJobs(ctx context.Context) ([]*models.Job, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := r.getRepoFactory(ctx).Job()
+
+	var ret []*models.Job
+	for _, job := range cron.Jobs() {
+		lastRun, err := qb.FindLastRun(job.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var schedule *string
+		if job.Schedule != "" {
+			s := job.Schedule
+			schedule = &s
+		}
+
+		ret = append(ret, &models.Job{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    schedule,
+			Running:     cron.IsRunning(job.Name),
+			LastRun:     lastRun,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindJobRun(ctx context.Context, id string) (*models.JobRun, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Job()
+
+	UUID, _ := uuid.FromString(id)
+	return qb.FindRun(UUID)
+}
+
+func (r *queryResolver) QueryJobRuns(ctx context.Context, job *string, limit *int) ([]*models.JobRun, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	count := defaultJobRunsLimit
+	if limit != nil && *limit > 0 {
+		count = *limit
+	}
+	if count > maxJobRunsLimit {
+		count = maxJobRunsLimit
+	}
+
+	return r.getRepoFactory(ctx).Job().FindRuns(job, count)
+}
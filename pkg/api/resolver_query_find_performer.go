@@ -33,3 +33,31 @@ func (r *queryResolver) QueryPerformers(ctx context.Context, performerFilter *mo
 		Count:      count,
 	}, nil
 }
+
+func (r *queryResolver) PerformersConnection(ctx context.Context, performerFilter *models.PerformerFilterType, filter *models.CursorQuerySpec) (*models.PerformerConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Performer()
+
+	performers, page, err := qb.QueryPage(performerFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []*models.PerformerEdge{}
+	for i, performer := range performers {
+		edges = append(edges, &models.PerformerEdge{
+			Cursor: page.Cursors[i],
+			Node:   performer,
+		})
+	}
+
+	return &models.PerformerConnection{
+		Edges:      edges,
+		PageInfo:   page.PageInfo(),
+		TotalCount: page.Count,
+	}, nil
+}
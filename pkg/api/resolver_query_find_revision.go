@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/revision"
+)
+
+func (r *queryResolver) FindRevision(ctx context.Context, targetType models.TargetTypeEnum, id string, at *time.Time) (*models.Revision, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	targetID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	when := time.Now()
+	if at != nil {
+		when = *at
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return fac.Revision().FindAt(targetType, targetID, when)
+}
+
+func (r *queryResolver) RevisionDiff(ctx context.Context, from string, to string) (*models.RevisionDiff, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Revision()
+
+	fromRevision, err := findRevision(qb, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := findRevision(qb, to)
+	if err != nil {
+		return nil, err
+	}
+
+	oldDetails, details, err := revision.Diff(fromRevision, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionDiff{
+		From:       fromRevision,
+		To:         toRevision,
+		OldDetails: oldDetails,
+		Details:    details,
+	}, nil
+}
+
+func findRevision(qb models.RevisionRepo, id string) (*models.Revision, error) {
+	revisionID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := qb.Find(revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, errors.New("revision " + id + " not found")
+	}
+	return ret, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/scene"
 )
 
 func (r *queryResolver) FindScene(ctx context.Context, id string) (*models.Scene, error) {
@@ -71,6 +72,41 @@ func (r *queryResolver) FindScenesByFullFingerprints(ctx context.Context, finger
 	return qb.FindByFullFingerprints(fingerprints)
 }
 
+func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, studioID *string, limit *int) ([]*models.SceneDuplicateCluster, error) {
+	// comparing every fingerprint is expensive, so it is limited to editors
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	d := config.GetPHashDistance()
+	if distance != nil {
+		d = *distance
+	}
+	if d < 0 || d > 16 {
+		return nil, errors.New("distance must be between 0 and 16")
+	}
+
+	l := 100
+	if limit != nil {
+		l = *limit
+	}
+	if l < 1 || l > 1000 {
+		return nil, errors.New("limit must be between 1 and 1000")
+	}
+
+	var studioUUID *uuid.UUID
+	if studioID != nil {
+		id, err := uuid.FromString(*studioID)
+		if err != nil {
+			return nil, err
+		}
+		studioUUID = &id
+	}
+
+	fac := r.getRepoFactory(ctx)
+	return scene.FindDuplicates(fac, d, studioUUID, l)
+}
+
 func (r *queryResolver) QueryScenes(ctx context.Context, sceneFilter *models.SceneFilterType, filter *models.QuerySpec) (*models.QueryScenesResultType, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
@@ -85,3 +121,31 @@ func (r *queryResolver) QueryScenes(ctx context.Context, sceneFilter *models.Sce
 		Count:  count,
 	}, nil
 }
+
+func (r *queryResolver) ScenesConnection(ctx context.Context, sceneFilter *models.SceneFilterType, filter *models.CursorQuerySpec) (*models.SceneConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Scene()
+
+	scenes, page, err := qb.QueryPage(sceneFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []*models.SceneEdge{}
+	for i, scene := range scenes {
+		edges = append(edges, &models.SceneEdge{
+			Cursor: page.Cursors[i],
+			Node:   scene,
+		})
+	}
+
+	return &models.SceneConnection{
+		Edges:      edges,
+		PageInfo:   page.PageInfo(),
+		TotalCount: page.Count,
+	}, nil
+}
@@ -40,3 +40,31 @@ func (r *queryResolver) QueryStudios(ctx context.Context, studioFilter *models.S
 		Count:   count,
 	}, nil
 }
+
+func (r *queryResolver) StudiosConnection(ctx context.Context, studioFilter *models.StudioFilterType, filter *models.CursorQuerySpec) (*models.StudioConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Studio()
+
+	studios, page, err := qb.QueryPage(studioFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []*models.StudioEdge{}
+	for i, studio := range studios {
+		edges = append(edges, &models.StudioEdge{
+			Cursor: page.Cursors[i],
+			Node:   studio,
+		})
+	}
+
+	return &models.StudioConnection{
+		Edges:      edges,
+		PageInfo:   page.PageInfo(),
+		TotalCount: page.Count,
+	}, nil
+}
@@ -59,3 +59,31 @@ func (r *queryResolver) QueryTags(ctx context.Context, tagFilter *models.TagFilt
 		Count: count,
 	}, nil
 }
+
+func (r *queryResolver) TagsConnection(ctx context.Context, tagFilter *models.TagFilterType, filter *models.CursorQuerySpec) (*models.TagConnection, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Tag()
+
+	tags, page, err := qb.QueryPage(tagFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := []*models.TagEdge{}
+	for i, tag := range tags {
+		edges = append(edges, &models.TagEdge{
+			Cursor: page.Cursors[i],
+			Node:   tag,
+		})
+	}
+
+	return &models.TagConnection{
+		Edges:      edges,
+		PageInfo:   page.PageInfo(),
+		TotalCount: page.Count,
+	}, nil
+}
@@ -78,9 +78,14 @@ func removeSensitiveUserDetails(ctx context.Context, users models.Users) {
 		userID = currentUser.ID.String()
 	}
 
+	// scoped api keys may not see the primary api key
+	scoped := validateUnscoped(ctx) != nil
+
 	for _, u := range users {
 		if u != nil && u.ID.String() != userID {
 			u.RemoveSensitiveFields()
+		} else if u != nil && scoped {
+			u.APIKey = ""
 		}
 	}
 }
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func (r *queryResolver) FindWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Webhook()
+
+	UUID, _ := uuid.FromString(id)
+	return qb.Find(UUID)
+}
+
+func (r *queryResolver) QueryWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Webhook()
+
+	return qb.FindAll()
+}
+
+func (r *queryResolver) QueryWebhookDeliveries(ctx context.Context, deliveryFilter *models.WebhookDeliveryFilterType, filter *models.QuerySpec) (*models.QueryWebhookDeliveriesResultType, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	fac := r.getRepoFactory(ctx)
+	qb := fac.Webhook()
+
+	deliveries, count, err := qb.QueryDeliveries(deliveryFilter, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QueryWebhookDeliveriesResultType{
+		Deliveries: deliveries,
+		Count:      count,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+const (
+	defaultWatchedActivityLimit = 25
+	maxWatchedActivityLimit     = 100
+)
+
+func (r *queryResolver) WatchedActivity(ctx context.Context, since *time.Time, limit *int) (*models.WatchedActivity, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, user.ErrUnauthorized
+	}
+
+	count := defaultWatchedActivityLimit
+	if limit != nil && *limit > 0 {
+		count = *limit
+	}
+	if count > maxWatchedActivityLimit {
+		count = maxWatchedActivityLimit
+	}
+
+	wqb := r.getRepoFactory(ctx).Watch()
+	scenes, err := wqb.FindWatchedScenes(currentUser.ID, since, count)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := wqb.FindWatchedEdits(currentUser.ID, since, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WatchedActivity{
+		Scenes: scenes,
+		Edits:  edits,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash-box/pkg/manager/edit"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type subscriptionResolver struct{ *Resolver }
+
+func (r *subscriptionResolver) EditCreated(ctx context.Context) (<-chan *models.Edit, error) {
+	return subscribeToEdits(ctx, edit.EventCreated)
+}
+
+func (r *subscriptionResolver) EditVoted(ctx context.Context) (<-chan *models.Edit, error) {
+	return subscribeToEdits(ctx, edit.EventVoted)
+}
+
+func (r *subscriptionResolver) EditCommented(ctx context.Context) (<-chan *models.Edit, error) {
+	return subscribeToEdits(ctx, edit.EventCommented)
+}
+
+func (r *subscriptionResolver) EditStatusChanged(ctx context.Context) (<-chan *models.Edit, error) {
+	return subscribeToEdits(ctx, edit.EventStatusChanged)
+}
+
+func subscribeToEdits(ctx context.Context, event edit.EventType) (<-chan *models.Edit, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	return edit.Subscribe(ctx, event), nil
+}
@@ -0,0 +1,169 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type revisionTestRunner struct {
+	testRunner
+}
+
+func createRevisionTestRunner(t *testing.T) *revisionTestRunner {
+	return &revisionTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *revisionTestRunner) testTagHistory() {
+	name := s.generateTagName()
+	createdTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:    name,
+		Aliases: []string{"revisionAlias1"},
+	})
+	if err != nil {
+		return
+	}
+	afterCreate := time.Now()
+
+	newName := s.generateTagName()
+	_, err = s.resolver.Mutation().TagUpdate(s.ctx, models.TagUpdateInput{
+		ID:      createdTag.ID,
+		Name:    &newName,
+		Aliases: []string{"revisionAlias2"},
+	})
+	if err != nil {
+		s.t.Errorf("Error updating tag: %s", err.Error())
+		return
+	}
+
+	description := "revisionDescription"
+	id := createdTag.ID
+	modifyEdit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{
+		Name:        &newName,
+		Description: &description,
+		Aliases:     []string{"revisionAlias2"},
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	})
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(modifyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	tagID := uuid.FromStringOrNil(createdTag.ID)
+	tag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	history, err := s.resolver.Tag().History(s.ctx, tag)
+	if err != nil {
+		s.t.Errorf("Error finding tag history: %s", err.Error())
+		return
+	}
+	if len(history) != 3 {
+		s.fieldMismatch(3, len(history), "History count")
+		return
+	}
+
+	operations := []string{history[0].Operation, history[1].Operation, history[2].Operation}
+	expectedOperations := []string{models.OperationEnumModify.String(), models.OperationEnumModify.String(), models.OperationEnumCreate.String()}
+	if !reflect.DeepEqual(operations, expectedOperations) {
+		s.fieldMismatch(expectedOperations, operations, "History operations")
+	}
+	if !history[0].EditID.Valid || history[0].EditID.UUID != appliedEdit.ID {
+		s.fieldMismatch(appliedEdit.ID, history[0].EditID, "Revision edit")
+	}
+	if history[1].EditID.Valid {
+		s.fieldMismatch(nil, history[1].EditID, "Revision edit")
+	}
+
+	created, err := s.resolver.Query().FindRevision(s.ctx, models.TargetTypeEnumTag, createdTag.ID, &afterCreate)
+	if err != nil {
+		s.t.Errorf("Error finding revision: %s", err.Error())
+		return
+	}
+	if created == nil || created.ID != history[2].ID || created.TargetID != tagID {
+		s.fieldMismatch(history[2].ID, created, "Revision at time")
+		return
+	}
+
+	details, _ := s.resolver.Revision().Details(s.ctx, created)
+	tagDetails := details.(*models.TagEdit)
+	if *tagDetails.Name != name {
+		s.fieldMismatch(name, *tagDetails.Name, "Revision name")
+	}
+	if !reflect.DeepEqual(tagDetails.AddedAliases, []string{"revisionAlias1"}) {
+		s.fieldMismatch([]string{"revisionAlias1"}, tagDetails.AddedAliases, "Revision aliases")
+	}
+
+	diff, err := s.resolver.Query().RevisionDiff(s.ctx, history[2].ID.String(), history[0].ID.String())
+	if err != nil {
+		s.t.Errorf("Error comparing revisions: %s", err.Error())
+		return
+	}
+
+	oldDetails := diff.OldDetails.(*models.TagEdit)
+	newDetails := diff.Details.(*models.TagEdit)
+	if oldDetails.Name == nil || *oldDetails.Name != name {
+		s.fieldMismatch(name, oldDetails.Name, "Diff old name")
+	}
+	if newDetails.Name == nil || *newDetails.Name != newName {
+		s.fieldMismatch(newName, newDetails.Name, "Diff new name")
+	}
+	if newDetails.Description == nil || *newDetails.Description != description {
+		s.fieldMismatch(description, newDetails.Description, "Diff new description")
+	}
+	if !reflect.DeepEqual(newDetails.AddedAliases, []string{"revisionAlias2"}) {
+		s.fieldMismatch([]string{"revisionAlias2"}, newDetails.AddedAliases, "Diff added aliases")
+	}
+	if !reflect.DeepEqual(newDetails.RemovedAliases, []string{"revisionAlias1"}) {
+		s.fieldMismatch([]string{"revisionAlias1"}, newDetails.RemovedAliases, "Diff removed aliases")
+	}
+}
+
+func (s *revisionTestRunner) testDestroyedTagRevision() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.resolver.Mutation().TagDestroy(s.ctx, models.TagDestroyInput{ID: createdTag.ID}); err != nil {
+		s.t.Errorf("Error destroying tag: %s", err.Error())
+		return
+	}
+
+	latest, err := s.resolver.Query().FindRevision(s.ctx, models.TargetTypeEnumTag, createdTag.ID, nil)
+	if err != nil {
+		s.t.Errorf("Error finding revision: %s", err.Error())
+		return
+	}
+	if latest == nil || latest.Operation != models.OperationEnumDestroy.String() {
+		s.fieldMismatch(models.OperationEnumDestroy.String(), latest, "Latest revision operation")
+		return
+	}
+
+	details, _ := s.resolver.Revision().Details(s.ctx, latest)
+	if name := details.(*models.TagEdit).Name; name == nil || *name != createdTag.Name {
+		s.fieldMismatch(createdTag.Name, name, "Destroyed tag name")
+	}
+}
+
+func TestTagHistory(t *testing.T) {
+	pt := createRevisionTestRunner(t)
+	pt.testTagHistory()
+}
+
+func TestDestroyedTagRevision(t *testing.T) {
+	pt := createRevisionTestRunner(t)
+	pt.testDestroyedTagRevision()
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/stashapp/stash-box/pkg/image"
@@ -21,10 +23,65 @@ func (rs imageRoutes) Routes() chi.Router {
 func (rs imageRoutes) Image(w http.ResponseWriter, r *http.Request) {
 	checksum := chi.URLParam(r, "checksum")
 
-	if err := config.ValidateImageLocation(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if config.GetImageBackend() == config.FileBackend {
+		if err := config.ValidateImageLocation(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	backend := image.GetBackend()
+	if backend == nil {
+		http.Error(w, image.ErrNoBackend.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	size := r.URL.Query().Get("size")
+	if size != "" && !isImageVariant(size) {
+		http.Error(w, "unknown image size", http.StatusBadRequest)
 		return
 	}
 
-	http.ServeFile(w, r, image.GetImagePath(config.GetImageLocation(), checksum))
+	img, err := getRepo(r.Context()).Image().FindByChecksum(checksum)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if img == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// serve the requested variant if it has been generated, falling back
+	// to the original
+	var file io.ReadCloser
+	if size != "" {
+		if exists, _ := backend.Exists(img, size); exists {
+			file, err = backend.ReadVariant(img, size)
+		}
+	}
+	if file == nil && err == nil {
+		file, err = backend.ReadFile(img)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	if rs, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", time.Time{}, rs)
+		return
+	}
+
+	_, _ = io.Copy(w, file)
+}
+
+func isImageVariant(name string) bool {
+	for _, variant := range config.GetImageVariants() {
+		if variant.Name == name {
+			return true
+		}
+	}
+	return false
 }
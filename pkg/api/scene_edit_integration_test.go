@@ -7,6 +7,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/gofrs/uuid"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -446,6 +449,157 @@ func (s *sceneEditTestRunner) verifyAppliedMergeSceneEdit(input models.SceneEdit
 	}
 }
 
+func (s *sceneEditTestRunner) testApplySceneMarkerEdit() {
+	createdScene, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+	performer, _ := s.createTestPerformer(nil)
+	tag, _ := s.createTestTag(nil)
+	otherTag, _ := s.createTestTag(nil)
+
+	title := createdScene.Title
+	end := 60
+	intro := &models.SceneMarkerInput{
+		Start:        0,
+		End:          &end,
+		TagID:        tag.ID,
+		PerformerIds: []string{performer.ID},
+	}
+	later := &models.SceneMarkerInput{
+		Start: 120,
+		TagID: otherTag.ID,
+	}
+
+	details := &models.SceneEditDetailsInput{
+		Title: title,
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: performer.ID},
+		},
+		Markers: []*models.SceneMarkerInput{later, intro},
+	}
+
+	id := createdScene.ID
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	edit, err := s.createTestSceneEdit(models.OperationEnumModify, details, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	scene, _ := s.resolver.Query().FindScene(s.ctx, id)
+	markers, err := s.resolver.Scene().Markers(s.ctx, scene)
+	if err != nil {
+		s.t.Errorf("Error getting scene markers: %s", err.Error())
+		return
+	}
+
+	if len(markers) != 2 {
+		s.t.Errorf("expected 2 markers, got %d", len(markers))
+		return
+	}
+
+	// markers are ordered by start time
+	if markers[0].StartSeconds != 0 || markers[0].TagID.String() != tag.ID {
+		s.fieldMismatch(intro, markers[0], "Markers[0]")
+	}
+	performers, err := s.resolver.SceneMarker().Performers(s.ctx, markers[0])
+	if err != nil || len(performers) != 1 || performers[0].ID.String() != performer.ID {
+		s.fieldMismatch(intro.PerformerIds, performers, "Markers[0].Performers")
+	}
+	introID := markers[0].ID
+
+	// remove the later marker, keeping the intro
+	details.Markers = []*models.SceneMarkerInput{intro}
+	edit, err = s.createTestSceneEdit(models.OperationEnumModify, details, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(edit.ID.String()); err != nil {
+		return
+	}
+
+	sceneID, _ := uuid.FromString(id)
+	remaining, err := dbtest.Repo().Scene().GetMarkers(sceneID)
+	if err != nil {
+		s.t.Errorf("Error getting scene markers: %s", err.Error())
+		return
+	}
+
+	if len(remaining) != 1 || remaining[0].ID != introID {
+		s.t.Errorf("expected intro marker %s to be kept, got %v", introID, remaining)
+	}
+
+	// marker performers must be scene performers
+	details.Performers = nil
+	if _, err := s.resolver.Mutation().SceneEdit(s.ctx, models.SceneEditInput{
+		Edit:    &editInput,
+		Details: details,
+	}); err == nil {
+		s.t.Error("expected error for marker performer not in scene")
+	}
+}
+
+func (s *sceneEditTestRunner) testApplyMergeSceneEditDeletesMarkers() {
+	mergeSource, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+	tag, _ := s.createTestTag(nil)
+
+	sourceID := mergeSource.ID
+	markerEdit, err := s.createTestSceneEdit(models.OperationEnumModify, &models.SceneEditDetailsInput{
+		Title: mergeSource.Title,
+		Markers: []*models.SceneMarkerInput{
+			{Start: 0, TagID: tag.ID},
+		},
+	}, &models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &sourceID,
+	})
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(markerEdit.ID.String()); err != nil {
+		return
+	}
+
+	targetID := mergeTarget.ID
+	mergeEdit, err := s.createTestSceneEdit(models.OperationEnumMerge, &models.SceneEditDetailsInput{
+		Title: mergeTarget.Title,
+	}, &models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &targetID,
+		MergeSourceIds: []string{sourceID},
+	})
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+
+	id, _ := uuid.FromString(sourceID)
+	markers, err := dbtest.Repo().Scene().GetMarkers(id)
+	if err != nil {
+		s.t.Errorf("Error getting scene markers: %s", err.Error())
+		return
+	}
+	if len(markers) != 0 {
+		s.t.Errorf("expected markers of merged scene to be deleted, got %d", len(markers))
+	}
+}
+
 func TestCreateSceneEdit(t *testing.T) {
 	pt := createSceneEditTestRunner(t)
 	pt.testCreateSceneEdit()
@@ -486,7 +640,17 @@ func TestApplyDestroySceneEdit(t *testing.T) {
 	pt.testApplyDestroySceneEdit()
 }
 
+func TestApplySceneMarkerEdit(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testApplySceneMarkerEdit()
+}
+
 func TestApplyMergeSceneEdit(t *testing.T) {
 	pt := createSceneEditTestRunner(t)
 	pt.testApplyMergeSceneEdit()
 }
+
+func TestApplyMergeSceneEditDeletesMarkers(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testApplyMergeSceneEditDeletesMarkers()
+}
@@ -1088,6 +1088,12 @@ func (s *sceneTestRunner) testUnauthorisedSceneModify() {
 	if err != user.ErrUnauthorized {
 		s.t.Errorf("SceneDestroy: got %v want %v", err, user.ErrUnauthorized)
 	}
+
+	// requires edit
+	_, err = s.resolver.Query().FindDuplicateScenes(s.ctx, nil, nil, nil)
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("FindDuplicateScenes: got %v want %v", err, user.ErrUnauthorized)
+	}
 }
 
 func (s *sceneTestRunner) testUnauthorisedSceneQuery() {
@@ -1158,6 +1164,71 @@ func TestUnauthorisedSceneModify(t *testing.T) {
 	pt.testUnauthorisedSceneModify()
 }
 
+func (s *sceneTestRunner) testFindDuplicateScenes() {
+	studio, _ := s.createTestStudio(nil)
+	studioID := studio.ID
+
+	fingerprint := s.generateSceneFingerprint(nil)
+	phash := &models.FingerprintEditInput{
+		Algorithm: models.FingerprintAlgorithmPhash,
+		Hash:      "f0f0f0f0f0f0f0f0",
+		Duration:  1234,
+		UserIds:   []string{},
+	}
+	closePHash := &models.FingerprintEditInput{
+		Algorithm: models.FingerprintAlgorithmPhash,
+		Hash:      "f0f0f0f0f0f0f0f1",
+		Duration:  1234,
+		UserIds:   []string{},
+	}
+
+	createScene := func(fingerprints ...*models.FingerprintEditInput) *sceneOutput {
+		title := s.generateSceneName()
+		scene, err := s.createTestScene(&models.SceneCreateInput{
+			Title:        &title,
+			StudioID:     &studioID,
+			Fingerprints: fingerprints,
+		})
+		if err != nil {
+			s.t.FailNow()
+		}
+		return scene
+	}
+
+	// target has the most fingerprint submissions
+	target := createScene(fingerprint, phash)
+	createScene(fingerprint)
+	createScene(closePHash)
+	createScene(s.generateSceneFingerprint(nil))
+
+	distance := 0
+	clusters, err := s.resolver.Query().FindDuplicateScenes(s.ctx, &distance, &studioID, nil)
+	if err != nil {
+		s.t.Errorf("Error finding duplicate scenes: %s", err.Error())
+		return
+	}
+
+	if len(clusters) != 1 || len(clusters[0].Scenes) != 2 {
+		s.t.Errorf("expected one cluster of 2 scenes with distance 0, got %v", clusters)
+		return
+	}
+
+	if clusters[0].SuggestedTarget.ID.String() != target.ID {
+		s.fieldMismatch(target.ID, clusters[0].SuggestedTarget.ID.String(), "SuggestedTarget")
+	}
+
+	distance = 1
+	clusters, err = s.resolver.Query().FindDuplicateScenes(s.ctx, &distance, &studioID, nil)
+	if err != nil {
+		s.t.Errorf("Error finding duplicate scenes: %s", err.Error())
+		return
+	}
+
+	if len(clusters) != 1 || len(clusters[0].Scenes) != 3 {
+		s.t.Errorf("expected one cluster of 3 scenes with distance 1, got %v", clusters)
+	}
+}
+
 func TestUnauthorisedSceneQuery(t *testing.T) {
 	pt := &sceneTestRunner{
 		testRunner: *asNone(t),
@@ -1165,6 +1236,14 @@ func TestUnauthorisedSceneQuery(t *testing.T) {
 	pt.testUnauthorisedSceneQuery()
 }
 
+func TestFindDuplicateScenes(t *testing.T) {
+	// creating scenes requires modify and finding duplicates requires edit
+	pt := &sceneTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testFindDuplicateScenes()
+}
+
 func TestSubmitFingerprint(t *testing.T) {
 	pt := createSceneTestRunner(t)
 	pt.testSubmitFingerprint()
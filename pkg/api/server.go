@@ -9,18 +9,23 @@ import (
 	"html/template"
 	"io/fs"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"net/url"
 	"path"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	gqlHandler "github.com/99designs/gqlgen/graphql/handler"
 	gqlExtension "github.com/99designs/gqlgen/graphql/handler/extension"
 	gqlTransport "github.com/99designs/gqlgen/graphql/handler/transport"
 	gqlPlayground "github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
 	"github.com/stashapp/stash-box/pkg/dataloader"
 	"github.com/stashapp/stash-box/pkg/logger"
@@ -28,6 +33,7 @@ import (
 	"github.com/stashapp/stash-box/pkg/manager/paths"
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/user"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 var version = "0.0.0"
@@ -36,6 +42,12 @@ var githash string
 
 const APIKeyHeader = "ApiKey"
 
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+const websocketKeepAliveInterval = 10 * time.Second
+
+var apiRateLimiter = user.NewRateLimiter()
+
 func getUserAndRoles(fac models.Repo, userID string) (*models.User, []models.RoleEnum, error) {
 	u, err := user.Get(fac, userID)
 	if err != nil {
@@ -47,7 +59,42 @@ func getUserAndRoles(fac models.Repo, userID string) (*models.User, []models.Rol
 		return nil, nil, err
 	}
 
-	return u, roles, nil
+	return u, user.ApplyTOTPPolicy(u, roles), nil
+}
+
+// getAPIKeyUserAndRoles returns the user that the api key belongs to, along
+// with the roles the key may use. If the key is a named api key, its roles
+// are restricted to its scope, and the key is returned.
+func getAPIKeyUserAndRoles(fac models.Repo, apiKey string) (*models.User, []models.RoleEnum, *models.APIKey, error) {
+	userID, err := user.GetUserIDFromAPIKey(apiKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	u, roles, err := getUserAndRoles(fac, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if u == nil {
+		return nil, nil, nil, user.ErrUnauthorized
+	}
+
+	key, err := user.ValidateAPIKey(fac, u, apiKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if key != nil {
+		roles = user.ScopeRoles(roles, models.APIKeyScopeEnum(key.Scope))
+
+		// don't expose the primary api key to scoped keys
+		scopedUser := *u
+		scopedUser.APIKey = ""
+		u = &scopedUser
+	}
+
+	return u, roles, key, nil
 }
 
 func authenticateHandler() func(http.Handler) http.Handler {
@@ -56,39 +103,47 @@ func authenticateHandler() func(http.Handler) http.Handler {
 			ctx := r.Context()
 
 			// translate api key into current user, if present
-			userID := ""
 			apiKey := r.Header.Get(APIKeyHeader)
-			var err error
 			if apiKey != "" {
-				userID, err = user.GetUserIDFromAPIKey(apiKey)
+				u, roles, key, err := getAPIKeyUserAndRoles(getRepo(ctx), apiKey)
+				if err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(err.Error()))
+					return
+				}
+
+				if allowed, retryAfter := apiRateLimiter.Allow(u.ID, user.GetAPIRateLimit(roles), time.Now()); !allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = w.Write([]byte(ErrRateLimited.Error()))
+					return
+				}
+
+				user.RecordAPICall(u.ID, key)
+
+				ctx = context.WithValue(ctx, user.ContextUser, u)
+				ctx = context.WithValue(ctx, user.ContextRoles, roles)
+				if key != nil {
+					ctx = context.WithValue(ctx, user.ContextAPIKey, key)
+				}
 			} else {
 				// handle session
-				userID, err = getSessionUserID(w, r)
-			}
-
-			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				_, err = w.Write([]byte(err.Error()))
+				userID, err := getSessionUserID(w, r)
 				if err != nil {
-					logger.Error(err)
+					w.WriteHeader(http.StatusInternalServerError)
+					_, err = w.Write([]byte(err.Error()))
+					if err != nil {
+						logger.Error(err)
+					}
+					return
 				}
-				return
-			}
 
-			u, roles, err := getUserAndRoles(getRepo(ctx), userID)
+				u, roles, _ := getUserAndRoles(getRepo(ctx), userID)
 
-			// ensure api key of the user matches the passed one
-			if apiKey != "" && u != nil && u.APIKey != apiKey {
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte(err.Error()))
-				return
+				ctx = context.WithValue(ctx, user.ContextUser, u)
+				ctx = context.WithValue(ctx, user.ContextRoles, roles)
 			}
 
-			// TODO - increment api key counters
-
-			ctx = context.WithValue(ctx, user.ContextUser, u)
-			ctx = context.WithValue(ctx, user.ContextRoles, roles)
-
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -96,6 +151,72 @@ func authenticateHandler() func(http.Handler) http.Handler {
 	}
 }
 
+// websocketInit authenticates websocket connections using the api key in the
+// connection init payload, since browsers cannot set headers on websocket
+// requests. Connections without an api key fall back to the session user.
+func websocketInit(ctx context.Context, initPayload gqlTransport.InitPayload) (context.Context, error) {
+	apiKey := initPayload.GetString(APIKeyHeader)
+	if apiKey == "" {
+		return ctx, nil
+	}
+
+	u, roles, key, err := getAPIKeyUserAndRoles(getRepo(ctx), apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed, _ := apiRateLimiter.Allow(u.ID, user.GetAPIRateLimit(roles), time.Now()); !allowed {
+		return nil, ErrRateLimited
+	}
+	user.RecordAPICall(u.ID, key)
+
+	ctx = context.WithValue(ctx, user.ContextUser, u)
+	ctx = context.WithValue(ctx, user.ContextRoles, roles)
+	if key != nil {
+		ctx = context.WithValue(ctx, user.ContextAPIKey, key)
+	}
+
+	return ctx, nil
+}
+
+// checkWebsocketOrigin only allows websocket connections from pages served
+// by this server or from the configured origins. Websocket connections carry
+// the session cookie, so any other page could otherwise act as the user.
+// Requests without an origin are not made by browsers and are allowed.
+func checkWebsocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	for _, allowed := range config.GetWebsocketAllowedOrigins() {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subscriptionLoaders replaces the dataloaders for each subscription event.
+// The loaders are otherwise created once per request, and would serve stale
+// data for the lifetime of the websocket connection.
+func subscriptionLoaders(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if op := graphql.GetOperationContext(ctx); op.Operation != nil && op.Operation.Operation == ast.Subscription {
+		ctx = context.WithValue(ctx, dataloader.GetLoadersKey(), dataloader.GetLoaders(ctx, getRepo(ctx)))
+	}
+
+	return next(ctx)
+}
+
 func redirect(w http.ResponseWriter, req *http.Request) {
 	target := "https://" + req.Host + req.URL.Path
 	if len(req.URL.RawQuery) > 0 {
@@ -137,11 +258,19 @@ func Start(rfp RepoProvider, ui embed.FS) {
 
 	gqlSrv := gqlHandler.New(models.NewExecutableSchema(models.Config{Resolvers: NewResolver(getRepo)}))
 	gqlSrv.SetRecoverFunc(recoverFunc)
+	gqlSrv.AddTransport(gqlTransport.Websocket{
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: checkWebsocketOrigin,
+		},
+		InitFunc:              websocketInit,
+		KeepAlivePingInterval: websocketKeepAliveInterval,
+	})
 	gqlSrv.AddTransport(gqlTransport.Options{})
 	gqlSrv.AddTransport(gqlTransport.GET{})
 	gqlSrv.AddTransport(gqlTransport.POST{})
 	gqlSrv.AddTransport(gqlTransport.MultipartForm{})
 	gqlSrv.Use(gqlExtension.Introspection{})
+	gqlSrv.AroundResponses(subscriptionLoaders)
 
 	r.Handle("/graphql", dataloader.Middleware(rfp.Repo())(gqlSrv))
 
@@ -162,6 +291,14 @@ func Start(rfp RepoProvider, ui embed.FS) {
 	})
 	r.HandleFunc("/logout", handleLogout)
 
+	// external identity provider login
+	if config.GetOIDCConfig() != nil {
+		r.Get("/oidc/login", handleOIDCLogin)
+		r.Get("/oidc/link", handleOIDCLink)
+		r.Get(oidcCallbackPath, handleOIDCCallback)
+		r.Post("/oidc/second-factor", handleOIDCSecondFactor)
+	}
+
 	r.Mount("/image", imageRoutes{}.Routes())
 
 	// Serve the web app
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
@@ -12,6 +13,7 @@ import (
 const cookieName = "session"
 const usernameFormKey = "username"
 const passwordFormKey = "password"
+const codeFormKey = "code"
 const userIDKey = "userID"
 const maxCookieAge = 60 * 60 * 1 // 1 hours
 
@@ -41,6 +43,19 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// users with two-factor authentication enabled must also provide a code
+	err = fac.WithTxn(func() error {
+		return user.AuthenticateSecondFactor(fac, userID, r.FormValue(codeFormKey))
+	})
+
+	if errors.Is(err, user.ErrTOTPRequired) || errors.Is(err, user.ErrTOTPInvalidCode) || errors.Is(err, user.ErrTOTPTooManyAttempts) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	newSession.Values[userIDKey] = userID
 	newSession.Options.MaxAge = maxCookieAge
 
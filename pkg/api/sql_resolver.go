@@ -29,3 +29,10 @@ func resolveNullInt64(value sql.NullInt64) (*int, error) {
 	}
 	return nil, nil
 }
+
+func resolveNullFloat64(value sql.NullFloat64) *float64 {
+	if value.Valid {
+		return &value.Float64
+	}
+	return nil
+}
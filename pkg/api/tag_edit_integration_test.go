@@ -563,6 +563,285 @@ func (s *tagEditTestRunner) verifyAppliedMergeTagEdit(input models.TagEditDetail
 	}
 }
 
+func (s *tagEditTestRunner) testRevertModifyTagEdit() {
+	existingName := "tagName5"
+	existingAlias := "tagAlias5"
+	tagCreateInput := models.TagCreateInput{
+		Name:    existingName,
+		Aliases: []string{existingAlias},
+	}
+	createdTag, err := s.createTestTag(&tagCreateInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newName5"
+	newDescription := "newDescription5"
+	tagEditDetailsInput := models.TagEditDetailsInput{
+		Name:        &newName,
+		Description: &newDescription,
+		Aliases:     []string{"newTagAlias5"},
+	}
+	id := createdTag.ID
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	modifyEdit, err := s.createTestTagEdit(models.OperationEnumModify, &tagEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(modifyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+	s.verifyEditOperation(models.OperationEnumModify.String(), revertEdit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), revertEdit)
+
+	reverts, _ := s.resolver.Edit().Reverts(s.ctx, revertEdit)
+	if reverts == nil || reverts.ID != appliedEdit.ID {
+		s.fieldMismatch(appliedEdit.ID, reverts, "Reverts")
+	}
+	revertedBy, _ := s.resolver.Edit().RevertedBy(s.ctx, appliedEdit)
+	if len(revertedBy) != 1 || revertedBy[0].ID != revertEdit.ID {
+		s.fieldMismatch(revertEdit.ID, revertedBy, "RevertedBy")
+	}
+
+	// a pending revert blocks further reverts
+	if _, err := s.resolver.Mutation().RevertEdit(s.ctx, models.RevertEditInput{ID: appliedEdit.ID.String()}); err == nil {
+		s.t.Error("Expected error reverting edit with pending revert")
+	}
+
+	if _, err := s.applyEdit(revertEdit.ID.String()); err != nil {
+		return
+	}
+
+	revertedTag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if revertedTag.Name != existingName {
+		s.fieldMismatch(existingName, revertedTag.Name, "Name")
+	}
+
+	description, _ := s.resolver.Tag().Description(s.ctx, revertedTag)
+	if description != nil {
+		s.fieldMismatch(nil, *description, "Description")
+	}
+
+	aliases, _ := s.resolver.Tag().Aliases(s.ctx, revertedTag)
+	if !reflect.DeepEqual([]string{existingAlias}, aliases) {
+		s.fieldMismatch([]string{existingAlias}, aliases, "Aliases")
+	}
+}
+
+func (s *tagEditTestRunner) testRevertDestroyTagEdit() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	tagID := createdTag.ID
+	sceneInput := models.SceneCreateInput{
+		TagIds: []string{tagID},
+	}
+	scene, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &tagID,
+	}
+	destroyEdit, err := s.createTestTagEdit(models.OperationEnumDestroy, &models.TagEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+	s.verifyEditOperation(models.OperationEnumRestore.String(), revertEdit)
+
+	if _, err := s.applyEdit(revertEdit.ID.String()); err != nil {
+		return
+	}
+
+	restoredTag, _ := s.resolver.Query().FindTag(s.ctx, &tagID, nil)
+	if restoredTag.Deleted {
+		s.fieldMismatch(restoredTag.Deleted, false, "Deleted")
+	}
+
+	scene, err = s.client.findScene(scene.ID)
+	if err != nil {
+		s.t.Errorf("Error finding scene: %s", err.Error())
+		return
+	}
+	if len(scene.Tags) != 1 || scene.Tags[0].ID != tagID {
+		s.fieldMismatch(1, len(scene.Tags), "Scene tag count")
+	}
+}
+
+func (s *tagEditTestRunner) testRevertMergeTagEdit() {
+	mergeSource, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	sceneInput := models.SceneCreateInput{
+		TagIds: []string{mergeSource.ID, mergeTarget.ID},
+	}
+	scene1, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+	sceneInput = models.SceneCreateInput{
+		TagIds: []string{mergeSource.ID},
+	}
+	scene2, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newName6"
+	tagEditDetailsInput := models.TagEditDetailsInput{
+		Name: &newName,
+	}
+	id := mergeTarget.ID
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{mergeSource.ID},
+	}
+	mergeEdit, err := s.createTestTagEdit(models.OperationEnumMerge, &tagEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(mergeEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+	s.verifyEditOperation(models.OperationEnumRestore.String(), revertEdit)
+
+	if _, err := s.applyEdit(revertEdit.ID.String()); err != nil {
+		return
+	}
+
+	sourceID := mergeSource.ID
+	restoredTag, _ := s.resolver.Query().FindTag(s.ctx, &sourceID, nil)
+	if restoredTag.Deleted {
+		s.fieldMismatch(restoredTag.Deleted, false, "Deleted")
+	}
+
+	target, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if target.Name != mergeTarget.Name {
+		s.fieldMismatch(mergeTarget.Name, target.Name, "Name")
+	}
+
+	scene1, err = s.client.findScene(scene1.ID)
+	if err != nil {
+		s.t.Errorf("Error finding scene: %s", err.Error())
+		return
+	}
+	if len(scene1.Tags) != 2 {
+		s.fieldMismatch(2, len(scene1.Tags), "Scene 1 tag count")
+	}
+
+	scene2, err = s.client.findScene(scene2.ID)
+	if err != nil {
+		s.t.Errorf("Error finding scene: %s", err.Error())
+		return
+	}
+	if len(scene2.Tags) != 1 || scene2.Tags[0].ID != mergeSource.ID {
+		s.fieldMismatch(mergeSource.ID, scene2.Tags, "Scene 2 tags")
+	}
+}
+
+func (s *tagEditTestRunner) testConflictingTagEdits() {
+	tagCreateInput := models.TagCreateInput{
+		Name: "tagName8",
+	}
+	createdTag, err := s.createTestTag(&tagCreateInput)
+	if err != nil {
+		return
+	}
+
+	id := createdTag.ID
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	name1 := "newName8a"
+	edit1, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name1}, &editInput)
+	if err != nil {
+		return
+	}
+	name2 := "newName8b"
+	edit2, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name2}, &editInput)
+	if err != nil {
+		return
+	}
+	description := "newDescription8"
+	edit3, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &tagCreateInput.Name, Description: &description}, &editInput)
+	if err != nil {
+		return
+	}
+
+	conflicts, err := s.resolver.Edit().ConflictingEdits(s.ctx, edit1)
+	if err != nil {
+		s.t.Errorf("Error finding conflicting edits: %s", err.Error())
+		return
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != edit2.ID {
+		s.fieldMismatch(edit2.ID, conflicts, "ConflictingEdits")
+	}
+
+	if _, err := s.applyEdit(edit1.ID.String()); err != nil {
+		return
+	}
+
+	// the name of the tag no longer matches the old name in the second edit
+	edit2ID := edit2.ID.String()
+	staleEdit, _ := s.resolver.Query().FindEdit(s.ctx, &edit2ID)
+	if !staleEdit.Stale {
+		s.t.Error("Expected conflicting edit to be stale")
+	}
+	edit3ID := edit3.ID.String()
+	unchangedEdit, _ := s.resolver.Query().FindEdit(s.ctx, &edit3ID)
+	if unchangedEdit.Stale {
+		s.t.Error("Expected edit of other fields not to be stale")
+	}
+
+	failedEdit, err := s.applyEdit(edit2.ID.String())
+	if err != nil {
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumFailed.String(), failedEdit)
+	s.verifyEditApplication(false, failedEdit)
+
+	tag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if tag.Name != name1 {
+		s.fieldMismatch(name1, tag.Name, "Name")
+	}
+}
+
 func TestCreateTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testCreateTagEdit()
@@ -602,3 +881,23 @@ func TestApplyMergeTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testApplyMergeTagEdit()
 }
+
+func TestRevertModifyTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertModifyTagEdit()
+}
+
+func TestRevertDestroyTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertDestroyTagEdit()
+}
+
+func TestRevertMergeTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertMergeTagEdit()
+}
+
+func TestConflictingTagEdits(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testConflictingTagEdits()
+}
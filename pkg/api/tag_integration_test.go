@@ -181,6 +181,66 @@ func (s *tagTestRunner) testDestroyTag() {
 	// TODO - ensure scene was not removed
 }
 
+func (s *tagTestRunner) testTagsConnection() {
+	prefix := s.generateTagName() + "-connection"
+	names := []string{prefix + "-a", prefix + "-b", prefix + "-c"}
+	for _, name := range names {
+		if _, err := s.resolver.Mutation().TagCreate(s.ctx, models.TagCreateInput{Name: name}); err != nil {
+			s.t.Errorf("Error creating tag: %s", err.Error())
+			return
+		}
+	}
+
+	first := 2
+	direction := models.SortDirectionEnumAsc
+	filter := &models.CursorQuerySpec{
+		First:     &first,
+		Direction: &direction,
+	}
+	tagFilter := &models.TagFilterType{Name: &prefix}
+
+	page, err := s.resolver.Query().TagsConnection(s.ctx, tagFilter, filter)
+	if err != nil {
+		s.t.Errorf("Error querying tags connection: %s", err.Error())
+		return
+	}
+
+	if page.TotalCount != len(names) {
+		s.fieldMismatch(len(names), page.TotalCount, "TotalCount")
+	}
+	if len(page.Edges) != first || !page.PageInfo.HasNextPage {
+		s.t.Errorf("Expected %d edges with a next page, got %d edges", first, len(page.Edges))
+		return
+	}
+	for i, edge := range page.Edges {
+		if edge.Node.Name != names[i] {
+			s.fieldMismatch(names[i], edge.Node.Name, "Name")
+		}
+	}
+
+	filter.After = page.PageInfo.EndCursor
+	page, err = s.resolver.Query().TagsConnection(s.ctx, tagFilter, filter)
+	if err != nil {
+		s.t.Errorf("Error querying tags connection: %s", err.Error())
+		return
+	}
+
+	if len(page.Edges) != 1 || page.PageInfo.HasNextPage {
+		s.t.Errorf("Expected 1 edge without a next page, got %d edges", len(page.Edges))
+		return
+	}
+	if page.Edges[0].Node.Name != names[2] {
+		s.fieldMismatch(names[2], page.Edges[0].Node.Name, "Name")
+	}
+
+	// cursors are only valid for the sort they were created with
+	sort := "created_at"
+	filter.Sort = &sort
+	if _, err := s.resolver.Query().TagsConnection(s.ctx, tagFilter, filter); err == nil {
+		s.t.Error("Expected error using cursor with a different sort")
+	}
+}
+
 func (s *tagTestRunner) testUnauthorisedTagModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().TagCreate(s.ctx, models.TagCreateInput{})
@@ -210,6 +270,11 @@ func (s *tagTestRunner) testUnauthorisedTagQuery() {
 	if err != user.ErrUnauthorized {
 		s.t.Errorf("QueryTags: got %v want %v", err, user.ErrUnauthorized)
 	}
+
+	_, err = s.resolver.Query().TagsConnection(s.ctx, nil, nil)
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("TagsConnection: got %v want %v", err, user.ErrUnauthorized)
+	}
 }
 
 func TestCreateTag(t *testing.T) {
@@ -237,6 +302,11 @@ func TestDestroyTag(t *testing.T) {
 	pt.testDestroyTag()
 }
 
+func TestTagsConnection(t *testing.T) {
+	pt := createTagTestRunner(t)
+	pt.testTagsConnection()
+}
+
 func TestUnauthorisedTagModify(t *testing.T) {
 	pt := &tagTestRunner{
 		testRunner: *asRead(t),
@@ -0,0 +1,113 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type totpTestRunner struct {
+	testRunner
+}
+
+func createTOTPTestRunner(t *testing.T) *totpTestRunner {
+	return &totpTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *totpTestRunner) testEnrollTOTP() {
+	createdUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), user.ContextUser, createdUser)
+	enrolment, err := s.resolver.Mutation().EnrollTotp(ctx)
+	if err != nil {
+		s.t.Errorf("Error enrolling: %s", err.Error())
+		return
+	}
+
+	if enrolment.Secret == "" {
+		s.t.Error("Enrolment secret is empty")
+	}
+	if !strings.HasPrefix(enrolment.URI, "otpauth://totp/") || !strings.Contains(enrolment.URI, enrolment.Secret) {
+		s.t.Errorf("Unexpected enrolment URI: %s", enrolment.URI)
+	}
+
+	if _, err := s.resolver.Mutation().VerifyTotp(ctx, models.TOTPCodeInput{Code: "invalid"}); err != user.ErrTOTPInvalidCode {
+		s.t.Errorf("Expected %v verifying invalid code, got %v", user.ErrTOTPInvalidCode, err)
+	}
+
+	// enrolment is not complete until verified
+	u, err := user.Get(dbtest.Repo(), createdUser.ID.String())
+	if err != nil {
+		s.t.Errorf("Error finding user: %s", err.Error())
+		return
+	}
+	if u.TOTPEnabled {
+		s.t.Error("Expected two-factor authentication to be disabled before verification")
+	}
+	if err := user.AuthenticateSecondFactor(dbtest.Repo(), createdUser.ID.String(), ""); err != nil {
+		s.t.Errorf("Expected login without code before verification, got %v", err)
+	}
+
+	// pending enrolment can be cancelled
+	if _, err := s.resolver.Mutation().DisableTotp(ctx, models.TOTPDisableInput{}); err != nil {
+		s.t.Errorf("Error disabling pending enrolment: %s", err.Error())
+	}
+	if _, err := s.resolver.Mutation().DisableTotp(ctx, models.TOTPDisableInput{}); err != user.ErrTOTPNotEnabled {
+		s.t.Errorf("Expected %v disabling twice, got %v", user.ErrTOTPNotEnabled, err)
+	}
+}
+
+func (s *totpTestRunner) testDisableOtherUserTOTP() {
+	createdUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+	otherUser, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumEdit})
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.TODO(), user.ContextUser, createdUser)
+	if _, err := s.resolver.Mutation().EnrollTotp(ctx); err != nil {
+		s.t.Errorf("Error enrolling: %s", err.Error())
+		return
+	}
+
+	userID := createdUser.ID.String()
+	input := models.TOTPDisableInput{
+		UserID: &userID,
+	}
+
+	// non-admins may not disable other users' two-factor authentication
+	otherCtx := context.WithValue(context.TODO(), user.ContextUser, otherUser)
+	otherCtx = context.WithValue(otherCtx, user.ContextRoles, []models.RoleEnum{models.RoleEnumEdit})
+	if _, err := s.resolver.Mutation().DisableTotp(otherCtx, input); err == nil {
+		s.t.Error("Expected error disabling other user's two-factor authentication")
+	}
+
+	if _, err := s.resolver.Mutation().DisableTotp(s.ctx, input); err != nil {
+		s.t.Errorf("Error disabling two-factor authentication as admin: %s", err.Error())
+	}
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	pt := createTOTPTestRunner(t)
+	pt.testEnrollTOTP()
+}
+
+func TestDisableOtherUserTOTP(t *testing.T) {
+	pt := createTOTPTestRunner(t)
+	pt.testDisableOtherUserTOTP()
+}
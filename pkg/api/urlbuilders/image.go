@@ -1,5 +1,7 @@
 package urlbuilders
 
+import "net/url"
+
 type ImageURLBuilder struct {
 	BaseURL  string
 	Checksum string
@@ -15,3 +17,8 @@ func NewImageURLBuilder(baseURL string, checksum string) ImageURLBuilder {
 func (b ImageURLBuilder) GetImageURL() string {
 	return b.BaseURL + "/image/" + b.Checksum
 }
+
+// GetVariantURL returns the URL of the named variant of the image.
+func (b ImageURLBuilder) GetVariantURL(variant string) string {
+	return b.GetImageURL() + "?size=" + url.QueryEscape(variant)
+}
@@ -30,3 +30,12 @@ func (b S3ImageURLBuilder) GetImageURL() string {
 	id := b.Image.ID.String()
 	return config.BaseURL + "/" + id[0:2] + "/" + id[2:4] + "/" + id
 }
+
+// GetVariantURL returns the URL of the named variant of the image.
+func (b S3ImageURLBuilder) GetVariantURL(variant string) string {
+	config := config.GetS3Config()
+
+	hash := md5.Sum([]byte(b.Image.ID.String() + "-" + variant))
+	id := hex.EncodeToString(hash[:])
+	return config.BaseURL + "/" + id[0:2] + "/" + id[2:4] + "/" + id
+}
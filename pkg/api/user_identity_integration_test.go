@@ -0,0 +1,150 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+const testIssuer = "https://idp.example.com"
+
+type userIdentityTestRunner struct {
+	testRunner
+}
+
+func createUserIdentityTestRunner(t *testing.T) *userIdentityTestRunner {
+	return &userIdentityTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *userIdentityTestRunner) authenticate(identity user.ExternalIdentity, autoProvision bool) (string, error) {
+	fac := dbtest.Repo()
+	var ret string
+	err := fac.WithTxn(func() error {
+		var err error
+		ret, err = user.AuthenticateExternal(fac, identity, autoProvision)
+		return err
+	})
+
+	return ret, err
+}
+
+func (s *userIdentityTestRunner) testLinkIdentity() {
+	createdUser, err := s.createTestUser(nil, nil)
+	if err != nil {
+		return
+	}
+
+	identity := user.ExternalIdentity{
+		Issuer:   testIssuer,
+		Subject:  "link-" + createdUser.Name,
+		Username: createdUser.Name,
+	}
+
+	if _, err := s.authenticate(identity, false); err != user.ErrIdentityNotLinked {
+		s.t.Errorf("Expected %v for unlinked identity, got %v", user.ErrIdentityNotLinked, err)
+		return
+	}
+
+	fac := dbtest.Repo()
+	if err := fac.WithTxn(func() error {
+		return user.LinkIdentity(fac, createdUser.ID, identity)
+	}); err != nil {
+		s.t.Errorf("Error linking identity: %s", err.Error())
+		return
+	}
+
+	userID, err := s.authenticate(identity, false)
+	if err != nil {
+		s.t.Errorf("Error authenticating linked identity: %s", err.Error())
+		return
+	}
+	if userID != createdUser.ID.String() {
+		s.fieldMismatch(createdUser.ID.String(), userID, "UserID")
+	}
+
+	// the identity may not be linked to another user
+	otherUser, err := s.createTestUser(nil, nil)
+	if err != nil {
+		return
+	}
+	if err := fac.WithTxn(func() error {
+		return user.LinkIdentity(fac, otherUser.ID, identity)
+	}); err != user.ErrIdentityAlreadyLinked {
+		s.t.Errorf("Expected %v linking identity to another user, got %v", user.ErrIdentityAlreadyLinked, err)
+	}
+}
+
+func (s *userIdentityTestRunner) testProvisionUser() {
+	name := s.generateUserName()
+	identity := user.ExternalIdentity{
+		Issuer:   testIssuer,
+		Subject:  "provision-" + name,
+		Username: name,
+		Email:    name + "@example.com",
+	}
+
+	userID, err := s.authenticate(identity, true)
+	if err != nil {
+		s.t.Errorf("Error provisioning user: %s", err.Error())
+		return
+	}
+
+	fac := dbtest.Repo()
+	provisioned, err := user.Get(fac, userID)
+	if err != nil || provisioned == nil {
+		s.t.Errorf("Error finding provisioned user: %v", err)
+		return
+	}
+	if provisioned.Name != name {
+		s.fieldMismatch(name, provisioned.Name, "Name")
+	}
+	if provisioned.Email != identity.Email {
+		s.fieldMismatch(identity.Email, provisioned.Email, "Email")
+	}
+
+	roles, err := user.GetRoles(fac, userID)
+	if err != nil {
+		s.t.Errorf("Error getting roles: %s", err.Error())
+		return
+	}
+	if len(roles) == 0 {
+		s.t.Error("Provisioned user has no roles")
+	}
+
+	// subsequent logins use the provisioned user
+	secondID, err := s.authenticate(identity, true)
+	if err != nil {
+		s.t.Errorf("Error authenticating provisioned user: %s", err.Error())
+		return
+	}
+	if secondID != userID {
+		s.fieldMismatch(userID, secondID, "UserID")
+	}
+
+	// existing users must link their identity
+	other := user.ExternalIdentity{
+		Issuer:   testIssuer,
+		Subject:  "other-" + name,
+		Username: name + "-other",
+		Email:    identity.Email,
+	}
+	if _, err := s.authenticate(other, true); err != user.ErrIdentityEmailUsed {
+		s.t.Errorf("Expected %v for existing email, got %v", user.ErrIdentityEmailUsed, err)
+	}
+}
+
+func TestLinkIdentity(t *testing.T) {
+	pt := createUserIdentityTestRunner(t)
+	pt.testLinkIdentity()
+}
+
+func TestProvisionUser(t *testing.T) {
+	pt := createUserIdentityTestRunner(t)
+	pt.testProvisionUser()
+}
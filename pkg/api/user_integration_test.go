@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
 	"github.com/stashapp/stash-box/pkg/models"
 	"github.com/stashapp/stash-box/pkg/user"
 )
@@ -351,6 +352,45 @@ func (s *userTestRunner) testUserQuery() {
 	}
 }
 
+func (s *userTestRunner) testUserAPICalls() {
+	createdUser, err := s.createTestUser(nil, nil)
+	if err != nil {
+		return
+	}
+
+	const calls = 3
+	for i := 0; i < calls; i++ {
+		user.RecordAPICall(createdUser.ID, nil)
+	}
+	if err := user.FlushAPICalls(dbtest.Repo()); err != nil {
+		s.t.Errorf("Error flushing API calls: %s", err.Error())
+		return
+	}
+
+	userFilter := models.UserFilterType{
+		Name: &createdUser.Name,
+		APICalls: &models.IntCriterionInput{
+			Value:    calls,
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+
+	users, err := s.resolver.Query().QueryUsers(s.ctx, &userFilter, nil)
+	if err != nil {
+		s.t.Errorf("QueryUsers: got %v want %v", err, nil)
+		return
+	}
+
+	if len(users.Users) != 1 {
+		s.t.Errorf("QueryUsers: expected user with %d API calls", calls)
+		return
+	}
+
+	if users.Users[0].APICalls != calls {
+		s.fieldMismatch(calls, users.Users[0].APICalls, "APICalls")
+	}
+}
+
 func (s *userTestRunner) testUnauthorisedUserQuery() {
 	userName := userDB.admin.Name
 
@@ -489,6 +529,62 @@ func (s *userTestRunner) testUserEditQuery() {
 	// TODO: Test edits are returned
 }
 
+func (s *userTestRunner) testUserReputation() {
+	matchingEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	opposingEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	voter, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumVote})
+	if err != nil {
+		return
+	}
+	voterCtx := context.WithValue(s.ctx, user.ContextUser, voter)
+	for _, id := range []string{matchingEdit.ID.String(), opposingEdit.ID.String()} {
+		if _, err := s.resolver.Mutation().EditVote(voterCtx, models.EditVoteInput{
+			ID:   id,
+			Vote: models.VoteTypeEnumAccept,
+		}); err != nil {
+			s.t.Errorf("Error voting on edit: %s", err.Error())
+			return
+		}
+	}
+
+	if _, err := s.applyEdit(matchingEdit.ID.String()); err != nil {
+		return
+	}
+	// edits cancelled by an admin other than the submitter are rejected
+	admin, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumAdmin})
+	if err != nil {
+		return
+	}
+	adminCtx := context.WithValue(s.ctx, user.ContextUser, admin)
+	if _, err := s.resolver.Mutation().CancelEdit(adminCtx, models.CancelEditInput{ID: opposingEdit.ID.String()}); err != nil {
+		s.t.Errorf("Error rejecting edit: %s", err.Error())
+		return
+	}
+
+	reputation, err := s.resolver.User().Reputation(s.ctx, voter)
+	if err != nil {
+		s.t.Errorf("Error getting reputation: %s", err.Error())
+		return
+	}
+
+	if reputation.MatchingVotes != 1 {
+		s.fieldMismatch(1, reputation.MatchingVotes, "MatchingVotes")
+	}
+	if reputation.OpposingVotes != 1 {
+		s.fieldMismatch(1, reputation.OpposingVotes, "OpposingVotes")
+	}
+	if reputation.Score != 0.5 {
+		s.fieldMismatch(0.5, reputation.Score, "Score")
+	}
+}
+
 func TestCreateUser(t *testing.T) {
 	pt := createUserTestRunner(t)
 	pt.testCreateUser()
@@ -543,6 +639,11 @@ func TestUserQuery(t *testing.T) {
 	pt.testUserQuery()
 }
 
+func TestUserAPICalls(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testUserAPICalls()
+}
+
 func TestUnauthorisedUserQuery(t *testing.T) {
 	pt := &userTestRunner{
 		testRunner: *asModify(t),
@@ -564,3 +665,8 @@ func TestUserEditQuery(t *testing.T) {
 	pt := createUserTestRunner(t)
 	pt.testUserEditQuery()
 }
+
+func TestUserReputation(t *testing.T) {
+	pt := createUserTestRunner(t)
+	pt.testUserReputation()
+}
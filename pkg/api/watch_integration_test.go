@@ -0,0 +1,127 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type watchTestRunner struct {
+	testRunner
+}
+
+func createWatchTestRunner(t *testing.T) *watchTestRunner {
+	return &watchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *watchTestRunner) testWatchedActivity() {
+	watcher, err := s.createTestUser(nil, []models.RoleEnum{models.RoleEnumRead})
+	if err != nil {
+		return
+	}
+	watcherCtx := context.WithValue(s.ctx, user.ContextUser, watcher)
+
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	watchInput := models.WatchInput{
+		TargetType: models.TargetTypeEnumTag,
+		ID:         createdTag.ID,
+	}
+	if _, err := s.resolver.Mutation().Watch(watcherCtx, watchInput); err != nil {
+		s.t.Errorf("Error watching tag: %s", err.Error())
+		return
+	}
+
+	watchlist, err := s.resolver.User().Watchlist(watcherCtx, watcher)
+	if err != nil {
+		s.t.Errorf("Error finding watchlist: %s", err.Error())
+		return
+	}
+	if len(watchlist) != 1 || watchlist[0].TargetID.String() != createdTag.ID {
+		s.t.Errorf("Watchlist: expected tag %s, got %v", createdTag.ID, watchlist)
+		return
+	}
+
+	tagID := createdTag.ID
+	name := s.generateTagName()
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name}, &models.EditInput{
+		ID:        &tagID,
+		Operation: models.OperationEnumModify,
+	})
+	if err != nil {
+		return
+	}
+
+	title := s.generateSceneName()
+	createdScene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:  &title,
+		TagIds: []string{tagID},
+	})
+	if err != nil {
+		return
+	}
+
+	activity, err := s.resolver.Query().WatchedActivity(watcherCtx, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error finding watched activity: %s", err.Error())
+		return
+	}
+	if len(activity.Edits) != 1 || activity.Edits[0].ID != createdEdit.ID {
+		s.t.Errorf("WatchedActivity edits: expected edit %s, got %v", createdEdit.ID, activity.Edits)
+	}
+	if len(activity.Scenes) != 1 || activity.Scenes[0].ID.String() != createdScene.ID {
+		s.t.Errorf("WatchedActivity scenes: expected scene %s, got %v", createdScene.ID, activity.Scenes)
+	}
+
+	notifications, err := s.resolver.User().Notifications(watcherCtx, watcher, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error finding notifications: %s", err.Error())
+		return
+	}
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationTypeEnumWatchedEntityEdit.String() {
+		s.t.Errorf("Notifications: expected a watched entity edit, got %v", notifications)
+	}
+
+	if _, err := s.resolver.Mutation().Unwatch(watcherCtx, watchInput); err != nil {
+		s.t.Errorf("Error unwatching tag: %s", err.Error())
+		return
+	}
+
+	activity, err = s.resolver.Query().WatchedActivity(watcherCtx, nil, nil)
+	if err != nil {
+		s.t.Errorf("Error finding watched activity: %s", err.Error())
+		return
+	}
+	if len(activity.Edits) != 0 || len(activity.Scenes) != 0 {
+		s.t.Errorf("WatchedActivity: expected no activity after unwatching, got %d edits and %d scenes", len(activity.Edits), len(activity.Scenes))
+	}
+}
+
+func (s *watchTestRunner) testWatchMissingEntity() {
+	_, err := s.resolver.Mutation().Watch(s.ctx, models.WatchInput{
+		TargetType: models.TargetTypeEnumPerformer,
+		ID:         "00000000-0000-0000-0000-000000000001",
+	})
+	if err == nil {
+		s.t.Error("Expected error watching missing entity")
+	}
+}
+
+func TestWatchedActivity(t *testing.T) {
+	pt := createWatchTestRunner(t)
+	pt.testWatchedActivity()
+}
+
+func TestWatchMissingEntity(t *testing.T) {
+	pt := createWatchTestRunner(t)
+	pt.testWatchMissingEntity()
+}
@@ -0,0 +1,262 @@
+//go:build integration
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	dbtest "github.com/stashapp/stash-box/pkg/database/databasetest"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+type webhookTestRunner struct {
+	testRunner
+}
+
+func createWebhookTestRunner(t *testing.T) *webhookTestRunner {
+	return &webhookTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *webhookTestRunner) createTestWebhook(targetTypes []models.TargetTypeEnum, operations []models.OperationEnum) (*models.Webhook, error) {
+	s.t.Helper()
+	input := models.WebhookCreateInput{
+		URL:         "http://localhost/webhook",
+		Secret:      "secret",
+		TargetTypes: targetTypes,
+		Operations:  operations,
+	}
+
+	webhook, err := s.resolver.Mutation().WebhookCreate(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error creating webhook: %s", err.Error())
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookTestRunner) queryDeliveries(webhook *models.Webhook) []*models.WebhookDelivery {
+	s.t.Helper()
+	webhookID := webhook.ID.String()
+	result, err := s.resolver.Query().QueryWebhookDeliveries(s.ctx, &models.WebhookDeliveryFilterType{
+		WebhookID: &webhookID,
+	}, nil)
+	if err != nil {
+		s.t.Errorf("Error querying webhook deliveries: %s", err.Error())
+		return nil
+	}
+
+	return result.Deliveries
+}
+
+func (s *webhookTestRunner) testCreateWebhook() {
+	webhook, err := s.createTestWebhook([]models.TargetTypeEnum{models.TargetTypeEnumTag}, nil)
+	if err != nil {
+		return
+	}
+
+	r := s.resolver.Webhook()
+
+	if !webhook.Active {
+		s.t.Error("Expected created webhook to be active")
+	}
+
+	targetTypes, _ := r.TargetTypes(s.ctx, webhook)
+	if len(targetTypes) != 1 || targetTypes[0] != models.TargetTypeEnumTag {
+		s.fieldMismatch([]models.TargetTypeEnum{models.TargetTypeEnumTag}, targetTypes, "TargetTypes")
+	}
+
+	operations, _ := r.Operations(s.ctx, webhook)
+	if len(operations) != 0 {
+		s.fieldMismatch([]models.OperationEnum{}, operations, "Operations")
+	}
+
+	_, err = s.resolver.Mutation().WebhookCreate(s.ctx, models.WebhookCreateInput{
+		URL:    "ftp://localhost",
+		Secret: "secret",
+	})
+	if err == nil {
+		s.t.Error("Expected error creating webhook with invalid URL")
+	}
+}
+
+func (s *webhookTestRunner) testWebhookDelivery() {
+	webhook, err := s.createTestWebhook([]models.TargetTypeEnum{models.TargetTypeEnumTag}, []models.OperationEnum{models.OperationEnumCreate})
+	if err != nil {
+		return
+	}
+
+	tag, err := s.resolver.Mutation().TagCreate(s.ctx, models.TagCreateInput{
+		Name: s.generateTagName(),
+	})
+	if err != nil {
+		s.t.Errorf("Error creating tag: %s", err.Error())
+		return
+	}
+
+	// non-matching events should not be delivered
+	if _, err := s.createTestStudio(nil); err != nil {
+		return
+	}
+	if _, err := s.resolver.Mutation().TagUpdate(s.ctx, models.TagUpdateInput{ID: tag.ID.String()}); err != nil {
+		s.t.Errorf("Error updating tag: %s", err.Error())
+		return
+	}
+
+	deliveries := s.queryDeliveries(webhook)
+	if len(deliveries) != 1 {
+		s.t.Errorf("Expected 1 delivery, got %d", len(deliveries))
+		return
+	}
+
+	delivery := deliveries[0]
+	if delivery.TargetID != tag.ID {
+		s.fieldMismatch(tag.ID, delivery.TargetID, "TargetID")
+	}
+	if delivery.Operation != models.OperationEnumCreate.String() {
+		s.fieldMismatch(models.OperationEnumCreate.String(), delivery.Operation, "Operation")
+	}
+
+	redelivered, err := s.resolver.Mutation().WebhookRedeliver(s.ctx, models.WebhookRedeliverInput{ID: delivery.ID.String()})
+	if err != nil {
+		s.t.Errorf("Error redelivering webhook delivery: %s", err.Error())
+		return
+	}
+
+	if redelivered.Status != models.WebhookDeliveryStatusEnumPending.String() || redelivered.Attempts != 0 {
+		s.t.Errorf("Expected redelivered delivery to be pending with no attempts, got %s with %d attempts", redelivered.Status, redelivered.Attempts)
+	}
+}
+
+func (s *webhookTestRunner) claimDueDeliveries() map[string]bool {
+	s.t.Helper()
+	fac := dbtest.Repo()
+	var deliveries models.WebhookDeliveries
+	if err := fac.WithTxn(func() error {
+		var err error
+		deliveries, err = fac.Webhook().ClaimDueDeliveries(10000, time.Now().Add(time.Hour))
+		return err
+	}); err != nil {
+		s.t.Errorf("Error claiming deliveries: %s", err.Error())
+		return nil
+	}
+
+	ret := make(map[string]bool)
+	for _, d := range deliveries {
+		ret[d.ID.String()] = true
+	}
+	return ret
+}
+
+func (s *webhookTestRunner) testClaimDueDeliveries() {
+	webhook, err := s.createTestWebhook([]models.TargetTypeEnum{models.TargetTypeEnumTag}, []models.OperationEnum{models.OperationEnumCreate})
+	if err != nil {
+		return
+	}
+
+	if _, err := s.createTestTag(nil); err != nil {
+		return
+	}
+
+	deliveries := s.queryDeliveries(webhook)
+	if len(deliveries) != 1 {
+		s.t.Errorf("Expected 1 delivery, got %d", len(deliveries))
+		return
+	}
+	id := deliveries[0].ID.String()
+
+	if !s.claimDueDeliveries()[id] {
+		s.t.Errorf("Expected delivery %s to be claimed", id)
+		return
+	}
+
+	// a claimed delivery must not be returned again until the claim expires
+	if s.claimDueDeliveries()[id] {
+		s.t.Errorf("Expected claimed delivery %s not to be claimed again", id)
+	}
+}
+
+func (s *webhookTestRunner) testDestroyWebhook() {
+	webhook, err := s.createTestWebhook(nil, nil)
+	if err != nil {
+		return
+	}
+
+	destroyed, err := s.resolver.Mutation().WebhookDestroy(s.ctx, models.WebhookDestroyInput{
+		ID: webhook.ID.String(),
+	})
+	if err != nil {
+		s.t.Errorf("Error destroying webhook: %s", err.Error())
+		return
+	}
+
+	if !destroyed {
+		s.t.Error("Webhook was not destroyed")
+		return
+	}
+
+	found, err := s.resolver.Query().FindWebhook(s.ctx, webhook.ID.String())
+	if err != nil {
+		s.t.Errorf("Error finding webhook after destroying: %s", err.Error())
+		return
+	}
+
+	if found != nil {
+		s.t.Error("Found webhook after destruction")
+	}
+}
+
+func (s *webhookTestRunner) testUnauthorisedWebhook() {
+	// all webhook interfaces require admin
+	_, err := s.resolver.Mutation().WebhookCreate(s.ctx, models.WebhookCreateInput{})
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("WebhookCreate: got %v want %v", err, user.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Mutation().WebhookUpdate(s.ctx, models.WebhookUpdateInput{})
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("WebhookUpdate: got %v want %v", err, user.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Mutation().WebhookDestroy(s.ctx, models.WebhookDestroyInput{})
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("WebhookDestroy: got %v want %v", err, user.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Query().QueryWebhookDeliveries(s.ctx, nil, nil)
+	if err != user.ErrUnauthorized {
+		s.t.Errorf("QueryWebhookDeliveries: got %v want %v", err, user.ErrUnauthorized)
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	pt := createWebhookTestRunner(t)
+	pt.testCreateWebhook()
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	pt := createWebhookTestRunner(t)
+	pt.testWebhookDelivery()
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	pt := createWebhookTestRunner(t)
+	pt.testClaimDueDeliveries()
+}
+
+func TestDestroyWebhook(t *testing.T) {
+	pt := createWebhookTestRunner(t)
+	pt.testDestroyWebhook()
+}
+
+func TestUnauthorisedWebhook(t *testing.T) {
+	pt := &webhookTestRunner{
+		testRunner: *asModify(t),
+	}
+	pt.testUnauthorisedWebhook()
+}
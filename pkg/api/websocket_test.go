@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+)
+
+func TestCheckWebsocketOrigin(t *testing.T) {
+	allowedOrigins := config.C.WebsocketAllowedOrigins
+	config.C.WebsocketAllowedOrigins = []string{"https://app.example.com/"}
+	t.Cleanup(func() {
+		config.C.WebsocketAllowedOrigins = allowedOrigins
+	})
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true},
+		{"https://stashdb.org", true},
+		{"http://STASHDB.org", true},
+		{"https://app.example.com", true},
+		{"https://evil.example.com", false},
+		{"https://stashdb.org.evil.com", false},
+		{"null", false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "https://stashdb.org/graphql", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if allowed := checkWebsocketOrigin(r); allowed != tt.allowed {
+			t.Errorf("checkWebsocketOrigin(%q) = %v, expected %v", tt.origin, allowed, tt.allowed)
+		}
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-var appSchemaVersion uint = 18
+var appSchemaVersion uint = 35
 
 var databaseProviders map[string]databaseProvider
 
@@ -29,3 +29,9 @@ func registerProvider(name string, provider databaseProvider) {
 	}
 	databaseProviders[name] = provider
 }
+
+// GetSchemaVersion returns the schema version that the database is migrated to
+// on initialization.
+func GetSchemaVersion() uint {
+	return appSchemaVersion
+}
@@ -0,0 +1,45 @@
+CREATE TABLE "webhooks" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "url" TEXT NOT NULL,
+  "secret" TEXT NOT NULL,
+  "active" BOOLEAN NOT NULL DEFAULT TRUE,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL
+);
+
+CREATE TABLE "webhook_target_types" (
+  "webhook_id" UUID NOT NULL,
+  "target_type" TEXT NOT NULL,
+  PRIMARY KEY("webhook_id", "target_type"),
+  FOREIGN KEY("webhook_id") REFERENCES "webhooks"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "webhook_operations" (
+  "webhook_id" UUID NOT NULL,
+  "operation" TEXT NOT NULL,
+  PRIMARY KEY("webhook_id", "operation"),
+  FOREIGN KEY("webhook_id") REFERENCES "webhooks"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "webhook_id" UUID NOT NULL,
+  "target_type" TEXT NOT NULL,
+  "operation" TEXT NOT NULL,
+  "target_id" UUID NOT NULL,
+  "edit_id" UUID,
+  "payload" JSONB NOT NULL,
+  "status" TEXT NOT NULL,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "response_code" INTEGER,
+  "error" TEXT,
+  "next_attempt_at" TIMESTAMP NOT NULL,
+  "last_attempt_at" TIMESTAMP,
+  "created_at" TIMESTAMP NOT NULL,
+  FOREIGN KEY("webhook_id") REFERENCES "webhooks"("id") ON DELETE CASCADE,
+  FOREIGN KEY("edit_id") REFERENCES "edits"("id") ON DELETE SET NULL
+);
+
+CREATE INDEX "webhook_deliveries_pending_idx" ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'PENDING';
+CREATE INDEX "webhook_deliveries_webhook_id_idx" ON "webhook_deliveries" ("webhook_id", "created_at");
+CREATE INDEX "webhook_deliveries_target_id_idx" ON "webhook_deliveries" ("target_id");
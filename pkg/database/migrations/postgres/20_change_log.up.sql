@@ -0,0 +1,23 @@
+CREATE TABLE "change_log" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "target_type" TEXT NOT NULL,
+  "target_id" UUID NOT NULL,
+  "operation" TEXT NOT NULL,
+  "merged_into_id" UUID,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "change_log_created_at_idx" ON "change_log" ("created_at");
+
+-- seed the log with the existing entities so that clients can sync from the start
+INSERT INTO "change_log" ("target_type", "target_id", "operation", "created_at")
+SELECT "target_type", "id", 'CREATE', "created_at" FROM (
+  SELECT 'TAG' AS "target_type", "id", "created_at" FROM "tags" WHERE NOT "deleted"
+  UNION ALL
+  SELECT 'STUDIO', "id", "created_at" FROM "studios" WHERE NOT "deleted"
+  UNION ALL
+  SELECT 'PERFORMER', "id", "created_at" FROM "performers" WHERE NOT "deleted"
+  UNION ALL
+  SELECT 'SCENE', "id", "created_at" FROM "scenes" WHERE NOT "deleted"
+) AS "existing"
+ORDER BY "created_at";
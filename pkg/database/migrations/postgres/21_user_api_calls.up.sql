@@ -0,0 +1,8 @@
+CREATE TABLE "user_api_calls" (
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "bucket" TIMESTAMP NOT NULL,
+  "calls" INTEGER NOT NULL,
+  PRIMARY KEY ("user_id", "bucket")
+);
+
+CREATE INDEX "user_api_calls_bucket_idx" ON "user_api_calls" ("bucket");
@@ -0,0 +1,11 @@
+CREATE TABLE "api_keys" (
+  "id" UUID PRIMARY KEY,
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "name" TEXT NOT NULL,
+  "scope" TEXT NOT NULL,
+  "expires_at" TIMESTAMP,
+  "last_used_at" TIMESTAMP,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "api_keys_user_id_idx" ON "api_keys" ("user_id");
@@ -0,0 +1,9 @@
+CREATE TABLE "user_identities" (
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "issuer" TEXT NOT NULL,
+  "subject" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("issuer", "subject")
+);
+
+CREATE INDEX "user_identities_user_id_idx" ON "user_identities" ("user_id");
@@ -0,0 +1,10 @@
+ALTER TABLE "users"
+  ADD COLUMN "totp_secret" TEXT,
+  ADD COLUMN "totp_enabled" BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN "totp_last_step" BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE "user_recovery_codes" (
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "code_hash" TEXT NOT NULL,
+  PRIMARY KEY ("user_id", "code_hash")
+);
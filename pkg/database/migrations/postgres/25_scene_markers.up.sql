@@ -0,0 +1,21 @@
+CREATE TABLE "scene_markers" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "scene_id" UUID NOT NULL REFERENCES "scenes"("id") ON DELETE CASCADE,
+  "tag_id" UUID NOT NULL REFERENCES "tags"("id") ON DELETE CASCADE,
+  "title" TEXT,
+  "start_seconds" INTEGER NOT NULL DEFAULT 0 CHECK ("start_seconds" >= 0),
+  "end_seconds" INTEGER CHECK ("end_seconds" > "start_seconds"),
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "scene_markers_scene_id_idx" ON "scene_markers" ("scene_id");
+CREATE INDEX "scene_markers_tag_id_idx" ON "scene_markers" ("tag_id");
+
+CREATE TABLE "scene_marker_performers" (
+  "marker_id" UUID NOT NULL REFERENCES "scene_markers"("id") ON DELETE CASCADE,
+  "performer_id" UUID NOT NULL REFERENCES "performers"("id") ON DELETE CASCADE,
+  PRIMARY KEY ("marker_id", "performer_id")
+);
+
+CREATE INDEX "scene_marker_performers_performer_id_idx" ON "scene_marker_performers" ("performer_id");
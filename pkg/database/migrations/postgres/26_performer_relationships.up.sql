@@ -0,0 +1,12 @@
+-- Symmetric relationships are stored once, with the lower performer id first.
+-- For GROUP_MEMBER, performer_id is the group and related_performer_id the
+-- member.
+CREATE TABLE "performer_relationships" (
+  "performer_id" UUID NOT NULL REFERENCES "performers"("id") ON DELETE CASCADE,
+  "related_performer_id" UUID NOT NULL REFERENCES "performers"("id") ON DELETE CASCADE,
+  "type" VARCHAR(20) NOT NULL,
+  PRIMARY KEY ("performer_id", "related_performer_id", "type"),
+  CHECK ("performer_id" <> "related_performer_id")
+);
+
+CREATE INDEX "performer_relationships_related_performer_id_idx" ON "performer_relationships" ("related_performer_id");
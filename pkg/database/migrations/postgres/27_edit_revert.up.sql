@@ -0,0 +1,10 @@
+ALTER TABLE "edits" ADD COLUMN "reverts_id" UUID REFERENCES "edits"("id") ON DELETE SET NULL;
+CREATE INDEX "edits_reverts_id_idx" ON "edits" ("reverts_id");
+
+-- Join rows of the entities removed by an applied merge or destroy edit,
+-- recorded at apply time so that the edit can be reverted.
+CREATE TABLE "edit_snapshots" (
+  "edit_id" UUID NOT NULL PRIMARY KEY REFERENCES "edits"("id") ON DELETE CASCADE,
+  "data" JSONB NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
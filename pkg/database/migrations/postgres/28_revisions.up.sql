@@ -0,0 +1,14 @@
+-- Immutable record of the state of an entity after each change to it,
+-- whether made directly or by applying an edit.
+CREATE TABLE "revisions" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "target_type" TEXT NOT NULL,
+  "target_id" UUID NOT NULL,
+  "operation" TEXT NOT NULL,
+  "data" JSONB NOT NULL,
+  "user_id" UUID REFERENCES "users"("id") ON DELETE SET NULL,
+  "edit_id" UUID REFERENCES "edits"("id") ON DELETE SET NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "revisions_target_idx" ON "revisions" ("target_type", "target_id", "created_at");
@@ -0,0 +1,3 @@
+-- Set on pending edits whose old values no longer match the target after a
+-- conflicting edit has been applied.
+ALTER TABLE "edits" ADD COLUMN "stale" BOOLEAN NOT NULL DEFAULT FALSE;
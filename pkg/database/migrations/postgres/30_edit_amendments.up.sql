@@ -0,0 +1,12 @@
+-- Trail of the amendments made to pending edits by their submitter. Each
+-- row holds the edit data before and after the amendment.
+CREATE TABLE "edit_amendments" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "edit_id" UUID NOT NULL REFERENCES "edits"("id") ON DELETE CASCADE,
+  "user_id" UUID REFERENCES "users"("id") ON DELETE SET NULL,
+  "old_data" JSONB NOT NULL,
+  "data" JSONB NOT NULL,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "edit_amendments_edit_id_idx" ON "edit_amendments" ("edit_id", "created_at");
@@ -0,0 +1,24 @@
+-- Entities followed by users, whose edits are notified to them.
+CREATE TABLE "user_watches" (
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "target_type" TEXT NOT NULL,
+  "target_id" UUID NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("user_id", "target_type", "target_id")
+);
+
+CREATE INDEX "user_watches_target_idx" ON "user_watches" ("target_type", "target_id");
+
+CREATE TABLE "notifications" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "user_id" UUID NOT NULL REFERENCES "users"("id") ON DELETE CASCADE,
+  "type" TEXT NOT NULL,
+  "edit_id" UUID NOT NULL REFERENCES "edits"("id") ON DELETE CASCADE,
+  "read" BOOLEAN NOT NULL DEFAULT FALSE,
+  -- set once the notification has been included in an email digest
+  "emailed" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX "notifications_user_idx" ON "notifications" ("user_id", "created_at");
+CREATE INDEX "notifications_unemailed_idx" ON "notifications" ("user_id") WHERE NOT "read" AND NOT "emailed";
@@ -0,0 +1,14 @@
+CREATE TABLE "job_runs" (
+  "id" UUID NOT NULL PRIMARY KEY,
+  "job" TEXT NOT NULL,
+  "status" TEXT NOT NULL,
+  "progress" DOUBLE PRECISION,
+  "error" TEXT,
+  -- the admin that triggered the run, null for scheduled runs
+  "user_id" UUID,
+  "started_at" TIMESTAMP NOT NULL,
+  "finished_at" TIMESTAMP,
+  FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE SET NULL
+);
+
+CREATE INDEX "job_runs_job_idx" ON "job_runs" ("job", "started_at");
@@ -0,0 +1,9 @@
+-- Resized and re-encoded copies of stored images
+CREATE TABLE "image_variants" (
+  "image_id" UUID NOT NULL REFERENCES "images"("id") ON DELETE CASCADE,
+  "name" TEXT NOT NULL,
+  "format" TEXT NOT NULL,
+  "width" INTEGER NOT NULL,
+  "height" INTEGER NOT NULL,
+  PRIMARY KEY ("image_id", "name")
+);
@@ -0,0 +1,20 @@
+ALTER TABLE "images" ADD COLUMN "phash" BIGINT;
+
+-- Create phash index if bktree is available
+DO $$
+DECLARE
+  extension pg_extension%rowtype;
+BEGIN
+
+  SELECT *
+  INTO extension
+  FROM pg_extension
+  WHERE extname='bktree';
+
+  IF found THEN
+    CREATE INDEX images_phash_index
+    ON images
+    USING spgist (phash bktree_ops);
+  END IF;
+
+END$$;
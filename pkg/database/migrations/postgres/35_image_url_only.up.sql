@@ -0,0 +1,3 @@
+-- Images created from a URL without fetching have no checksum
+DROP INDEX "images_checksum_idx";
+CREATE UNIQUE INDEX "images_checksum_idx" ON "images" ("checksum") WHERE "checksum" <> '';
@@ -17,23 +17,26 @@ const (
 )
 
 type Loaders struct {
-	SceneFingerprintsByID  FingerprintsLoader
-	ImageByID              ImageLoader
-	PerformerByID          PerformerLoader
-	PerformerAliasesByID   StringsLoader
-	PerformerImageIDsByID  UUIDsLoader
-	PerformerMergeIDsByID  UUIDsLoader
-	PerformerPiercingsByID BodyModificationsLoader
-	PerformerTattoosByID   BodyModificationsLoader
-	PerformerUrlsByID      URLLoader
-	SceneImageIDsByID      UUIDsLoader
-	SceneAppearancesByID   SceneAppearancesLoader
-	SceneUrlsByID          URLLoader
-	StudioImageIDsByID     UUIDsLoader
-	StudioUrlsByID         URLLoader
-	SceneTagIDsByID        UUIDsLoader
-	TagByID                TagLoader
-	TagCategoryByID        TagCategoryLoader
+	SceneFingerprintsByID       FingerprintsLoader
+	ImageByID                   ImageLoader
+	PerformerByID               PerformerLoader
+	PerformerAliasesByID        StringsLoader
+	PerformerImageIDsByID       UUIDsLoader
+	PerformerMergeIDsByID       UUIDsLoader
+	PerformerPiercingsByID      BodyModificationsLoader
+	PerformerRelationsByID      PerformerRelationsLoader
+	PerformerTattoosByID        BodyModificationsLoader
+	PerformerUrlsByID           URLLoader
+	SceneImageIDsByID           UUIDsLoader
+	SceneAppearancesByID        SceneAppearancesLoader
+	SceneMarkersByID            SceneMarkersLoader
+	SceneMarkerPerformerIDsByID UUIDsLoader
+	SceneUrlsByID               URLLoader
+	StudioImageIDsByID          UUIDsLoader
+	StudioUrlsByID              URLLoader
+	SceneTagIDsByID             UUIDsLoader
+	TagByID                     TagLoader
+	TagCategoryByID             TagCategoryLoader
 }
 
 func Middleware(fac models.Repo) func(next http.Handler) http.Handler {
@@ -121,6 +124,14 @@ func GetLoaders(ctx context.Context, fac models.Repo) *Loaders {
 				return qb.GetAllPiercings(ids)
 			},
 		},
+		PerformerRelationsByID: PerformerRelationsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]*models.PerformerRelation, []error) {
+				qb := fac.Performer()
+				return qb.GetAllRelations(ids)
+			},
+		},
 		SceneAppearancesByID: SceneAppearancesLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
@@ -129,6 +140,22 @@ func GetLoaders(ctx context.Context, fac models.Repo) *Loaders {
 				return qb.GetAllAppearances(ids)
 			},
 		},
+		SceneMarkersByID: SceneMarkersLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]*models.SceneMarker, []error) {
+				qb := fac.Scene()
+				return qb.GetAllMarkers(ids)
+			},
+		},
+		SceneMarkerPerformerIDsByID: UUIDsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([][]uuid.UUID, []error) {
+				qb := fac.Scene()
+				return qb.GetAllMarkerPerformerIDs(ids)
+			},
+		},
 		SceneUrlsByID: URLLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
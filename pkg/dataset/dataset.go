@@ -0,0 +1,229 @@
+// Package dataset exports and imports the entity data of an instance as
+// JSON Lines files, one file per table.
+//
+// The first line of each file is a header recording the format version and
+// the database schema version it was exported from. Each subsequent line is
+// a single row. Datasets can only be imported into a database with the same
+// schema version.
+package dataset
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/database"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// FormatVersion is the version of the dataset file format.
+const FormatVersion = 1
+
+const fileExtension = ".jsonl"
+
+// imported fingerprints are assigned to the user created for fingerprints
+// submitted before submissions were tracked by user
+const fingerprintTable = "scene_fingerprints"
+const fingerprintUser = "_legacy_submissions"
+
+// maximum size of a single row
+const maxRowSize = 16 * 1024 * 1024
+
+type header struct {
+	Version       int    `json:"version"`
+	SchemaVersion uint   `json:"schema_version"`
+	Table         string `json:"table"`
+}
+
+func (h header) validate(table string) error {
+	if h.Version != FormatVersion {
+		return fmt.Errorf("unsupported dataset version %d, expected %d", h.Version, FormatVersion)
+	}
+	if h.SchemaVersion != database.GetSchemaVersion() {
+		return fmt.Errorf("dataset was exported from schema version %d, but database is at version %d", h.SchemaVersion, database.GetSchemaVersion())
+	}
+	if h.Table != table {
+		return fmt.Errorf("dataset file contains table %s, expected %s", h.Table, table)
+	}
+
+	return nil
+}
+
+func tablePath(dir string, table string) string {
+	return filepath.Join(dir, table+fileExtension)
+}
+
+// Export writes every table of the dataset to the provided directory,
+// creating it if necessary. Existing files are overwritten. All tables are
+// read from the same snapshot, so that rows written during the export do not
+// leave references to rows missing from the dataset.
+func Export(fac models.Repo, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return fac.WithTxn(func() error {
+		dqb := fac.Dataset()
+		if err := dqb.BeginSnapshot(); err != nil {
+			return err
+		}
+
+		for _, table := range dqb.Tables() {
+			count, err := exportTable(dqb, table, tablePath(dir, table))
+			if err != nil {
+				return fmt.Errorf("error exporting %s: %w", table, err)
+			}
+
+			logger.Infof("Exported %d rows from %s", count, table)
+		}
+
+		return nil
+	})
+}
+
+func exportTable(dqb models.DatasetRepo, table string, path string) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+
+	if err := encoder.Encode(header{
+		Version:       FormatVersion,
+		SchemaVersion: database.GetSchemaVersion(),
+		Table:         table,
+	}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	if err := dqb.ExportRows(table, func(row json.RawMessage) error {
+		count++
+		return encoder.Encode(row)
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+
+	return count, f.Close()
+}
+
+// Import restores a dataset written by Export from the provided directory.
+// The import is performed in a single transaction, and fails if any of the
+// rows already exist.
+func Import(fac models.Repo, dir string) error {
+	return fac.WithTxn(func() error {
+		dqb := fac.Dataset()
+
+		fingerprintUserID, err := findFingerprintUser(fac)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range dqb.Tables() {
+			importRow := func(row json.RawMessage) error {
+				return dqb.ImportRow(table, row)
+			}
+			if table == fingerprintTable {
+				importRow = func(row json.RawMessage) error {
+					row, err := setField(row, "user_id", fingerprintUserID)
+					if err != nil {
+						return err
+					}
+					return dqb.ImportRow(table, row)
+				}
+			}
+
+			count, err := readTable(table, tablePath(dir, table), importRow)
+			if err != nil {
+				return fmt.Errorf("error importing %s: %w", table, err)
+			}
+
+			logger.Infof("Imported %d rows into %s", count, table)
+		}
+
+		return nil
+	})
+}
+
+// readTable calls fn with each row of the dataset file, after validating
+// the header.
+func readTable(table string, path string, fn func(row json.RawMessage) error) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxRowSize)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("missing dataset header")
+	}
+
+	var h header
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+		return 0, fmt.Errorf("invalid dataset header: %w", err)
+	}
+	if err := h.validate(table); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		count++
+		if err := fn(json.RawMessage(line)); err != nil {
+			return 0, fmt.Errorf("line %d: %w", count+1, err)
+		}
+	}
+
+	return count, scanner.Err()
+}
+
+func findFingerprintUser(fac models.Repo) (uuid.UUID, error) {
+	u, err := fac.User().FindByName(fingerprintUser)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if u == nil {
+		return uuid.Nil, fmt.Errorf("user %s not found", fingerprintUser)
+	}
+
+	return u.ID, nil
+}
+
+func setField(row json.RawMessage, field string, value interface{}) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return nil, err
+	}
+
+	v, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields[field] = v
+	return json.Marshal(fields)
+}
@@ -0,0 +1,101 @@
+package dataset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/database"
+)
+
+func writeTestFile(t *testing.T, h header, rows ...string) string {
+	t.Helper()
+
+	headerLine, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := append([]string{string(headerLine)}, rows...)
+	path := filepath.Join(t.TempDir(), h.Table+fileExtension)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadTable(t *testing.T) {
+	rows := []string{
+		`{"id":"a","name":"first"}`,
+		`{"id":"b","name":"second"}`,
+	}
+	path := writeTestFile(t, header{
+		Version:       FormatVersion,
+		SchemaVersion: database.GetSchemaVersion(),
+		Table:         "tags",
+	}, rows...)
+
+	var got []string
+	count, err := readTable("tags", path, func(row json.RawMessage) error {
+		got = append(got, string(row))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readTable returned error: %s", err)
+	}
+
+	if count != len(rows) {
+		t.Errorf("count = %d, want %d", count, len(rows))
+	}
+	for i := range rows {
+		if got[i] != rows[i] {
+			t.Errorf("row %d = %s, want %s", i, got[i], rows[i])
+		}
+	}
+}
+
+func TestReadTableInvalidHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		h     header
+		table string
+	}{
+		{"version", header{Version: FormatVersion + 1, SchemaVersion: database.GetSchemaVersion(), Table: "tags"}, "tags"},
+		{"schema version", header{Version: FormatVersion, SchemaVersion: database.GetSchemaVersion() - 1, Table: "tags"}, "tags"},
+		{"table", header{Version: FormatVersion, SchemaVersion: database.GetSchemaVersion(), Table: "tags"}, "studios"},
+	}
+
+	for _, tt := range tests {
+		path := writeTestFile(t, tt.h, `{"id":"a"}`)
+		_, err := readTable(tt.table, path, func(row json.RawMessage) error {
+			t.Errorf("%s: unexpected row %s", tt.name, row)
+			return nil
+		})
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestSetField(t *testing.T) {
+	row, err := setField(json.RawMessage(`{"hash":"abc","submissions":12345678901}`), "user_id", "u")
+	if err != nil {
+		t.Fatalf("setField returned error: %s", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fields["user_id"]) != `"u"` {
+		t.Errorf("user_id = %s, want \"u\"", fields["user_id"])
+	}
+	// numbers should be preserved exactly
+	if string(fields["submissions"]) != "12345678901" {
+		t.Errorf("submissions = %s, want 12345678901", fields["submissions"])
+	}
+}
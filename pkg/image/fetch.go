@@ -0,0 +1,137 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+)
+
+// content types accepted from remote image URLs
+var fetchContentTypes = map[string]bool{
+	"image/jpeg":    true,
+	"image/png":     true,
+	"image/gif":     true,
+	"image/webp":    true,
+	"image/svg+xml": true,
+}
+
+// maximum number of redirects followed when fetching an image
+const maxFetchRedirects = 5
+
+var errFetchAddress = errors.New("fetching image: address not allowed")
+
+// fetchAddressAllowed reports whether images may be fetched from the IP.
+// Replaced in tests to allow local servers.
+var fetchAddressAllowed = isPublicIP
+
+// fetchImage downloads the image at url, within the configured size limit.
+func fetchImage(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFetchURL(u); err != nil {
+		return nil, err
+	}
+
+	return downloadImage(newFetchClient(config.GetImageFetchTimeout()), u.String(), config.GetImageFetchMaxSize())
+}
+
+// newFetchClient returns a client that refuses to connect to loopback,
+// private, link-local and unspecified addresses, so that users cannot make
+// the server request internal services.
+func newFetchClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// called with the resolved address of each connection
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !fetchAddressAllowed(net.ParseIP(host)) {
+				return errFetchAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: checkFetchRedirect,
+	}
+}
+
+func checkFetchRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxFetchRedirects {
+		return fmt.Errorf("fetching image: stopped after %d redirects", maxFetchRedirects)
+	}
+	return validateFetchURL(req.URL)
+}
+
+// validateFetchURL rejects URLs that are not http or https, or that have a
+// disallowed IP address as host. Host names are checked once resolved.
+func validateFetchURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("fetching image: unsupported scheme %q", u.Scheme)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !fetchAddressAllowed(ip) {
+		return errFetchAddress
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return ip != nil &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+func downloadImage(client *http.Client, url string, maxSize int64) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching image: unexpected status %s", resp.Status)
+	}
+
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !fetchContentTypes[contentType] {
+		return nil, fmt.Errorf("fetching image: unsupported content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("fetching image: size exceeds %d bytes", maxSize)
+	}
+
+	// the content length is not always set, so limit the read as well
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("fetching image: size exceeds %d bytes", maxSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,215 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newImageServer(t *testing.T, data []byte) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("/chunked.png", func(w http.ResponseWriter, r *http.Request) {
+		// flushing before writing everything omits the content length
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(data[:1])
+		w.(http.Flusher).Flush()
+		_, _ = w.Write(data[1:])
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+	})
+	mux.HandleFunc("/missing.png", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadImage(t *testing.T) {
+	data := testPNG(t, 10, 10)
+	server := newImageServer(t, data)
+	size := int64(len(data))
+
+	tests := []struct {
+		path    string
+		maxSize int64
+		wantErr bool
+	}{
+		{"/image.png", size, false},
+		{"/image.png", size - 1, true},
+		{"/chunked.png", size, false},
+		{"/chunked.png", size - 1, true},
+		{"/page.html", size, true},
+		{"/missing.png", size, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+strconv.FormatInt(tt.maxSize, 10), func(t *testing.T) {
+			got, err := downloadImage(server.Client(), server.URL+tt.path, tt.maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("downloadImage: %s", err.Error())
+			}
+			if !bytes.Equal(got, data) {
+				t.Error("downloaded data does not match")
+			}
+		})
+	}
+}
+
+func TestCreateFetchesURL(t *testing.T) {
+	data := testPNG(t, 40, 20)
+	server := newImageServer(t, data)
+
+	fetchRemoteImages := config.C.FetchRemoteImages
+	config.C.FetchRemoteImages = true
+	// the test server is local
+	fetchAddressAllowed = func(ip net.IP) bool { return true }
+	t.Cleanup(func() {
+		config.C.FetchRemoteImages = fetchRemoteImages
+		fetchAddressAllowed = isPublicIP
+	})
+
+	backend := &memoryBackend{files: make(map[string][]byte)}
+	repo := &memoryImageRepo{}
+	s := &Service{Repository: repo, Backend: backend}
+
+	url := server.URL + "/image.png"
+	img, err := s.Create(models.ImageCreateInput{URL: &url})
+	if err != nil {
+		t.Fatalf("Create: %s", err.Error())
+	}
+
+	if img.RemoteURL.String != url {
+		t.Errorf("RemoteURL: expected %s, got %s", url, img.RemoteURL.String)
+	}
+	if img.Width != 40 || img.Height != 20 {
+		t.Errorf("dimensions: expected 40x20, got %dx%d", img.Width, img.Height)
+	}
+	if !img.PHash.Valid {
+		t.Error("PHash: expected hash")
+	}
+	if !bytes.Equal(backend.files[img.Checksum], data) {
+		t.Error("stored file does not match")
+	}
+
+	// the same file from another URL is deduplicated
+	chunkedURL := server.URL + "/chunked.png"
+	existing, err := s.Create(models.ImageCreateInput{URL: &chunkedURL})
+	if err != nil {
+		t.Fatalf("Create: %s", err.Error())
+	}
+	if existing.ID != img.ID {
+		t.Errorf("expected existing image %s, got %s", img.ID, existing.ID)
+	}
+
+	badURL := server.URL + "/page.html"
+	if _, err := s.Create(models.ImageCreateInput{URL: &badURL}); err == nil {
+		t.Error("Create with non-image URL: expected error")
+	}
+}
+
+func TestFetchImageRejectsLocalAddresses(t *testing.T) {
+	server := newImageServer(t, testPNG(t, 10, 10))
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	urls := []string{
+		server.URL + "/image.png",
+		// resolved to a loopback address when connecting
+		"http://localhost:" + port + "/image.png",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/image.png",
+		"http://[::1]:" + port + "/image.png",
+		"http://0.0.0.0:" + port + "/image.png",
+	}
+	for _, u := range urls {
+		if _, err := fetchImage(u); !errors.Is(err, errFetchAddress) {
+			t.Errorf("fetchImage(%s): expected %v, got %v", u, errFetchAddress, err)
+		}
+	}
+
+	for _, u := range []string{"file:///etc/passwd", "ftp://example.com/image.png"} {
+		if _, err := fetchImage(u); err == nil {
+			t.Errorf("fetchImage(%s): expected error", u)
+		}
+	}
+}
+
+func TestFetchImageRedirects(t *testing.T) {
+	server := newImageServer(t, testPNG(t, 10, 10))
+
+	// allow the local server itself so that only the redirect checks apply
+	client := &http.Client{
+		Transport:     server.Client().Transport,
+		CheckRedirect: checkFetchRedirect,
+	}
+
+	redirect := func(to string) string {
+		return server.URL + "/redirect?to=" + url.QueryEscape(to)
+	}
+
+	if _, err := downloadImage(client, redirect(server.URL+"/image.png"), 1024); !errors.Is(err, errFetchAddress) {
+		t.Errorf("redirect to loopback address: expected %v, got %v", errFetchAddress, err)
+	}
+	if _, err := downloadImage(client, redirect("http://169.254.169.254/"), 1024); !errors.Is(err, errFetchAddress) {
+		t.Errorf("redirect to link-local address: expected %v, got %v", errFetchAddress, err)
+	}
+	if _, err := downloadImage(client, redirect("file:///etc/passwd"), 1024); err == nil {
+		t.Error("redirect to file URL: expected error")
+	}
+
+	fetchAddressAllowed = func(ip net.IP) bool { return true }
+	t.Cleanup(func() {
+		fetchAddressAllowed = isPublicIP
+	})
+
+	if _, err := downloadImage(client, redirect(server.URL+"/image.png"), 1024); err != nil {
+		t.Errorf("allowed redirect: %s", err.Error())
+	}
+
+	chain := server.URL + "/image.png"
+	for i := 0; i <= maxFetchRedirects; i++ {
+		chain = redirect(chain)
+	}
+	if _, err := downloadImage(client, chain, 1024); err == nil {
+		t.Errorf("more than %d redirects: expected error", maxFetchRedirects)
+	}
+}
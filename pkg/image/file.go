@@ -2,8 +2,11 @@ package image
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
@@ -40,3 +43,61 @@ func (s *FileBackend) WriteFile(file *bytes.Reader, image *models.Image) error {
 func (s *FileBackend) DestroyFile(image *models.Image) error {
 	return os.Remove(GetImagePath(config.GetImageLocation(), image.Checksum))
 }
+
+func (s *FileBackend) ReadFile(image *models.Image) (io.ReadCloser, error) {
+	return os.Open(GetImagePath(config.GetImageLocation(), image.Checksum))
+}
+
+func (s *FileBackend) WriteVariant(data []byte, image *models.Image, variant string) error {
+	path := GetVariantPath(config.GetImageLocation(), image.Checksum, variant)
+	if err := ioutil.WriteFile(path, data, os.FileMode(0644)); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileBackend) ReadVariant(image *models.Image, variant string) (io.ReadCloser, error) {
+	return os.Open(GetVariantPath(config.GetImageLocation(), image.Checksum, variant))
+}
+
+func (s *FileBackend) DestroyVariant(image *models.Image, variant string) error {
+	return os.Remove(GetVariantPath(config.GetImageLocation(), image.Checksum, variant))
+}
+
+func (s *FileBackend) Exists(image *models.Image, variant string) (bool, error) {
+	_, err := os.Stat(filepath.Join(config.GetImageLocation(), s.Key(image, variant)))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *FileBackend) Key(image *models.Image, variant string) string {
+	if variant == "" {
+		return image.Checksum
+	}
+	return variantFileName(image.Checksum, variant)
+}
+
+func (s *FileBackend) List(ctx context.Context, fn func(key string) error) error {
+	files, err := ioutil.ReadDir(config.GetImageLocation())
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		if err := fn(file.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
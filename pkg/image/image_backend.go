@@ -2,6 +2,8 @@ package image
 
 import (
 	"bytes"
+	"context"
+	"io"
 
 	"github.com/stashapp/stash-box/pkg/models"
 )
@@ -9,4 +11,19 @@ import (
 type Backend interface {
 	WriteFile(file *bytes.Reader, image *models.Image) error
 	DestroyFile(image *models.Image) error
+	// ReadFile returns the stored original of the image.
+	ReadFile(image *models.Image) (io.ReadCloser, error)
+	// WriteVariant stores the named variant of the image.
+	WriteVariant(data []byte, image *models.Image, variant string) error
+	// ReadVariant returns the stored named variant of the image.
+	ReadVariant(image *models.Image, variant string) (io.ReadCloser, error)
+	DestroyVariant(image *models.Image, variant string) error
+	// Exists returns true if the named variant of the image is stored, or
+	// the original if variant is empty.
+	Exists(image *models.Image, variant string) (bool, error)
+	// Key returns the key the named variant of the image, or the original if
+	// variant is empty, is stored under. Keys match those passed to List.
+	Key(image *models.Image, variant string) string
+	// List calls fn with the key of each stored file.
+	List(ctx context.Context, fn func(key string) error) error
 }
@@ -1,6 +1,8 @@
 package image
 
 import (
+	"context"
+
 	"github.com/gofrs/uuid"
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
@@ -11,20 +13,27 @@ type BackendService interface {
 	Destroy(input models.ImageDestroyInput) error
 	DestroyUnusedImages() error
 	DestroyUnusedImage(imageID uuid.UUID) error
+	GenerateVariants(image *models.Image) error
+	ComputePHash(image *models.Image) error
+	FetchRemote(image *models.Image) error
+	VerifyStorage(ctx context.Context, withTxn func(fn func() error) error) (*StorageReport, error)
 }
 
 func GetService(repo models.ImageRepo) BackendService {
-	imageBackend := config.GetImageBackend()
-
-	var backend Backend
-	if imageBackend == config.FileBackend {
-		backend = &FileBackend{}
-	} else if imageBackend == config.S3Backend {
-		backend = &S3Backend{}
-	}
-
 	return &Service{
 		Repository: repo,
-		Backend:    backend,
+		Backend:    GetBackend(),
+	}
+}
+
+// GetBackend returns the configured image backend, or nil if none is
+// configured.
+func GetBackend() Backend {
+	switch config.GetImageBackend() {
+	case config.FileBackend:
+		return &FileBackend{}
+	case config.S3Backend:
+		return &S3Backend{}
 	}
+	return nil
 }
@@ -0,0 +1,93 @@
+package image
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	phashSampleSize = 32
+	phashHashSize   = 8
+)
+
+// PerceptualHash returns a 64-bit DCT hash of the image. Visually similar
+// images, such as re-encodings or resizes of the same image, have hashes
+// within a small hamming distance of each other.
+func PerceptualHash(img image.Image) uint64 {
+	sample := imaging.Resize(imaging.Grayscale(img), phashSampleSize, phashSampleSize, imaging.Lanczos)
+
+	pixels := make([][]float64, phashSampleSize)
+	for y := range pixels {
+		pixels[y] = make([]float64, phashSampleSize)
+		for x := range pixels[y] {
+			// grayscale, so any channel will do
+			pixels[y][x] = float64(sample.Pix[y*sample.Stride+x*4])
+		}
+	}
+
+	// the lowest frequencies of the DCT describe the structure of the image
+	coefficients := dct2D(pixels)
+	var values []float64
+	for y := 0; y < phashHashSize; y++ {
+		values = append(values, coefficients[y][:phashHashSize]...)
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, v := range values {
+		if v > median {
+			hash |= 1 << uint(len(values)-1-i)
+		}
+	}
+	return hash
+}
+
+// HashDistance returns the number of bits that differ between the hashes.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// dct2D returns the type-II discrete cosine transform of the square matrix.
+func dct2D(input [][]float64) [][]float64 {
+	n := len(input)
+
+	rows := make([][]float64, n)
+	for y := range input {
+		rows[y] = dct1D(input[y])
+	}
+
+	output := make([][]float64, n)
+	for y := range output {
+		output[y] = make([]float64, n)
+	}
+	column := make([]float64, n)
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			column[y] = rows[y][x]
+		}
+		for y, v := range dct1D(column) {
+			output[y][x] = v
+		}
+	}
+	return output
+}
+
+func dct1D(input []float64) []float64 {
+	n := len(input)
+	output := make([]float64, n)
+	for k := range output {
+		var sum float64
+		for i, v := range input {
+			sum += v * math.Cos(math.Pi/float64(n)*(float64(i)+0.5)*float64(k))
+		}
+		output[k] = sum
+	}
+	return output
+}
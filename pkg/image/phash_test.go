@@ -0,0 +1,75 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+// testPattern draws shapes on a gradient, mirrored horizontally if mirror
+// is set.
+func testPattern(width, height int, mirror bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x)/float64(width), float64(y)/float64(height)
+			if mirror {
+				fx = 1 - fx
+			}
+			v := 40 + 80*fx + 40*fy
+			if math.Hypot(fx-0.3, fy-0.4) < 0.2 {
+				v = 230
+			}
+			if fx > 0.6 && fx < 0.9 && fy > 0.2 && fy < 0.8 {
+				v = 10
+			}
+			c := uint8(v)
+			img.Set(x, y, color.RGBA{R: c, G: c / 2, B: 255 - c, A: 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHash(t *testing.T) {
+	original := testPattern(400, 300, false)
+	hash := PerceptualHash(original)
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, imaging.Resize(original, 200, 150, imaging.Box), &jpeg.Options{Quality: 40}); err != nil {
+		t.Fatal(err)
+	}
+	reencoded, _, err := image.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := HashDistance(hash, PerceptualHash(reencoded)); d > 4 {
+		t.Errorf("re-encoded image: expected distance of at most 4, got %d", d)
+	}
+
+	if d := HashDistance(hash, PerceptualHash(testPattern(400, 300, true))); d < 16 {
+		t.Errorf("mirrored image: expected distance of at least 16, got %d", d)
+	}
+}
+
+func TestHashDistance(t *testing.T) {
+	tests := []struct {
+		a, b     uint64
+		expected int
+	}{
+		{0, 0, 0},
+		{0xff, 0x0f, 4},
+		{0, 0xffffffffffffffff, 64},
+	}
+
+	for _, tt := range tests {
+		if d := HashDistance(tt.a, tt.b); d != tt.expected {
+			t.Errorf("HashDistance(%x, %x) = %d, expected %d", tt.a, tt.b, d, tt.expected)
+		}
+	}
+}
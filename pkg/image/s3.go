@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"io"
 	"net/http"
 
 	"github.com/minio/minio-go/v7"
@@ -15,12 +16,13 @@ import (
 
 type S3Backend struct{}
 
+// s3Transport is used by S3 clients if set. Replaced in tests to connect to
+// a local server.
+var s3Transport http.RoundTripper
+
 func (s *S3Backend) WriteFile(file *bytes.Reader, image *models.Image) error {
 	s3config := config.GetS3Config()
-	minioClient, err := minio.New(s3config.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(s3config.AccessKey, s3config.Secret, ""),
-		Secure: true,
-	})
+	minioClient, err := newS3Client(s3config)
 	if err != nil {
 		return err
 	}
@@ -42,9 +44,7 @@ func (s *S3Backend) WriteFile(file *bytes.Reader, image *models.Image) error {
 			return err
 		}
 
-		hash := md5.Sum([]byte(image.ID.String() + "-resized"))
-		resizedID := hex.EncodeToString(hash[:])
-		if err := uploadS3File(*minioClient, resized, s3config.Bucket, resizedID); err != nil {
+		if err := uploadS3File(*minioClient, resized, s3config.Bucket, s3VariantID(image, "resized")); err != nil {
 			return err
 		}
 	}
@@ -54,31 +54,126 @@ func (s *S3Backend) WriteFile(file *bytes.Reader, image *models.Image) error {
 
 func (s *S3Backend) DestroyFile(image *models.Image) error {
 	s3config := config.GetS3Config()
-	minioClient, err := minio.New(s3config.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(s3config.AccessKey, s3config.Secret, ""),
-		Secure: true,
-	})
+	minioClient, err := newS3Client(s3config)
 	if err != nil {
 		return err
 	}
 
 	id := image.ID.String()
-	path := id[0:2] + "/" + id[2:4] + "/" + id
-	err = minioClient.RemoveObject(context.TODO(), s3config.Bucket, path, minio.RemoveObjectOptions{})
+	err = minioClient.RemoveObject(context.TODO(), s3config.Bucket, s3Path(id), minio.RemoveObjectOptions{})
 
 	if err != nil {
 		return err
 	}
 
-	hash := md5.Sum([]byte(id + "-resized"))
-	resizedID := hex.EncodeToString(hash[:])
-	path = resizedID[0:2] + "/" + resizedID[2:4] + "/" + resizedID
 	// Resized versions may or may not exist, so we attempt to delete and ignore the results
-	_ = minioClient.RemoveObject(context.TODO(), s3config.Bucket, path, minio.RemoveObjectOptions{})
+	_ = minioClient.RemoveObject(context.TODO(), s3config.Bucket, s3Path(s3VariantID(image, "resized")), minio.RemoveObjectOptions{})
+
+	return nil
+}
+
+func (s *S3Backend) ReadFile(image *models.Image) (io.ReadCloser, error) {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return nil, err
+	}
+
+	return minioClient.GetObject(context.TODO(), s3config.Bucket, s3Path(image.ID.String()), minio.GetObjectOptions{})
+}
+
+func (s *S3Backend) WriteVariant(data []byte, image *models.Image, variant string) error {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return err
+	}
+
+	return uploadS3File(*minioClient, data, s3config.Bucket, s3VariantID(image, variant))
+}
+
+func (s *S3Backend) ReadVariant(image *models.Image, variant string) (io.ReadCloser, error) {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return nil, err
+	}
+
+	return minioClient.GetObject(context.TODO(), s3config.Bucket, s3Path(s3VariantID(image, variant)), minio.GetObjectOptions{})
+}
+
+func (s *S3Backend) DestroyVariant(image *models.Image, variant string) error {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return err
+	}
+
+	return minioClient.RemoveObject(context.TODO(), s3config.Bucket, s3Path(s3VariantID(image, variant)), minio.RemoveObjectOptions{})
+}
+
+func (s *S3Backend) Exists(image *models.Image, variant string) (bool, error) {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = minioClient.StatObject(context.TODO(), s3config.Bucket, s.Key(image, variant), minio.StatObjectOptions{})
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *S3Backend) Key(image *models.Image, variant string) string {
+	if variant == "" {
+		return s3Path(image.ID.String())
+	}
+	return s3Path(s3VariantID(image, variant))
+}
+
+func (s *S3Backend) List(ctx context.Context, fn func(key string) error) error {
+	s3config := config.GetS3Config()
+	minioClient, err := newS3Client(s3config)
+	if err != nil {
+		return err
+	}
+
+	// stops the listing if fn fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for object := range minioClient.ListObjects(ctx, s3config.Bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		if err := fn(object.Key); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+func newS3Client(s3config *config.S3Config) (*minio.Client, error) {
+	return minio.New(s3config.Endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4(s3config.AccessKey, s3config.Secret, ""),
+		Secure:    true,
+		Transport: s3Transport,
+	})
+}
+
+// s3VariantID returns the object id of the named variant of the image.
+func s3VariantID(image *models.Image, variant string) string {
+	hash := md5.Sum([]byte(image.ID.String() + "-" + variant))
+	return hex.EncodeToString(hash[:])
+}
+
+func s3Path(id string) string {
+	return id[0:2] + "/" + id[2:4] + "/" + id
+}
+
 func uploadS3File(client minio.Client, file []byte, bucket string, id string) error {
 	ctx := context.TODO()
 
@@ -88,11 +183,10 @@ func uploadS3File(client minio.Client, file []byte, bucket string, id string) er
 		contentType = "image/svg+xml"
 	}
 
-	path := id[0:2] + "/" + id[2:4] + "/" + id
 	_, err := client.PutObject(
 		ctx,
 		bucket,
-		path,
+		s3Path(id),
 		bytes.NewReader(file),
 		int64(len(file)),
 		minio.PutObjectOptions{
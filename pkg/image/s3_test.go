@@ -0,0 +1,179 @@
+package image
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type s3ListResult struct {
+	XMLName     xml.Name `xml:"ListBucketResult"`
+	Name        string
+	KeyCount    int
+	MaxKeys     int
+	IsTruncated bool
+	Contents    []s3ListObject
+}
+
+type s3ListObject struct {
+	Key          string
+	Size         int
+	ETag         string
+	LastModified string
+}
+
+// newS3Server returns a stand-in for an S3 server, storing the given keys in
+// the bucket, that answers the requests used by S3Backend.Exists and List.
+func newS3Server(t *testing.T, bucket string, keys []string) *httptest.Server {
+	t.Helper()
+	stored := make(map[string]bool)
+	for _, key := range keys {
+		stored[key] = true
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/"+bucket)
+		query := r.URL.Query()
+
+		switch {
+		case path == "/" && query.Has("location"):
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+		case path == "/" && r.Method == http.MethodGet:
+			result := s3ListResult{Name: bucket, MaxKeys: 1000}
+			for _, key := range keys {
+				result.Contents = append(result.Contents, s3ListObject{
+					Key:          key,
+					Size:         1,
+					ETag:         `"00000000000000000000000000000000"`,
+					LastModified: "2021-01-01T00:00:00.000Z",
+				})
+			}
+			result.KeyCount = len(result.Contents)
+			w.Header().Set("Content-Type", "application/xml")
+			_ = xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodHead:
+			if !stored[strings.TrimPrefix(path, "/")] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"00000000000000000000000000000000"`)
+			w.Header().Set("Last-Modified", "Fri, 01 Jan 2021 00:00:00 GMT")
+			w.Header().Set("Content-Length", "1")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func useS3Server(t *testing.T, server *httptest.Server, bucket string) {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s3config := *config.GetS3Config()
+	config.C.S3.S3Config = config.S3Config{
+		Endpoint:  u.Host,
+		Bucket:    bucket,
+		AccessKey: "access",
+		Secret:    "secret",
+	}
+	s3Transport = server.Client().Transport
+	t.Cleanup(func() {
+		config.C.S3.S3Config = s3config
+		s3Transport = nil
+	})
+}
+
+func TestS3BackendExists(t *testing.T) {
+	backend := &S3Backend{}
+	stored := &models.Image{ID: uuid.Must(uuid.NewV4())}
+	missing := &models.Image{ID: uuid.Must(uuid.NewV4())}
+
+	server := newS3Server(t, "images", []string{
+		backend.Key(stored, ""),
+		backend.Key(stored, "thumb"),
+	})
+	useS3Server(t, server, "images")
+
+	tests := []struct {
+		image    *models.Image
+		variant  string
+		expected bool
+	}{
+		{stored, "", true},
+		{stored, "thumb", true},
+		{stored, "full", false},
+		{missing, "", false},
+	}
+
+	for _, tt := range tests {
+		exists, err := backend.Exists(tt.image, tt.variant)
+		if err != nil {
+			t.Errorf("Exists(%s, %q): %s", tt.image.ID, tt.variant, err.Error())
+			continue
+		}
+		if exists != tt.expected {
+			t.Errorf("Exists(%s, %q) = %v, expected %v", tt.image.ID, tt.variant, exists, tt.expected)
+		}
+	}
+}
+
+func TestS3BackendKey(t *testing.T) {
+	backend := &S3Backend{}
+	img := &models.Image{ID: uuid.FromStringOrNil("0123abcd-0000-0000-0000-000000000000")}
+
+	if key := backend.Key(img, ""); key != "01/23/0123abcd-0000-0000-0000-000000000000" {
+		t.Errorf("original key: got %s", key)
+	}
+
+	variantID := s3VariantID(img, "thumb")
+	if key := backend.Key(img, "thumb"); key != variantID[0:2]+"/"+variantID[2:4]+"/"+variantID {
+		t.Errorf("variant key: got %s", key)
+	}
+	if backend.Key(img, "thumb") == backend.Key(img, "full") {
+		t.Error("variants have the same key")
+	}
+}
+
+func TestS3BackendList(t *testing.T) {
+	backend := &S3Backend{}
+	img := &models.Image{ID: uuid.Must(uuid.NewV4())}
+	keys := []string{
+		backend.Key(img, ""),
+		backend.Key(img, "thumb"),
+		"ab/cd/orphaned",
+	}
+
+	server := newS3Server(t, "images", keys)
+	useS3Server(t, server, "images")
+
+	var listed []string
+	if err := backend.List(context.Background(), func(key string) error {
+		listed = append(listed, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("List: %s", err.Error())
+	}
+
+	sort.Strings(keys)
+	sort.Strings(listed)
+	if strings.Join(listed, ",") != strings.Join(keys, ",") {
+		t.Errorf("List: expected %v, got %v", keys, listed)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
@@ -45,14 +47,23 @@ func (s *Service) Create(input models.ImageCreateInput) (*models.Image, error) {
 	}
 
 	// handle image upload
+	var fileData []byte
 	if input.File != nil {
-		file := make([]byte, input.File.Size)
-		if _, err := input.File.File.Read(file); err != nil {
+		fileData = make([]byte, input.File.Size)
+		if _, err := input.File.File.Read(fileData); err != nil {
 			return nil, err
 		}
-		fileReader := bytes.NewReader(file)
+	} else if input.URL == nil {
+		return nil, errors.New("Missing URL or file")
+	} else if config.GetFetchRemoteImages() {
+		fileData, err = fetchImage(*input.URL)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-		checksum, err := calculateChecksum(fileReader)
+	if fileData != nil {
+		checksum, err := calculateChecksum(bytes.NewReader(fileData))
 		if err != nil {
 			return nil, err
 		}
@@ -71,29 +82,109 @@ func (s *Service) Create(input models.ImageCreateInput) (*models.Image, error) {
 		// set the checksum in the new image
 		newImage.Checksum = checksum
 
-		if _, err = fileReader.Seek(0, 0); err != nil {
-			return nil, err
-		}
-		if err := populateImageDimensions(fileReader, &newImage); err != nil {
+		if err := s.storeFile(fileData, &newImage); err != nil {
 			return nil, err
 		}
+	}
 
-		if _, err = fileReader.Seek(0, 0); err != nil {
+	image, err := s.Repository.Create(newImage)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileData != nil {
+		if err := s.generateNewVariants(fileData, image); err != nil {
 			return nil, err
 		}
-		if err := s.Backend.WriteFile(fileReader, &newImage); err != nil {
-			return nil, err
+	}
+
+	return image, nil
+}
+
+// FetchRemote downloads and stores an image that only has a URL. An error
+// is returned if the downloaded file is already stored as another image.
+func (s *Service) FetchRemote(image *models.Image) error {
+	if image.Checksum != "" || !image.RemoteURL.Valid {
+		return nil
+	}
+
+	fileData, err := fetchImage(image.RemoteURL.String)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := calculateChecksum(bytes.NewReader(fileData))
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.Repository.FindByChecksum(checksum)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("image is already stored as image %s", existing.ID)
+	}
+
+	image.Checksum = checksum
+	if err := s.storeFile(fileData, image); err != nil {
+		return err
+	}
+
+	if _, err := s.Repository.Update(*image); err != nil {
+		return err
+	}
+
+	return s.generateNewVariants(fileData, image)
+}
+
+// storeFile populates the dimensions and hash of the image from the file
+// and writes the file to the backend.
+func (s *Service) storeFile(fileData []byte, image *models.Image) error {
+	if err := populateImageDimensions(bytes.NewReader(fileData), image); err != nil {
+		return err
+	}
+
+	if image.Width > 0 {
+		if err := populateImagePHash(bytes.NewReader(fileData), image); err != nil {
+			return err
 		}
-	} else if input.URL != nil {
-		return nil, errors.New("Missing URL or file")
 	}
 
-	image, err := s.Repository.Create(newImage)
+	return s.Backend.WriteFile(bytes.NewReader(fileData), image)
+}
+
+// generateNewVariants generates the variants of a newly stored image from
+// its file rather than reading it back from the backend.
+func (s *Service) generateNewVariants(fileData []byte, image *models.Image) error {
+	variants := config.GetImageVariants()
+	if len(variants) == 0 || image.Width <= 0 {
+		return nil
+	}
+
+	return s.generateVariants(bytes.NewReader(fileData), image, variants)
+}
+
+// ComputePHash calculates and stores the perceptual hash of a stored image
+// from its original. SVG images and images without a stored file are
+// skipped.
+func (s *Service) ComputePHash(image *models.Image) error {
+	if s.Backend == nil || image.Checksum == "" || image.Width <= 0 {
+		return nil
+	}
+
+	file, err := s.Backend.ReadFile(image)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer file.Close()
 
-	return image, nil
+	if err := populateImagePHash(file, image); err != nil {
+		return err
+	}
+
+	_, err = s.Repository.Update(*image)
+	return err
 }
 
 func (s *Service) Destroy(input models.ImageDestroyInput) error {
@@ -109,12 +200,20 @@ func (s *Service) Destroy(input models.ImageDestroyInput) error {
 		return err
 	}
 
+	variants, err := s.Repository.FindVariants(imageID)
+	if err != nil {
+		return err
+	}
+
 	if err = s.Repository.Destroy(imageID); err != nil {
 		return err
 	}
 
-	// delete the file. Suppress any error
+	// delete the files. Suppress any error
 	_ = s.Backend.DestroyFile(image)
+	for _, variant := range variants {
+		_ = s.Backend.DestroyVariant(image, variant.Name)
+	}
 
 	return nil
 }
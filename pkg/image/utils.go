@@ -3,6 +3,7 @@ package image
 import (
 	"bytes"
 	"crypto/md5"
+	"database/sql"
 	"encoding/hex"
 	"image"
 	_ "image/gif"
@@ -45,6 +46,20 @@ func populateImageDimensions(imgReader *bytes.Reader, dest *models.Image) error
 	return nil
 }
 
+func populateImagePHash(imgReader io.Reader, dest *models.Image) error {
+	img, _, err := image.Decode(imgReader)
+	if err != nil {
+		return err
+	}
+
+	dest.PHash = sql.NullInt64{
+		Int64: int64(PerceptualHash(img)),
+		Valid: true,
+	}
+
+	return nil
+}
+
 func resizeImage(srcReader io.Reader, maxDimension int64) ([]byte, error) {
 	var resizedImage image.Image
 	srcImage, _, err := image.Decode(srcReader)
@@ -80,3 +95,13 @@ func calculateChecksum(file io.Reader) (string, error) {
 func GetImagePath(imageDir string, checksum string) string {
 	return filepath.Join(imageDir, checksum)
 }
+
+// GetVariantPath returns the path of the named variant of the image with
+// the given checksum.
+func GetVariantPath(imageDir string, checksum string, variant string) string {
+	return filepath.Join(imageDir, variantFileName(checksum, variant))
+}
+
+func variantFileName(checksum string, variant string) string {
+	return checksum + "-" + variant
+}
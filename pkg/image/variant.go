@@ -0,0 +1,180 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sync"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// Encoder writes img to w. quality is in the range 1-100, or zero for the
+// encoder default.
+type Encoder func(w io.Writer, img image.Image, quality int) error
+
+var (
+	encodersMutex sync.RWMutex
+	encoders      = map[string]Encoder{
+		"jpeg": encodeJPEG,
+		"png":  encodePNG,
+	}
+)
+
+// RegisterEncoder makes an encoder available for variants of the given
+// format, replacing any existing encoder for the format.
+func RegisterEncoder(format string, encoder Encoder) {
+	encodersMutex.Lock()
+	defer encodersMutex.Unlock()
+	encoders[format] = encoder
+}
+
+func getEncoder(format string) Encoder {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+	return encoders[format]
+}
+
+// HasEncoder returns true if variants of the given format can be encoded.
+func HasEncoder(format string) bool {
+	return getEncoder(format) != nil
+}
+
+// ValidateVariants returns an error if a variant uses a format without an
+// encoder.
+func ValidateVariants(variants []config.ImageVariantConfig) error {
+	for _, variant := range variants {
+		if !HasEncoder(variant.Format) {
+			return fmt.Errorf("image variant %s: no encoder for image format %s", variant.Name, variant.Format)
+		}
+	}
+	return nil
+}
+
+func encodeJPEG(w io.Writer, img image.Image, quality int) error {
+	var options *jpeg.Options
+	if quality > 0 {
+		options = &jpeg.Options{Quality: quality}
+	}
+	return jpeg.Encode(w, img, options)
+}
+
+func encodePNG(w io.Writer, img image.Image, quality int) error {
+	return png.Encode(w, img)
+}
+
+// fitDimensions returns the dimensions of an image of width by height
+// scaled down to fit within maxDimension, keeping the aspect ratio. Images
+// are never scaled up.
+func fitDimensions(width, height, maxDimension int) (int, int) {
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return width, height
+	}
+
+	if height > width {
+		scaled := width * maxDimension / height
+		if scaled < 1 {
+			scaled = 1
+		}
+		return scaled, maxDimension
+	}
+
+	scaled := height * maxDimension / width
+	if scaled < 1 {
+		scaled = 1
+	}
+	return maxDimension, scaled
+}
+
+// encodeVariant resizes and encodes src according to the variant config.
+func encodeVariant(src image.Image, variant config.ImageVariantConfig) ([]byte, *models.ImageVariant, error) {
+	encoder := getEncoder(variant.Format)
+	if encoder == nil {
+		return nil, nil, fmt.Errorf("no encoder for image format %s", variant.Format)
+	}
+
+	dim := src.Bounds().Size()
+	width, height := fitDimensions(dim.X, dim.Y, int(variant.MaxDimension))
+
+	resized := src
+	if width != dim.X || height != dim.Y {
+		resized = imaging.Resize(src, width, height, imaging.Lanczos)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := encoder(buf, resized, variant.Quality); err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), &models.ImageVariant{
+		Name:   variant.Name,
+		Format: variant.Format,
+		Width:  int64(width),
+		Height: int64(height),
+	}, nil
+}
+
+// GenerateVariants creates the configured variants of a stored image from
+// its original. SVG images and images without a stored file are skipped.
+func (s *Service) GenerateVariants(img *models.Image) error {
+	variants := config.GetImageVariants()
+	if len(variants) == 0 || s.Backend == nil || img.Checksum == "" || img.Width <= 0 {
+		return nil
+	}
+
+	file, err := s.Backend.ReadFile(img)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.generateVariants(file, img, variants)
+}
+
+func (s *Service) generateVariants(file io.Reader, img *models.Image, variants []config.ImageVariantConfig) error {
+	src, _, err := image.Decode(file)
+	if err != nil {
+		return err
+	}
+
+	// files are written after their rows, so that a failure rolls back the
+	// rows and only the files written so far need to be removed
+	var written []string
+	for _, variant := range variants {
+		data, newVariant, err := encodeVariant(src, variant)
+		if err != nil {
+			// a misconfigured variant should not prevent the others
+			logger.Warnf("Skipping image variant %s: %s", variant.Name, err.Error())
+			continue
+		}
+
+		newVariant.ImageID = img.ID
+		if err := s.Repository.CreateVariant(*newVariant); err != nil {
+			s.destroyVariants(img, written)
+			return err
+		}
+
+		if err := s.Backend.WriteVariant(data, img, variant.Name); err != nil {
+			s.destroyVariants(img, written)
+			return err
+		}
+		written = append(written, variant.Name)
+	}
+
+	return nil
+}
+
+func (s *Service) destroyVariants(img *models.Image, variants []string) {
+	for _, variant := range variants {
+		if err := s.Backend.DestroyVariant(img, variant); err != nil {
+			logger.Warnf("Failed to remove variant %s of image %s: %s", variant, img.ID.String(), err.Error())
+		}
+	}
+}
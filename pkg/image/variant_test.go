@@ -0,0 +1,215 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"sort"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type memoryBackend struct {
+	Backend
+	files    map[string][]byte
+	variants map[string][]byte
+	// variant that fails to be written
+	failVariant string
+}
+
+func (b *memoryBackend) WriteFile(file *bytes.Reader, image *models.Image) error {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(file); err != nil {
+		return err
+	}
+	b.files[image.Checksum] = buf.Bytes()
+	return nil
+}
+
+func (b *memoryBackend) WriteVariant(data []byte, image *models.Image, variant string) error {
+	if variant == b.failVariant {
+		return errors.New("write failed")
+	}
+	b.variants[variant] = data
+	return nil
+}
+
+func (b *memoryBackend) DestroyVariant(image *models.Image, variant string) error {
+	delete(b.variants, variant)
+	return nil
+}
+
+type memoryImageRepo struct {
+	models.ImageRepo
+	images   models.Images
+	variants models.ImageVariants
+}
+
+func (r *memoryImageRepo) Create(newImage models.Image) (*models.Image, error) {
+	r.images = append(r.images, &newImage)
+	return &newImage, nil
+}
+
+func (r *memoryImageRepo) FindByChecksum(checksum string) (*models.Image, error) {
+	for _, image := range r.images {
+		if image.Checksum == checksum {
+			return image, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryImageRepo) FindStored(afterID uuid.UUID, limit int) ([]*models.Image, error) {
+	var ret []*models.Image
+	for _, image := range r.images {
+		if image.Checksum != "" && bytes.Compare(image.ID.Bytes(), afterID.Bytes()) > 0 {
+			ret = append(ret, image)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i].ID.Bytes(), ret[j].ID.Bytes()) < 0
+	})
+	if len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret, nil
+}
+
+func (r *memoryImageRepo) FindVariantsByImageIDs(imageIDs []uuid.UUID) (models.ImageVariants, error) {
+	var ret models.ImageVariants
+	for _, variant := range r.variants {
+		for _, id := range imageIDs {
+			if variant.ImageID == id {
+				ret = append(ret, variant)
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (r *memoryImageRepo) CreateVariant(newVariant models.ImageVariant) error {
+	r.variants = append(r.variants, &newVariant)
+	return nil
+}
+
+func TestFitDimensions(t *testing.T) {
+	tests := []struct {
+		width, height, maxDimension   int
+		expectedWidth, expectedHeight int
+	}{
+		{1000, 500, 0, 1000, 500},
+		{1000, 500, 2000, 1000, 500},
+		{1000, 500, 100, 100, 50},
+		{500, 1000, 100, 50, 100},
+		{1000, 1000, 100, 100, 100},
+		{1000, 1, 100, 100, 1},
+	}
+
+	for _, tt := range tests {
+		width, height := fitDimensions(tt.width, tt.height, tt.maxDimension)
+		if width != tt.expectedWidth || height != tt.expectedHeight {
+			t.Errorf("fitDimensions(%d, %d, %d) = %dx%d, expected %dx%d", tt.width, tt.height, tt.maxDimension, width, height, tt.expectedWidth, tt.expectedHeight)
+		}
+	}
+}
+
+func TestGenerateVariants(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &memoryBackend{variants: make(map[string][]byte)}
+	repo := &memoryImageRepo{}
+	s := &Service{Repository: repo, Backend: backend}
+
+	img := &models.Image{ID: uuid.Must(uuid.NewV4()), Width: 400, Height: 200}
+	variants := []config.ImageVariantConfig{
+		{Name: "thumb", MaxDimension: 100, Format: "jpeg", Quality: 80},
+		{Name: "full", Format: "png"},
+		{Name: "unknown", MaxDimension: 100, Format: "bmp"},
+	}
+	if err := s.generateVariants(buf, img, variants); err != nil {
+		t.Fatalf("generateVariants: %s", err.Error())
+	}
+
+	expected := map[string]struct {
+		format        string
+		width, height int
+	}{
+		"thumb": {"jpeg", 100, 50},
+		"full":  {"png", 400, 200},
+	}
+
+	if len(repo.variants) != len(expected) {
+		t.Fatalf("expected %d variants, got %d", len(expected), len(repo.variants))
+	}
+	for _, variant := range repo.variants {
+		e, found := expected[variant.Name]
+		if !found {
+			t.Errorf("unexpected variant %s", variant.Name)
+			continue
+		}
+		if variant.ImageID != img.ID {
+			t.Errorf("%s: ImageID: expected %s, got %s", variant.Name, img.ID, variant.ImageID)
+		}
+
+		decoded, format, err := image.Decode(bytes.NewReader(backend.variants[variant.Name]))
+		if err != nil {
+			t.Errorf("%s: decoding stored variant: %s", variant.Name, err.Error())
+			continue
+		}
+		size := decoded.Bounds().Size()
+		if format != e.format || size.X != e.width || size.Y != e.height {
+			t.Errorf("%s: expected %s %dx%d, got %s %dx%d", variant.Name, e.format, e.width, e.height, format, size.X, size.Y)
+		}
+		if variant.Width != int64(size.X) || variant.Height != int64(size.Y) {
+			t.Errorf("%s: recorded %dx%d, stored %dx%d", variant.Name, variant.Width, variant.Height, size.X, size.Y)
+		}
+	}
+}
+
+func TestGenerateVariantsCleansUpOnError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &memoryBackend{variants: make(map[string][]byte), failVariant: "full"}
+	repo := &memoryImageRepo{}
+	s := &Service{Repository: repo, Backend: backend}
+
+	img := &models.Image{ID: uuid.Must(uuid.NewV4()), Width: 400, Height: 200}
+	variants := []config.ImageVariantConfig{
+		{Name: "thumb", MaxDimension: 100, Format: "jpeg"},
+		{Name: "full", Format: "png"},
+	}
+	if err := s.generateVariants(buf, img, variants); err == nil {
+		t.Fatal("generateVariants: expected error")
+	}
+
+	if len(backend.variants) != 0 {
+		t.Errorf("expected written variants to be removed, got %d", len(backend.variants))
+	}
+}
+
+func TestValidateVariants(t *testing.T) {
+	valid := []config.ImageVariantConfig{
+		{Name: "thumb", Format: "jpeg"},
+		{Name: "lossless", Format: "png"},
+	}
+	if err := ValidateVariants(valid); err != nil {
+		t.Errorf("expected variants to be valid, got %s", err.Error())
+	}
+
+	invalid := append(valid, config.ImageVariantConfig{Name: "modern", Format: "avif"})
+	if err := ValidateVariants(invalid); err == nil {
+		t.Error("expected error for variant without an encoder")
+	}
+}
@@ -0,0 +1,146 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const verifyBatchSize = 1000
+
+// legacy resized copy written by the S3 backend when s3.max_dimension is set
+const s3ResizedVariant = "resized"
+
+var ErrNoBackend = errors.New("no image backend configured")
+
+// StorageReport lists the differences between the stored files and the
+// images table.
+type StorageReport struct {
+	// Keys of files of images that are not stored
+	Missing []string
+	// Keys of stored files that do not belong to an image
+	Orphaned []string
+	// Images whose stored original does not match their checksum
+	Mismatched []uuid.UUID
+}
+
+func (r *StorageReport) HasProblems() bool {
+	return len(r.Missing) > 0 || len(r.Orphaned) > 0 || len(r.Mismatched) > 0
+}
+
+func (r *StorageReport) String() string {
+	return fmt.Sprintf("%d missing files, %d orphaned files, %d checksum mismatches", len(r.Missing), len(r.Orphaned), len(r.Mismatched))
+}
+
+// VerifyStorage checks that the original and variants of each stored image
+// exist and that the originals match their checksums, then lists stored
+// files that do not belong to any image. Each batch of images is read in its
+// own transaction using withTxn, and the files are checked outside of it.
+func (s *Service) VerifyStorage(ctx context.Context, withTxn func(fn func() error) error) (*StorageReport, error) {
+	if s.Backend == nil {
+		return nil, ErrNoBackend
+	}
+
+	report := &StorageReport{}
+	expected := make(map[string]bool)
+
+	lastID := uuid.Nil
+	for {
+		var images []*models.Image
+		var variants models.ImageVariants
+		if err := withTxn(func() error {
+			var err error
+			images, err = s.Repository.FindStored(lastID, verifyBatchSize)
+			if err != nil || len(images) == 0 {
+				return err
+			}
+
+			var ids []uuid.UUID
+			for _, image := range images {
+				ids = append(ids, image.ID)
+			}
+			variants, err = s.Repository.FindVariantsByImageIDs(ids)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		if len(images) == 0 {
+			break
+		}
+
+		imageVariants := make(map[uuid.UUID][]string)
+		for _, variant := range variants {
+			imageVariants[variant.ImageID] = append(imageVariants[variant.ImageID], variant.Name)
+		}
+
+		for _, image := range images {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			if err := s.verifyImage(image, imageVariants[image.ID], report); err != nil {
+				return nil, err
+			}
+
+			expected[s.Backend.Key(image, "")] = true
+			expected[s.Backend.Key(image, s3ResizedVariant)] = true
+			for _, variant := range imageVariants[image.ID] {
+				expected[s.Backend.Key(image, variant)] = true
+			}
+		}
+
+		lastID = images[len(images)-1].ID
+	}
+
+	if err := s.Backend.List(ctx, func(key string) error {
+		if !expected[key] {
+			report.Orphaned = append(report.Orphaned, key)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *Service) verifyImage(image *models.Image, variants []string, report *StorageReport) error {
+	exists, err := s.Backend.Exists(image, "")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		report.Missing = append(report.Missing, s.Backend.Key(image, ""))
+	} else {
+		file, err := s.Backend.ReadFile(image)
+		if err != nil {
+			return err
+		}
+		checksum, err := calculateChecksum(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+
+		if checksum != image.Checksum {
+			report.Mismatched = append(report.Mismatched, image.ID)
+		}
+	}
+
+	for _, variant := range variants {
+		exists, err := s.Backend.Exists(image, variant)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			report.Missing = append(report.Missing, s.Backend.Key(image, variant))
+		}
+	}
+
+	return nil
+}
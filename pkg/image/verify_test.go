@@ -0,0 +1,85 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestVerifyStorage(t *testing.T) {
+	imageLocation := config.C.ImageLocation
+	config.C.ImageLocation = t.TempDir()
+	t.Cleanup(func() {
+		config.C.ImageLocation = imageLocation
+	})
+
+	backend := &FileBackend{}
+	repo := &memoryImageRepo{}
+	s := &Service{Repository: repo, Backend: backend}
+
+	// stores an image with a thumb variant, returning the image
+	store := func(width int) *models.Image {
+		data := testPNG(t, width, 10)
+		checksum, err := calculateChecksum(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		image := &models.Image{ID: uuid.Must(uuid.NewV4()), Checksum: checksum}
+		if err := backend.WriteFile(bytes.NewReader(data), image); err != nil {
+			t.Fatal(err)
+		}
+		if err := backend.WriteVariant(data, image, "thumb"); err != nil {
+			t.Fatal(err)
+		}
+
+		repo.images = append(repo.images, image)
+		repo.variants = append(repo.variants, &models.ImageVariant{ImageID: image.ID, Name: "thumb"})
+		return image
+	}
+
+	store(10)
+	missing := store(20)
+	corrupted := store(30)
+	missingVariant := store(40)
+
+	if err := backend.DestroyFile(missing); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.DestroyVariant(missingVariant, "thumb"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetImagePath(config.C.ImageLocation, corrupted.Checksum), []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(config.C.ImageLocation, "orphan"), []byte("orphan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.VerifyStorage(context.Background(), func(fn func() error) error { return fn() })
+	if err != nil {
+		t.Fatalf("VerifyStorage: %s", err.Error())
+	}
+
+	expectedMissing := []string{missing.Checksum, variantFileName(missingVariant.Checksum, "thumb")}
+	sort.Strings(expectedMissing)
+	sort.Strings(report.Missing)
+	if !reflect.DeepEqual(report.Missing, expectedMissing) {
+		t.Errorf("Missing: expected %v, got %v", expectedMissing, report.Missing)
+	}
+	if !reflect.DeepEqual(report.Orphaned, []string{"orphan"}) {
+		t.Errorf("Orphaned: expected [orphan], got %v", report.Orphaned)
+	}
+	if len(report.Mismatched) != 1 || report.Mismatched[0] != corrupted.ID {
+		t.Errorf("Mismatched: expected [%s], got %v", corrupted.ID, report.Mismatched)
+	}
+}
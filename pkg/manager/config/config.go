@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -19,6 +20,66 @@ type S3Config struct {
 	MaxDimension int64  `mapstructure:"max_dimension"`
 }
 
+// ImageVariantConfig describes a resized and re-encoded copy of uploaded
+// images, requested by name.
+type ImageVariantConfig struct {
+	Name string `mapstructure:"name"`
+	// Images are scaled down to fit within this size. Zero keeps the
+	// original dimensions
+	MaxDimension int64 `mapstructure:"max_dimension"`
+	// Encoding of the variant. Defaults to jpeg
+	Format string `mapstructure:"format"`
+	// Encoding quality from 1 to 100, for formats that support it
+	Quality int `mapstructure:"quality"`
+}
+
+type OIDCConfig struct {
+	// Issuer URL of the OpenID Connect provider. OIDC login is disabled if
+	// not set
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Scopes       []string `mapstructure:"scopes"`
+	// ID token claim used as the username of provisioned users
+	UsernameClaim string `mapstructure:"username_claim"`
+	// Create users with the default user roles for unlinked identities
+	AutoProvision bool `mapstructure:"auto_provision"`
+	// Log in users with two-factor authentication enabled without asking
+	// for a code
+	SkipTOTP bool `mapstructure:"skip_totp"`
+}
+
+// VotingPolicyConfig overrides the voting rules for edits of a target type
+// and operation. An empty target type or operation matches all. Unset
+// values fall back to the global voting settings.
+type VotingPolicyConfig struct {
+	TargetType string `mapstructure:"target_type"`
+	Operation  string `mapstructure:"operation"`
+	// Duration, in seconds, before votes may close the edit
+	MinVotingPeriod *int `mapstructure:"min_voting_period"`
+	// Number of unanimous votes required for immediate approval
+	VoteApplicationThreshold *int `mapstructure:"vote_application_threshold"`
+	// Vote count required for approval at the end of the voting period
+	ApprovalThreshold *int `mapstructure:"approval_threshold"`
+	// Number of distinct users that must have voted for or against the edit
+	// for it to be approved
+	Quorum *int `mapstructure:"quorum"`
+	// Roles allowed to cast immediate accept and reject votes
+	ImmediateVoteRoles []string `mapstructure:"immediate_vote_roles"`
+}
+
+// VotingPolicy holds the voting rules applying to edits of a target type
+// and operation.
+type VotingPolicy struct {
+	TargetType               string
+	Operation                string
+	MinVotingPeriod          int
+	VoteApplicationThreshold int
+	ApprovalThreshold        int
+	Quorum                   int
+	ImmediateVoteRoles       []string
+}
+
 type config struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
@@ -27,6 +88,10 @@ type config struct {
 	HTTPUpgrade  bool `mapstructure:"http_upgrade"`
 	IsProduction bool `mapstructure:"is_production"`
 
+	// Origins, other than the server's own, allowed to open websocket
+	// connections
+	WebsocketAllowedOrigins []string `mapstructure:"websocket_allowed_origins"`
+
 	// Key used to sign JWT tokens
 	JWTSignKey string `mapstructure:"jwt_secret_key"`
 	// Key used for session store
@@ -38,6 +103,9 @@ type config struct {
 	ActivationExpiry  int      `mapstructure:"activation_expiry"`
 	EmailCooldown     int      `mapstructure:"email_cooldown"`
 	DefaultUserRoles  []string `mapstructure:"default_user_roles"`
+	// Roles that are only granted to users with two-factor authentication
+	// enabled
+	RequireTOTPRoles []string `mapstructure:"require_totp_roles"`
 
 	// Number of approved edits before user automatically gets VOTE role
 	VotePromotionThreshold int `mapstructure:"vote_promotion_threshold"`
@@ -49,6 +117,12 @@ type config struct {
 	MinDestructiveVotingPeriod int `mapstructure:"min_destructive_voting_period"`
 	// Interval between checks for completed voting periods
 	VoteCronInterval string `mapstructure:"vote_cron_interval"`
+	// Schedules of background jobs by job name, overriding their defaults
+	JobSchedules map[string]string `mapstructure:"job_schedules"`
+	// Weigh votes by the reputation of the voter when tallying them
+	WeightedVoting bool `mapstructure:"weighted_voting"`
+	// Per target type and operation overrides of the voting settings
+	VotingPolicies []VotingPolicyConfig `mapstructure:"voting_policies"`
 
 	// Email settings
 	EmailHost string `mapstructure:"email_host"`
@@ -57,10 +131,21 @@ type config struct {
 	EmailPW   string `mapstructure:"email_password"`
 	EmailFrom string `mapstructure:"email_from"`
 	HostURL   string `mapstructure:"host_url"`
+	// Interval between email digests of unread notifications. Digests are
+	// not sent if empty
+	NotificationDigestInterval string `mapstructure:"notification_digest_interval"`
 
 	// Image storage settings
 	ImageLocation string `mapstructure:"image_location"`
 	ImageBackend  string `mapstructure:"image_backend"`
+	// Resized copies generated for uploaded images
+	ImageVariants []ImageVariantConfig `mapstructure:"image_variants"`
+	// Download and store images created from a URL
+	FetchRemoteImages bool `mapstructure:"fetch_remote_images"`
+	// Maximum size, in bytes, of fetched images
+	ImageFetchMaxSize int64 `mapstructure:"image_fetch_max_size"`
+	// Time, in seconds, to wait for a fetched image
+	ImageFetchTimeout int `mapstructure:"image_fetch_timeout"`
 
 	// Logging options
 	LogFile     string `mapstructure:"logFile"`
@@ -72,8 +157,29 @@ type config struct {
 		S3Config `mapstructure:",squash"`
 	}
 
+	OIDC struct {
+		OIDCConfig `mapstructure:",squash"`
+	}
+
 	PHashDistance int `mapstructure:"phash_distance"`
 
+	// Webhook settings
+	// Number of delivery attempts before a webhook delivery is failed
+	WebhookMaxAttempts int `mapstructure:"webhook_max_attempts"`
+	// Timeout, in seconds, for webhook requests
+	WebhookTimeout int `mapstructure:"webhook_timeout"`
+	// Interval between checks for webhook deliveries due for retry
+	WebhookRetryInterval string `mapstructure:"webhook_retry_interval"`
+
+	// API key rate limiting
+	// Number of requests per minute allowed for each API key. 0 is unlimited
+	APIRateLimit int `mapstructure:"api_rate_limit"`
+	// Per role overrides of the API rate limit. Users get the highest limit
+	// of their roles
+	APIRateLimitRoles map[string]int `mapstructure:"api_rate_limit_roles"`
+	// Duration, in seconds, of the period that API calls are counted over
+	APICallWindow int `mapstructure:"api_call_window"`
+
 	Title string `mapstructure:"title"`
 }
 
@@ -89,6 +195,7 @@ const (
 )
 
 var defaultUserRoles = []string{"READ", "VOTE", "EDIT"}
+var defaultImmediateVoteRoles = []string{"ADMIN"}
 var C = &config{
 	RequireInvite:              true,
 	RequireActivation:          true,
@@ -96,12 +203,18 @@ var C = &config{
 	EmailCooldown:              5 * 60,
 	EmailPort:                  25,
 	ImageBackend:               string(FileBackend),
+	ImageFetchMaxSize:          10 * 1024 * 1024,
+	ImageFetchTimeout:          30,
 	PHashDistance:              0,
 	VoteApplicationThreshold:   3,
 	VotePromotionThreshold:     10,
 	VoteCronInterval:           "5m",
 	VotingPeriod:               345600,
 	MinDestructiveVotingPeriod: 172800,
+	WebhookMaxAttempts:         8,
+	WebhookTimeout:             10,
+	WebhookRetryInterval:       "1m",
+	APICallWindow:              24 * 60 * 60,
 }
 
 func GetDatabasePath() string {
@@ -173,6 +286,12 @@ func GetDefaultUserRoles() []string {
 	return C.DefaultUserRoles
 }
 
+// GetRequireTOTPRoles returns the roles that are only granted to users with
+// two-factor authentication enabled.
+func GetRequireTOTPRoles() []string {
+	return C.RequireTOTPRoles
+}
+
 func GetEmailHost() string {
 	return C.EmailHost
 }
@@ -197,6 +316,10 @@ func GetHostURL() string {
 	return C.HostURL
 }
 
+func GetWebsocketAllowedOrigins() []string {
+	return C.WebsocketAllowedOrigins
+}
+
 // GetImageLocation returns the path of where to locally store images.
 func GetImageLocation() string {
 	return C.ImageLocation
@@ -207,10 +330,53 @@ func GetImageBackend() ImageBackendType {
 	return ImageBackendType(C.ImageBackend)
 }
 
+// GetImageVariants returns the variants generated for uploaded images.
+func GetImageVariants() []ImageVariantConfig {
+	var ret []ImageVariantConfig
+	for _, v := range C.ImageVariants {
+		if v.Format == "" {
+			v.Format = "jpeg"
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// GetFetchRemoteImages returns true if images created from a URL should be
+// downloaded and stored.
+func GetFetchRemoteImages() bool {
+	return C.FetchRemoteImages
+}
+
+func GetImageFetchMaxSize() int64 {
+	return C.ImageFetchMaxSize
+}
+
+func GetImageFetchTimeout() time.Duration {
+	return time.Duration(C.ImageFetchTimeout * int(time.Second))
+}
+
 func GetS3Config() *S3Config {
 	return &C.S3.S3Config
 }
 
+// GetOIDCConfig returns the OpenID Connect login settings, or nil if OIDC
+// login is not configured.
+func GetOIDCConfig() *OIDCConfig {
+	if C.OIDC.Issuer == "" {
+		return nil
+	}
+
+	ret := C.OIDC.OIDCConfig
+	if len(ret.Scopes) == 0 {
+		ret.Scopes = []string{"openid", "profile", "email"}
+	}
+	if ret.UsernameClaim == "" {
+		ret.UsernameClaim = "preferred_username"
+	}
+	return &ret
+}
+
 // ValidateImageLocation returns an error is image_location is not set.
 func ValidateImageLocation() error {
 	if C.ImageLocation == "" {
@@ -327,10 +493,130 @@ func GetMinDestructiveVotingPeriod() int {
 	return C.MinDestructiveVotingPeriod
 }
 
+// GetVotingPolicy returns the voting rules for edits of the target type and
+// operation. The most specific configured policy matching both applies,
+// with unset values taken from less specific policies, then from the global
+// voting settings.
+func GetVotingPolicy(targetType string, operation string) VotingPolicy {
+	ret := VotingPolicy{
+		TargetType:               targetType,
+		Operation:                operation,
+		VoteApplicationThreshold: C.VoteApplicationThreshold,
+		ImmediateVoteRoles:       defaultImmediateVoteRoles,
+	}
+	if operation == "DESTROY" || operation == "MERGE" {
+		// destructive edits stay open for a minimum period, and require at
+		// least +1 votes to pass
+		ret.MinVotingPeriod = C.MinDestructiveVotingPeriod
+		ret.ApprovalThreshold = 1
+	}
+
+	// apply matching policies from least to most specific
+	for specificity := 0; specificity <= 3; specificity++ {
+		for _, p := range C.VotingPolicies {
+			if !policyMatches(p, targetType, operation, specificity) {
+				continue
+			}
+
+			if p.MinVotingPeriod != nil {
+				ret.MinVotingPeriod = *p.MinVotingPeriod
+			}
+			if p.VoteApplicationThreshold != nil {
+				ret.VoteApplicationThreshold = *p.VoteApplicationThreshold
+			}
+			if p.ApprovalThreshold != nil {
+				ret.ApprovalThreshold = *p.ApprovalThreshold
+			}
+			if p.Quorum != nil {
+				ret.Quorum = *p.Quorum
+			}
+			if p.ImmediateVoteRoles != nil {
+				ret.ImmediateVoteRoles = p.ImmediateVoteRoles
+			}
+		}
+	}
+
+	return ret
+}
+
+// policyMatches returns true if the policy applies to the target type and
+// operation, and has the given specificity: 0 for policies matching all
+// edits, 1 for the target type only, 2 for the operation only and 3 for
+// both.
+func policyMatches(p VotingPolicyConfig, targetType string, operation string, specificity int) bool {
+	matchesTarget := p.TargetType == "" || strings.EqualFold(p.TargetType, targetType)
+	matchesOperation := p.Operation == "" || strings.EqualFold(p.Operation, operation)
+	if !matchesTarget || !matchesOperation {
+		return false
+	}
+
+	s := 0
+	if p.TargetType != "" {
+		s++
+	}
+	if p.Operation != "" {
+		s += 2
+	}
+	return s == specificity
+}
+
+// GetWeightedVoting returns true if votes are weighted by the reputation of
+// the voter.
+func GetWeightedVoting() bool {
+	return C.WeightedVoting
+}
+
+// GetNotificationDigestInterval returns the interval between email digests of
+// unread notifications.
+func GetNotificationDigestInterval() string {
+	return C.NotificationDigestInterval
+}
+
 func GetVoteCronInterval() string {
 	return C.VoteCronInterval
 }
 
+// GetJobSchedule returns the configured schedule of the named job, or the
+// provided default if it is not configured. An empty schedule disables
+// scheduled runs of the job.
+func GetJobSchedule(name string, defaultSchedule string) string {
+	if schedule, found := C.JobSchedules[name]; found {
+		return schedule
+	}
+	return defaultSchedule
+}
+
+func GetWebhookMaxAttempts() int {
+	return C.WebhookMaxAttempts
+}
+
+func GetWebhookTimeout() time.Duration {
+	return time.Duration(C.WebhookTimeout * int(time.Second))
+}
+
+func GetWebhookRetryInterval() string {
+	return C.WebhookRetryInterval
+}
+
+func GetAPIRateLimit() int {
+	return C.APIRateLimit
+}
+
+// GetAPIRateLimitRoles returns the per role API rate limits, keyed by
+// upper case role name.
+func GetAPIRateLimitRoles() map[string]int {
+	ret := make(map[string]int)
+	for role, limit := range C.APIRateLimitRoles {
+		// viper lowercases map keys
+		ret[strings.ToUpper(role)] = limit
+	}
+	return ret
+}
+
+func GetAPICallWindow() time.Duration {
+	return time.Duration(C.APICallWindow * int(time.Second))
+}
+
 func GetTitle() string {
 	if C.Title == "" {
 		return "Stash-Box"
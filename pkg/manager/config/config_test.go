@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetVotingPolicy(t *testing.T) {
+	original := *C
+	defer func() { *C = original }()
+
+	intPtr := func(v int) *int { return &v }
+
+	C.VoteApplicationThreshold = 3
+	C.MinDestructiveVotingPeriod = 100
+	C.VotingPolicies = []VotingPolicyConfig{
+		{TargetType: "SCENE", Operation: "DESTROY", Quorum: intPtr(5)},
+		{Operation: "destroy", ApprovalThreshold: intPtr(2), Quorum: intPtr(3)},
+		{TargetType: "SCENE", MinVotingPeriod: intPtr(50), ImmediateVoteRoles: []string{"MODIFY"}},
+		{VoteApplicationThreshold: intPtr(4)},
+	}
+
+	tests := []struct {
+		name       string
+		targetType string
+		operation  string
+		want       VotingPolicy
+	}{
+		{
+			"global",
+			"TAG", "MODIFY",
+			VotingPolicy{TargetType: "TAG", Operation: "MODIFY", VoteApplicationThreshold: 4, ImmediateVoteRoles: []string{"ADMIN"}},
+		},
+		{
+			"destructive defaults",
+			"TAG", "MERGE",
+			VotingPolicy{TargetType: "TAG", Operation: "MERGE", MinVotingPeriod: 100, VoteApplicationThreshold: 4, ApprovalThreshold: 1, ImmediateVoteRoles: []string{"ADMIN"}},
+		},
+		{
+			"operation",
+			"TAG", "DESTROY",
+			VotingPolicy{TargetType: "TAG", Operation: "DESTROY", MinVotingPeriod: 100, VoteApplicationThreshold: 4, ApprovalThreshold: 2, Quorum: 3, ImmediateVoteRoles: []string{"ADMIN"}},
+		},
+		{
+			"target type and operation",
+			"SCENE", "DESTROY",
+			VotingPolicy{TargetType: "SCENE", Operation: "DESTROY", MinVotingPeriod: 50, VoteApplicationThreshold: 4, ApprovalThreshold: 2, Quorum: 5, ImmediateVoteRoles: []string{"MODIFY"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetVotingPolicy(tt.targetType, tt.operation)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetVotingPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJobSchedule(t *testing.T) {
+	original := *C
+	defer func() { *C = original }()
+
+	C.JobSchedules = map[string]string{
+		"configured": "2h",
+		"disabled":   "",
+	}
+
+	tests := []struct {
+		name string
+		job  string
+		want string
+	}{
+		{"configured", "configured", "2h"},
+		{"disabled", "disabled", ""},
+		{"default", "other", "1h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetJobSchedule(tt.job, "1h"); got != tt.want {
+				t.Errorf("GetJobSchedule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
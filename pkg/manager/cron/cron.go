@@ -2,69 +2,73 @@ package cron
 
 import (
 	"github.com/robfig/cron/v3"
-	"golang.org/x/sync/semaphore"
 
-	"github.com/stashapp/stash-box/pkg/api"
 	"github.com/stashapp/stash-box/pkg/logger"
-	"github.com/stashapp/stash-box/pkg/manager/config"
-	"github.com/stashapp/stash-box/pkg/manager/edit"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
 )
 
-var sem = semaphore.NewWeighted(1)
+const apiCallFlushInterval = "1m"
 
-type EditCron struct {
-	rfp api.RepoProvider
+type RepoProvider interface {
+	Repo() models.Repo
 }
 
-// processEdits runs at set intervals and closes edits where the voting period has ended,
-// either by applying the edit if enough positive votes have been cast, or by rejecting it.
-func (c EditCron) processEdits() {
-	if !sem.TryAcquire(1) {
-		logger.Debug("Edit cronjob failed to start, already running.")
-	}
-	defer sem.Release(1)
+type APICallCron struct {
+	rfp RepoProvider
+}
 
-	edits, err := c.rfp.Repo().Edit().FindCompletedEdits(config.GetVotingPeriod(), config.GetMinDestructiveVotingPeriod(), config.GetVoteApplicationThreshold())
-	if err != nil {
-		logger.Errorf("Edit cronjob failed to fetch completed edits: %s", err.Error())
-		return
+// flushAPICalls writes the API calls counted in memory to the database.
+// This is bookkeeping rather than a job, so runs are not recorded.
+func (c APICallCron) flushAPICalls() {
+	if err := user.FlushAPICalls(c.rfp.Repo()); err != nil {
+		logger.Errorf("API call cronjob failed to flush API calls: %s", err.Error())
 	}
+}
 
-	logger.Debugf("Edit cronjob running for %d edits", len(edits))
-	for _, e := range edits {
-		if err := c.rfp.Repo().WithTxn(func() error {
-			voteThreshold := 0
-			if e.Operation == models.OperationEnumDestroy.String() || e.Operation == models.OperationEnumMerge.String() {
-				// Require at least +1 votes to pass destructive edits
-				voteThreshold = 1
-			}
+// Init registers the built-in jobs and starts running all registered jobs
+// at their schedules.
+func Init(rfp RepoProvider) {
+	jobs.mutex.Lock()
+	jobs.rfp = rfp
+	jobs.mutex.Unlock()
 
-			var err error
-			if e.VoteCount >= voteThreshold {
-				_, err = edit.ApplyEdit(c.rfp.Repo(), e.ID, false)
-			} else {
-				_, err = edit.CloseEdit(c.rfp.Repo(), e.ID, models.VoteStatusEnumRejected)
-			}
-			return err
-		}); err != nil {
-			logger.Errorf("Edit cronjob failed to apply edit %s: %s", e.ID.String(), err.Error())
-		}
+	// runs of a previous process can no longer finish
+	fac := rfp.Repo()
+	if err := fac.WithTxn(func() error {
+		return fac.Job().FailRunning("interrupted by server shutdown")
+	}); err != nil {
+		logger.Errorf("Failed to fail interrupted job runs: %s", err.Error())
 	}
-}
 
-func Init(rfp api.RepoProvider) {
+	registerBuiltinJobs()
+
 	c := cron.New()
-	editCron := EditCron{rfp}
+	for _, job := range Jobs() {
+		if job.Schedule == "" {
+			continue
+		}
 
-	interval := config.GetVoteCronInterval()
-	if interval != "" {
-		_, err := c.AddFunc("@every "+config.GetVoteCronInterval(), editCron.processEdits)
+		job := job
+		_, err := c.AddFunc("@every "+job.Schedule, func() {
+			_, err := jobs.start(job, nil)
+			if err == ErrJobRunning {
+				logger.Debugf("Job %s failed to start, already running.", job.Name)
+			} else if err != nil {
+				logger.Errorf("Job %s failed to start: %s", job.Name, err.Error())
+			}
+		})
 		if err != nil {
 			panic(err.Error())
 		}
 
-		c.Start()
-		logger.Debugf("Edit cronjob initialized to run every %s", interval)
+		logger.Debugf("Job %s initialized to run every %s", job.Name, job.Schedule)
 	}
+
+	apiCallCron := APICallCron{rfp}
+	if _, err := c.AddFunc("@every "+apiCallFlushInterval, apiCallCron.flushAPICalls); err != nil {
+		panic(err.Error())
+	}
+
+	c.Start()
 }
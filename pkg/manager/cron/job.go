@@ -0,0 +1,228 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// number of runs kept in the run history of each job
+const runHistorySize = 100
+
+var (
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobRunning         = errors.New("job is already running")
+	ErrJobRunNotRunning   = errors.New("job run is not running")
+	ErrJobsNotInitialized = errors.New("job manager not initialized")
+)
+
+// Job is a named background task. Jobs run at their schedule, if any, and
+// when triggered by an admin.
+type Job struct {
+	Name        string
+	Description string
+	// Interval between scheduled runs, in time.ParseDuration format. The job
+	// is only run when triggered if empty.
+	Schedule string
+	// Run performs the job. It should return promptly once ctx is cancelled.
+	Run func(ctx context.Context, fac models.Repo, progress *Progress) error
+}
+
+// Progress reports the progress of a running job.
+type Progress struct {
+	job      string
+	mutex    sync.Mutex
+	fraction *float64
+}
+
+// Update records that done out of total items have been processed.
+func (p *Progress) Update(done, total int) {
+	if total <= 0 {
+		return
+	}
+
+	fraction := float64(done) / float64(total)
+	p.mutex.Lock()
+	p.fraction = &fraction
+	p.mutex.Unlock()
+
+	logger.Progressf("%s: %d/%d", p.job, done, total)
+}
+
+func (p *Progress) get() *float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.fraction
+}
+
+type activeRun struct {
+	runID    uuid.UUID
+	cancel   context.CancelFunc
+	progress *Progress
+}
+
+type jobManager struct {
+	rfp RepoProvider
+
+	mutex  sync.Mutex
+	jobs   map[string]*Job
+	active map[string]*activeRun
+}
+
+var jobs = &jobManager{
+	jobs:   make(map[string]*Job),
+	active: make(map[string]*activeRun),
+}
+
+// Register adds a job to the registry. Jobs registered after Init are not
+// scheduled.
+func Register(job Job) {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+	jobs.jobs[job.Name] = &job
+}
+
+// Jobs returns the registered jobs, ordered by name.
+func Jobs() []*Job {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+
+	var ret []*Job
+	for _, job := range jobs.jobs {
+		ret = append(ret, job)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// IsRunning returns true if the named job has a run in progress.
+func IsRunning(name string) bool {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+	_, found := jobs.active[name]
+	return found
+}
+
+// RunProgress returns the progress reported by the run, if it is in
+// progress and has reported any.
+func RunProgress(runID uuid.UUID) *float64 {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+	for _, run := range jobs.active {
+		if run.runID == runID {
+			return run.progress.get()
+		}
+	}
+	return nil
+}
+
+// Trigger starts a run of the named job on behalf of the user.
+func Trigger(name string, userID *uuid.UUID) (*models.JobRun, error) {
+	jobs.mutex.Lock()
+	job, found := jobs.jobs[name]
+	jobs.mutex.Unlock()
+	if !found {
+		return nil, ErrJobNotFound
+	}
+
+	return jobs.start(job, userID)
+}
+
+// Cancel requests cancellation of the run. The run is recorded as cancelled
+// once the job returns.
+func Cancel(runID uuid.UUID) error {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+	for _, run := range jobs.active {
+		if run.runID == runID {
+			run.cancel()
+			return nil
+		}
+	}
+	return ErrJobRunNotRunning
+}
+
+func (m *jobManager) start(job *Job, userID *uuid.UUID) (*models.JobRun, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.rfp == nil {
+		return nil, ErrJobsNotInitialized
+	}
+	if _, found := m.active[job.Name]; found {
+		return nil, ErrJobRunning
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	fac := m.rfp.Repo()
+	var run *models.JobRun
+	if err := fac.WithTxn(func() error {
+		run, err = fac.Job().CreateRun(*models.NewJobRun(UUID, job.Name, userID))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	active := &activeRun{
+		runID:    run.ID,
+		cancel:   cancel,
+		progress: &Progress{job: job.Name},
+	}
+	m.active[job.Name] = active
+
+	go m.run(ctx, job, *run, active)
+
+	return run, nil
+}
+
+func (m *jobManager) run(ctx context.Context, job *Job, run models.JobRun, active *activeRun) {
+	defer func() {
+		active.cancel()
+		m.mutex.Lock()
+		delete(m.active, job.Name)
+		m.mutex.Unlock()
+	}()
+
+	logger.Debugf("Job %s started", job.Name)
+	err := job.Run(ctx, m.rfp.Repo(), active.progress)
+
+	switch {
+	case ctx.Err() != nil:
+		run.Finish(models.JobStatusEnumCancelled, nil)
+		logger.Infof("Job %s cancelled", job.Name)
+	case err != nil:
+		run.Finish(models.JobStatusEnumFailed, err)
+		logger.Errorf("Job %s failed: %s", job.Name, err.Error())
+	default:
+		run.Finish(models.JobStatusEnumSucceeded, nil)
+		logger.Debugf("Job %s finished", job.Name)
+	}
+
+	if progress := active.progress.get(); progress != nil {
+		run.Progress.Float64, run.Progress.Valid = *progress, true
+	}
+
+	fac := m.rfp.Repo()
+	if err := fac.WithTxn(func() error {
+		jqb := fac.Job()
+		if _, err := jqb.UpdateRun(run); err != nil {
+			return err
+		}
+		return jqb.PruneRuns(job.Name, runHistorySize)
+	}); err != nil {
+		logger.Errorf("Failed to record run of job %s: %s", job.Name, err.Error())
+	}
+}
@@ -0,0 +1,186 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+type memoryJobRepo struct {
+	models.JobRepo
+
+	mutex sync.Mutex
+	runs  map[uuid.UUID]models.JobRun
+}
+
+func (r *memoryJobRepo) CreateRun(newRun models.JobRun) (*models.JobRun, error) {
+	return r.UpdateRun(newRun)
+}
+
+func (r *memoryJobRepo) UpdateRun(updatedRun models.JobRun) (*models.JobRun, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.runs[updatedRun.ID] = updatedRun
+	return &updatedRun, nil
+}
+
+func (r *memoryJobRepo) PruneRuns(job string, keep int) error {
+	return nil
+}
+
+func (r *memoryJobRepo) get(id uuid.UUID) models.JobRun {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.runs[id]
+}
+
+type memoryRepo struct {
+	models.Repo
+	jobs *memoryJobRepo
+}
+
+func (r memoryRepo) WithTxn(fn func() error) error {
+	return fn()
+}
+
+func (r memoryRepo) Job() models.JobRepo {
+	return r.jobs
+}
+
+type memoryRepoProvider struct {
+	repo memoryRepo
+}
+
+func (p memoryRepoProvider) Repo() models.Repo {
+	return p.repo
+}
+
+func setupTestJobs(t *testing.T) *memoryJobRepo {
+	jobRepo := &memoryJobRepo{runs: make(map[uuid.UUID]models.JobRun)}
+	jobs = &jobManager{
+		rfp:    memoryRepoProvider{repo: memoryRepo{jobs: jobRepo}},
+		jobs:   make(map[string]*Job),
+		active: make(map[string]*activeRun),
+	}
+	return jobRepo
+}
+
+// waitForRun waits for the run to finish and returns its final state.
+func waitForRun(t *testing.T, jobRepo *memoryJobRepo, id uuid.UUID) models.JobRun {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		run := jobRepo.get(id)
+		if run.Status != models.JobStatusEnumRunning.String() {
+			return run
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("job run did not finish")
+	return models.JobRun{}
+}
+
+func TestTriggerJob(t *testing.T) {
+	jobRepo := setupTestJobs(t)
+
+	Register(Job{
+		Name: "succeed",
+		Run: func(ctx context.Context, fac models.Repo, progress *Progress) error {
+			progress.Update(1, 2)
+			return nil
+		},
+	})
+	Register(Job{
+		Name: "fail",
+		Run: func(ctx context.Context, fac models.Repo, progress *Progress) error {
+			return errors.New("failed")
+		},
+	})
+
+	if _, err := Trigger("missing", nil); err != ErrJobNotFound {
+		t.Errorf("Trigger missing job: expected %v, got %v", ErrJobNotFound, err)
+	}
+
+	userID := uuid.Must(uuid.NewV4())
+	run, err := Trigger("succeed", &userID)
+	if err != nil {
+		t.Fatalf("Trigger: %s", err.Error())
+	}
+	if !run.UserID.Valid || run.UserID.UUID != userID {
+		t.Errorf("UserID: expected %s, got %v", userID, run.UserID)
+	}
+
+	finished := waitForRun(t, jobRepo, run.ID)
+	if finished.Status != models.JobStatusEnumSucceeded.String() {
+		t.Errorf("Status: expected %s, got %s", models.JobStatusEnumSucceeded, finished.Status)
+	}
+	if !finished.Progress.Valid || finished.Progress.Float64 != 0.5 {
+		t.Errorf("Progress: expected 0.5, got %v", finished.Progress)
+	}
+	if !finished.FinishedAt.Valid {
+		t.Error("FinishedAt: expected finish time")
+	}
+
+	run, err = Trigger("fail", nil)
+	if err != nil {
+		t.Fatalf("Trigger: %s", err.Error())
+	}
+	finished = waitForRun(t, jobRepo, run.ID)
+	if finished.Status != models.JobStatusEnumFailed.String() {
+		t.Errorf("Status: expected %s, got %s", models.JobStatusEnumFailed, finished.Status)
+	}
+	if finished.Error.String != "failed" {
+		t.Errorf("Error: expected %q, got %q", "failed", finished.Error.String)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	jobRepo := setupTestJobs(t)
+
+	started := make(chan struct{})
+	Register(Job{
+		Name: "block",
+		Run: func(ctx context.Context, fac models.Repo, progress *Progress) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	run, err := Trigger("block", nil)
+	if err != nil {
+		t.Fatalf("Trigger: %s", err.Error())
+	}
+	<-started
+
+	if !IsRunning("block") {
+		t.Error("IsRunning: expected job to be running")
+	}
+	if _, err := Trigger("block", nil); err != ErrJobRunning {
+		t.Errorf("Trigger running job: expected %v, got %v", ErrJobRunning, err)
+	}
+
+	if err := Cancel(run.ID); err != nil {
+		t.Fatalf("Cancel: %s", err.Error())
+	}
+
+	finished := waitForRun(t, jobRepo, run.ID)
+	if finished.Status != models.JobStatusEnumCancelled.String() {
+		t.Errorf("Status: expected %s, got %s", models.JobStatusEnumCancelled, finished.Status)
+	}
+	if finished.Error.Valid {
+		t.Errorf("Error: expected none, got %q", finished.Error.String)
+	}
+
+	if err := Cancel(run.ID); err != ErrJobRunNotRunning {
+		t.Errorf("Cancel finished run: expected %v, got %v", ErrJobRunNotRunning, err)
+	}
+}
@@ -0,0 +1,285 @@
+package cron
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/image"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/manager/edit"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/notification"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+const (
+	ProcessEditsJob            = "process-edits"
+	SendNotificationDigestsJob = "send-notification-digests"
+	DestroyUnusedImagesJob     = "destroy-unused-images"
+	ClearExpiredActivationsJob = "clear-expired-activations"
+	GenerateImageVariantsJob   = "generate-image-variants"
+	ComputeImageHashesJob      = "compute-image-hashes"
+	FetchRemoteImagesJob       = "fetch-remote-images"
+	VerifyImageStorageJob      = "verify-image-storage"
+	defaultActivationsSchedule = "1h"
+
+	imageBatchSize = 100
+)
+
+func registerBuiltinJobs() {
+	Register(Job{
+		Name:        ProcessEditsJob,
+		Description: "Closes edits whose voting period has ended",
+		Schedule:    config.GetJobSchedule(ProcessEditsJob, config.GetVoteCronInterval()),
+		Run:         processEdits,
+	})
+	Register(Job{
+		Name:        SendNotificationDigestsJob,
+		Description: "Emails users a digest of their unread notifications",
+		Schedule:    config.GetJobSchedule(SendNotificationDigestsJob, config.GetNotificationDigestInterval()),
+		Run:         sendNotificationDigests,
+	})
+	Register(Job{
+		Name:        DestroyUnusedImagesJob,
+		Description: "Deletes images not used by any scene, performer, studio or pending edit",
+		Schedule:    config.GetJobSchedule(DestroyUnusedImagesJob, ""),
+		Run:         destroyUnusedImages,
+	})
+	Register(Job{
+		Name:        ClearExpiredActivationsJob,
+		Description: "Deletes expired pending account activations and password resets",
+		Schedule:    config.GetJobSchedule(ClearExpiredActivationsJob, defaultActivationsSchedule),
+		Run:         clearExpiredActivations,
+	})
+	Register(Job{
+		Name:        GenerateImageVariantsJob,
+		Description: "Generates missing configured variants of stored images",
+		Schedule:    config.GetJobSchedule(GenerateImageVariantsJob, ""),
+		Run:         generateImageVariants,
+	})
+	Register(Job{
+		Name:        ComputeImageHashesJob,
+		Description: "Computes perceptual hashes of stored images that have none",
+		Schedule:    config.GetJobSchedule(ComputeImageHashesJob, ""),
+		Run:         computeImageHashes,
+	})
+	Register(Job{
+		Name:        FetchRemoteImagesJob,
+		Description: "Downloads and stores images that only have a URL",
+		Schedule:    config.GetJobSchedule(FetchRemoteImagesJob, ""),
+		Run:         fetchRemoteImages,
+	})
+	Register(Job{
+		Name:        VerifyImageStorageJob,
+		Description: "Reports missing, orphaned and corrupted files in image storage",
+		Schedule:    config.GetJobSchedule(VerifyImageStorageJob, ""),
+		Run:         verifyImageStorage,
+	})
+}
+
+// processEdits closes edits where the voting period has ended, either by
+// applying the edit if the votes cast satisfy its voting policy, or by
+// rejecting it.
+func processEdits(ctx context.Context, fac models.Repo, progress *Progress) error {
+	edits, err := edit.FindCompletedEdits(fac)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Edit job running for %d edits", len(edits))
+	for i, e := range edits {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := fac.WithTxn(func() error {
+			result, err := edit.ResolveCompletedEdit(fac, e)
+			if err != nil {
+				return err
+			}
+
+			switch result {
+			case models.VoteStatusEnumAccepted:
+				_, err = edit.ApplyEdit(fac, e.ID, false)
+			case models.VoteStatusEnumRejected:
+				_, err = edit.CloseEdit(fac, e.ID, models.VoteStatusEnumRejected)
+			}
+			return err
+		}); err != nil {
+			// a failing edit should not hold up the others
+			logger.Errorf("Edit job failed to apply edit %s: %s", e.ID.String(), err.Error())
+		}
+
+		progress.Update(i+1, len(edits))
+	}
+
+	return nil
+}
+
+func sendNotificationDigests(ctx context.Context, fac models.Repo, progress *Progress) error {
+	return notification.SendDigests(fac, manager.GetInstance().EmailManager)
+}
+
+func destroyUnusedImages(ctx context.Context, fac models.Repo, progress *Progress) error {
+	for {
+		var unused []*models.Image
+		if err := fac.WithTxn(func() error {
+			var err error
+			unused, err = fac.Image().FindUnused()
+			return err
+		}); err != nil {
+			return err
+		}
+
+		destroyed := 0
+		for i, unusedImage := range unused {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err := fac.WithTxn(func() error {
+				iqb := fac.Image()
+				// the image may have been used since it was found
+				isUnused, err := iqb.IsUnused(unusedImage.ID)
+				if err != nil || !isUnused {
+					return err
+				}
+
+				destroyed++
+				return image.GetService(iqb).Destroy(models.ImageDestroyInput{
+					ID: unusedImage.ID.String(),
+				})
+			}); err != nil {
+				return err
+			}
+
+			progress.Update(i+1, len(unused))
+		}
+
+		// stop once no further images can be destroyed
+		if destroyed == 0 {
+			return nil
+		}
+	}
+}
+
+// generateImageVariants generates the configured variants of images stored
+// before the variant was configured.
+func generateImageVariants(ctx context.Context, fac models.Repo, progress *Progress) error {
+	var names []string
+	for _, variant := range config.GetImageVariants() {
+		// variants that cannot be encoded are never created, so the images
+		// missing them would be returned on every run
+		if image.HasEncoder(variant.Format) {
+			names = append(names, variant.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	return processImages(ctx, fac, "generate variants of",
+		func(qb models.ImageRepo, afterID uuid.UUID) ([]*models.Image, error) {
+			return qb.FindMissingVariants(names, afterID, imageBatchSize)
+		},
+		image.BackendService.GenerateVariants,
+	)
+}
+
+// computeImageHashes computes the perceptual hashes of images stored before
+// hashes were computed on upload.
+func computeImageHashes(ctx context.Context, fac models.Repo, progress *Progress) error {
+	return processImages(ctx, fac, "compute hash of",
+		func(qb models.ImageRepo, afterID uuid.UUID) ([]*models.Image, error) {
+			return qb.FindMissingPHashes(afterID, imageBatchSize)
+		},
+		image.BackendService.ComputePHash,
+	)
+}
+
+// fetchRemoteImages stores images created from a URL while fetching was
+// disabled.
+func fetchRemoteImages(ctx context.Context, fac models.Repo, progress *Progress) error {
+	return processImages(ctx, fac, "fetch",
+		func(qb models.ImageRepo, afterID uuid.UUID) ([]*models.Image, error) {
+			return qb.FindURLOnly(afterID, imageBatchSize)
+		},
+		image.BackendService.FetchRemote,
+	)
+}
+
+// verifyImageStorage logs each difference between the image storage and the
+// images table, failing the run if there are any.
+func verifyImageStorage(ctx context.Context, fac models.Repo, progress *Progress) error {
+	report, err := image.GetService(fac.Image()).VerifyStorage(ctx, fac.WithTxn)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range report.Missing {
+		logger.Warnf("Image storage is missing file %s", key)
+	}
+	for _, key := range report.Orphaned {
+		logger.Warnf("Image storage has orphaned file %s", key)
+	}
+	for _, id := range report.Mismatched {
+		logger.Warnf("Stored file of image %s does not match its checksum", id.String())
+	}
+
+	if report.HasProblems() {
+		return errors.New(report.String())
+	}
+
+	logger.Infof("Image storage verified: %s", report.String())
+	return nil
+}
+
+// processImages applies fn to each batch of images returned by find, each
+// image in its own transaction. Images are visited in id order, so images
+// that fail are logged and not retried.
+func processImages(
+	ctx context.Context,
+	fac models.Repo,
+	action string,
+	find func(qb models.ImageRepo, afterID uuid.UUID) ([]*models.Image, error),
+	fn func(s image.BackendService, img *models.Image) error,
+) error {
+	lastID := uuid.Nil
+	for {
+		var images []*models.Image
+		if err := fac.WithTxn(func() error {
+			var err error
+			images, err = find(fac.Image(), lastID)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if len(images) == 0 {
+			return nil
+		}
+
+		for _, img := range images {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if err := fac.WithTxn(func() error {
+				return fn(image.GetService(fac.Image()), img)
+			}); err != nil {
+				logger.Errorf("Failed to %s image %s: %s", action, img.ID.String(), err.Error())
+			}
+			lastID = img.ID
+		}
+	}
+}
+
+func clearExpiredActivations(ctx context.Context, fac models.Repo, progress *Progress) error {
+	return fac.WithTxn(func() error {
+		return user.ClearExpiredActivations(fac)
+	})
+}
@@ -0,0 +1,188 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/notification"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+// AmendEdit replaces the details of the pending edit with the edit_id of the
+// input. setData must set the amended details on the edit, diffed against
+// the current state of the target. The previous details are kept as an
+// amendment, and the votes cast on them are removed.
+func AmendEdit(fac models.Repo, currentUser *models.User, targetType models.TargetTypeEnum, input *models.EditInput, setData func(edit *models.Edit) error) (*models.Edit, error) {
+	editID, err := uuid.FromString(*input.EditID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Edit
+	err = fac.WithTxn(func() error {
+		eqb := fac.Edit()
+		edit, err := eqb.Find(editID)
+		if err != nil {
+			return err
+		}
+
+		if err := validateAmendment(eqb, currentUser, targetType, input, edit); err != nil {
+			return err
+		}
+
+		oldData := edit.Data
+		if err := setData(edit); err != nil {
+			return err
+		}
+
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		amendment := models.NewEditAmendment(UUID, currentUser, edit, oldData)
+		if err := eqb.CreateAmendment(*amendment); err != nil {
+			return err
+		}
+
+		// voters are notified before their votes are discarded
+		if err := notification.OnEditAmended(fac, edit); err != nil {
+			return err
+		}
+
+		if err := eqb.ResetVotes(edit.ID); err != nil {
+			return err
+		}
+
+		// the amended details are diffed against the current target
+		edit.Stale, err = isStale(fac, edit, targetType)
+		if err != nil {
+			return err
+		}
+		edit.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		ret, err = eqb.Update(*edit)
+		if err != nil {
+			return err
+		}
+
+		m := mutator{fac: fac, edit: ret}
+		if err := m.CreateComment(currentUser, input.Comment); err != nil {
+			return err
+		}
+
+		PublishEvent(fac, EventAmended, ret)
+		return nil
+	})
+
+	return ret, err
+}
+
+func validateAmendment(eqb models.EditRepo, currentUser *models.User, targetType models.TargetTypeEnum, input *models.EditInput, edit *models.Edit) error {
+	if edit == nil {
+		return errors.New("edit not found")
+	}
+
+	if edit.UserID != currentUser.ID {
+		return user.ErrUnauthorized
+	}
+
+	if edit.Status != models.VoteStatusEnumPending.String() {
+		return errors.New("only pending edits can be amended")
+	}
+
+	if edit.RevertsID.Valid {
+		return errors.New("revert edits cannot be amended")
+	}
+
+	if edit.TargetType != targetType.String() || edit.Operation != input.Operation.String() {
+		return errors.New("amendment must have the same target type and operation as the edit")
+	}
+
+	if input.Operation == models.OperationEnumCreate {
+		return nil
+	}
+
+	targetID, err := findTargetID(eqb, targetType, edit.ID)
+	if err != nil {
+		return err
+	}
+	if input.ID == nil || *input.ID != targetID.String() {
+		return errors.New("amendment must have the same target as the edit")
+	}
+
+	return nil
+}
+
+// AmendmentDiff returns the proposed fields changed by the amendment, with
+// their values before the amendment in old, and their amended values in
+// new. List fields are compared as a whole.
+func AmendmentDiff(targetType models.TargetTypeEnum, amendment *models.EditAmendment) (old models.EditDetails, new models.EditDetails, err error) {
+	oldData, newData, err := diffProposedData(amendment.OldData, amendment.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oldRevision := models.Revision{TargetType: targetType.String(), Data: oldData}
+	if old, err = oldRevision.GetDetails(); err != nil {
+		return nil, nil, err
+	}
+	newRevision := models.Revision{TargetType: targetType.String(), Data: newData}
+	if new, err = newRevision.GetDetails(); err != nil {
+		return nil, nil, err
+	}
+
+	return old, new, nil
+}
+
+func diffProposedData(from []byte, to []byte) ([]byte, []byte, error) {
+	fromData, err := getEditFieldData(&models.Edit{Data: from})
+	if err != nil {
+		return nil, nil, err
+	}
+	toData, err := getEditFieldData(&models.Edit{Data: to})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make(map[string]bool)
+	for k := range fromData.New {
+		keys[k] = true
+	}
+	for k := range toData.New {
+		keys[k] = true
+	}
+
+	oldFields := make(map[string]json.RawMessage)
+	newFields := make(map[string]json.RawMessage)
+	for k := range keys {
+		fromValue, toValue := fromData.New[k], toData.New[k]
+		equal, err := jsonEqual(fromValue, toValue)
+		if err != nil {
+			return nil, nil, err
+		}
+		if equal {
+			continue
+		}
+
+		if !isNullJSON(fromValue) {
+			oldFields[k] = fromValue
+		}
+		if !isNullJSON(toValue) {
+			newFields[k] = toValue
+		}
+	}
+
+	oldData, err := json.Marshal(oldFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	newData, err := json.Marshal(newFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return oldData, newData, nil
+}
@@ -0,0 +1,46 @@
+package edit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestAmendmentDiff(t *testing.T) {
+	amendment := &models.EditAmendment{
+		OldData: types.JSONText(`{
+			"new_data": {"name": "a", "description": "desc", "added_aliases": ["x"]},
+			"old_data": {"name": "old"}
+		}`),
+		Data: types.JSONText(`{
+			"new_data": {"name": "b", "added_aliases": ["x"], "removed_aliases": ["y"]},
+			"old_data": {"name": "old"}
+		}`),
+	}
+
+	old, new, err := AmendmentDiff(models.TargetTypeEnumTag, amendment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldName := "a"
+	description := "desc"
+	wantOld := &models.TagEdit{
+		Name:        &oldName,
+		Description: &description,
+	}
+	if !reflect.DeepEqual(old, wantOld) {
+		t.Errorf("old = %+v, want %+v", old, wantOld)
+	}
+
+	newName := "b"
+	wantNew := &models.TagEdit{
+		Name:           &newName,
+		RemovedAliases: []string{"y"},
+	}
+	if !reflect.DeepEqual(new, wantNew) {
+		t.Errorf("new = %+v, want %+v", new, wantNew)
+	}
+}
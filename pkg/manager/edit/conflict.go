@@ -0,0 +1,310 @@
+package edit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/revision"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+// FindConflicts returns the other pending edits conflicting with the pending
+// edit. Edits conflict when they change the same field of the same target,
+// or when one of them removes the target of the other, by destroying it or
+// merging it into another entity.
+func FindConflicts(fac models.Repo, edit *models.Edit) ([]*models.Edit, error) {
+	if edit.Status != models.VoteStatusEnumPending.String() || edit.Operation == models.OperationEnumCreate.String() {
+		return nil, nil
+	}
+
+	var targetType models.TargetTypeEnum
+	utils.ResolveEnumString(edit.TargetType, &targetType)
+	eqb := fac.Edit()
+
+	targetID, err := findTargetID(eqb, targetType, edit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := findRelatedPendingEdits(fac, edit, targetType, *targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.Edit
+	for _, candidate := range candidates {
+		candidateTargetID, err := findTargetID(eqb, targetType, candidate.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		conflict, err := editsConflict(edit, *targetID, candidate, *candidateTargetID)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			ret = append(ret, candidate)
+		}
+	}
+
+	return ret, nil
+}
+
+// findRelatedPendingEdits returns the other pending edits of the target,
+// pending merges of the target into another entity, and for merge edits,
+// pending edits of the merge sources.
+func findRelatedPendingEdits(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum, targetID uuid.UUID) ([]*models.Edit, error) {
+	eqb := fac.Edit()
+
+	targetIDs := []uuid.UUID{targetID}
+	if edit.Operation == models.OperationEnumMerge.String() {
+		if data := edit.GetData(); data != nil {
+			for _, id := range data.MergeSources {
+				sourceID, err := uuid.FromString(id)
+				if err != nil {
+					return nil, err
+				}
+				targetIDs = append(targetIDs, sourceID)
+			}
+		}
+	}
+
+	var edits []*models.Edit
+	for _, id := range targetIDs {
+		targetEdits, err := findTargetEdits(eqb, targetType, id)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, targetEdits...)
+	}
+
+	merges, err := eqb.FindPendingMergesBySourceID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	edits = append(edits, merges...)
+
+	seen := map[uuid.UUID]bool{edit.ID: true}
+	var ret []*models.Edit
+	for _, e := range edits {
+		if seen[e.ID] || e.Status != models.VoteStatusEnumPending.String() {
+			continue
+		}
+		seen[e.ID] = true
+		ret = append(ret, e)
+	}
+
+	return ret, nil
+}
+
+func editsConflict(edit *models.Edit, targetID uuid.UUID, other *models.Edit, otherTargetID uuid.UUID) (bool, error) {
+	// edits of different targets are only related through a merge, which
+	// removes the source
+	if targetID != otherTargetID {
+		return true, nil
+	}
+
+	if edit.Operation == models.OperationEnumDestroy.String() || other.Operation == models.OperationEnumDestroy.String() {
+		return true, nil
+	}
+
+	fields, err := editFields(edit)
+	if err != nil {
+		return false, err
+	}
+	otherFields, err := editFields(other)
+	if err != nil {
+		return false, err
+	}
+
+	for field := range fields {
+		if otherFields[field] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type editFieldData struct {
+	New map[string]json.RawMessage `json:"new_data"`
+	Old map[string]json.RawMessage `json:"old_data"`
+}
+
+func getEditFieldData(edit *models.Edit) (*editFieldData, error) {
+	data := editFieldData{}
+	if len(edit.Data) == 0 {
+		return &data, nil
+	}
+	if err := json.Unmarshal(edit.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// editFields returns the names of the fields set by the edit. List fields
+// are named without their added or removed prefix.
+func editFields(edit *models.Edit) (map[string]bool, error) {
+	data, err := getEditFieldData(edit)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]bool)
+	for _, fields := range []map[string]json.RawMessage{data.New, data.Old} {
+		for k, v := range fields {
+			if isNullJSON(v) {
+				continue
+			}
+			k = strings.TrimPrefix(k, "added_")
+			k = strings.TrimPrefix(k, "removed_")
+			ret[k] = true
+		}
+	}
+
+	return ret, nil
+}
+
+// isStale returns true if the pending edit no longer applies to its target,
+// either because the target or a merge source has been removed, or because
+// the old value of a field it changes no longer matches the target.
+func isStale(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum) (bool, error) {
+	var operation models.OperationEnum
+	utils.ResolveEnumString(edit.Operation, &operation)
+	if operation == models.OperationEnumCreate || operation == models.OperationEnumRestore {
+		return false, nil
+	}
+
+	targetID, err := findTargetID(fac.Edit(), targetType, edit.ID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := isTargetDeleted(fac, targetType, *targetID)
+	if err != nil || deleted {
+		return deleted, err
+	}
+
+	if operation == models.OperationEnumDestroy {
+		return false, nil
+	}
+
+	if data := edit.GetData(); data != nil {
+		for _, id := range data.MergeSources {
+			sourceID, err := uuid.FromString(id)
+			if err != nil {
+				return false, err
+			}
+			deleted, err := isTargetDeleted(fac, targetType, sourceID)
+			if err != nil || deleted {
+				return deleted, err
+			}
+		}
+	}
+
+	details, err := revision.CurrentDetails(fac, targetType, *targetID)
+	if err != nil {
+		return false, err
+	}
+	buffer, err := json.Marshal(details)
+	if err != nil {
+		return false, err
+	}
+	var current map[string]json.RawMessage
+	if err := json.Unmarshal(buffer, &current); err != nil {
+		return false, err
+	}
+
+	data, err := getEditFieldData(edit)
+	if err != nil {
+		return false, err
+	}
+
+	// list changes are applied as additions and removals, so only the old
+	// values of scalar fields need to match
+	for _, fields := range []map[string]json.RawMessage{data.New, data.Old} {
+		for k, v := range fields {
+			if isNullJSON(v) || strings.HasPrefix(k, "added_") || strings.HasPrefix(k, "removed_") {
+				continue
+			}
+
+			equal, err := jsonEqual(data.Old[k], current[k])
+			if err != nil || !equal {
+				return !equal, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// markStaleEdits updates the stale flag of the pending edits related to the
+// applied edit.
+func markStaleEdits(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum) error {
+	eqb := fac.Edit()
+	targetID, err := findTargetID(eqb, targetType, edit.ID)
+	if err != nil {
+		return err
+	}
+
+	related, err := findRelatedPendingEdits(fac, edit, targetType, *targetID)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range related {
+		stale, err := isStale(fac, e, targetType)
+		if err != nil {
+			return err
+		}
+		if stale == e.Stale {
+			continue
+		}
+
+		e.Stale = stale
+		if _, err := eqb.Update(*e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findTargetEdits(eqb models.EditRepo, targetType models.TargetTypeEnum, id uuid.UUID) ([]*models.Edit, error) {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		return eqb.FindByTagID(id)
+	case models.TargetTypeEnumPerformer:
+		return eqb.FindByPerformerID(id)
+	case models.TargetTypeEnumStudio:
+		return eqb.FindByStudioID(id)
+	case models.TargetTypeEnumScene:
+		return eqb.FindBySceneID(id)
+	default:
+		return nil, nil
+	}
+}
+
+func isNullJSON(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
+
+// jsonEqual compares JSON values, treating missing values as null.
+func jsonEqual(a json.RawMessage, b json.RawMessage) (bool, error) {
+	if isNullJSON(a) || isNullJSON(b) {
+		return isNullJSON(a) == isNullJSON(b), nil
+	}
+
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aValue, bValue), nil
+}
@@ -0,0 +1,80 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestEditsConflict(t *testing.T) {
+	targetID := uuid.Must(uuid.NewV4())
+	otherID := uuid.Must(uuid.NewV4())
+
+	newEdit := func(operation models.OperationEnum, data string) *models.Edit {
+		return &models.Edit{
+			ID:        uuid.Must(uuid.NewV4()),
+			Operation: operation.String(),
+			Data:      types.JSONText(data),
+		}
+	}
+
+	rename := newEdit(models.OperationEnumModify, `{"new_data": {"name": "a"}, "old_data": {"name": "b"}}`)
+	unsetName := newEdit(models.OperationEnumModify, `{"new_data": {}, "old_data": {"name": "b"}}`)
+	addAlias := newEdit(models.OperationEnumModify, `{"new_data": {"added_aliases": ["c"]}, "old_data": {}}`)
+	removeAlias := newEdit(models.OperationEnumModify, `{"new_data": {"removed_aliases": ["d"]}, "old_data": {}}`)
+	nullName := newEdit(models.OperationEnumModify, `{"new_data": {"name": null, "added_urls": [{"url": "u", "type": "t"}]}, "old_data": {}}`)
+	destroy := newEdit(models.OperationEnumDestroy, ``)
+
+	tests := []struct {
+		name          string
+		edit          *models.Edit
+		other         *models.Edit
+		otherTargetID uuid.UUID
+		want          bool
+	}{
+		{"same field", rename, unsetName, targetID, true},
+		{"same list field", addAlias, removeAlias, targetID, true},
+		{"different fields", rename, addAlias, targetID, false},
+		{"null fields ignored", rename, nullName, targetID, false},
+		{"destroy", destroy, addAlias, targetID, true},
+		{"merge source", rename, addAlias, otherID, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := editsConflict(tt.edit, targetID, tt.other, tt.otherTargetID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("editsConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`"a"`, `"a"`, true},
+		{`"a"`, `"b"`, false},
+		{``, `null`, true},
+		{``, `"a"`, false},
+		{`170`, `170.0`, true},
+		{`{"a": 1, "b": 2}`, `{"b":2,"a":1}`, true},
+	}
+
+	for _, tt := range tests {
+		got, err := jsonEqual([]byte(tt.a), []byte(tt.b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("jsonEqual(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
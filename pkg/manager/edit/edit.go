@@ -3,14 +3,16 @@ package edit
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/notification"
+	"github.com/stashapp/stash-box/pkg/revision"
 	"github.com/stashapp/stash-box/pkg/user"
 	"github.com/stashapp/stash-box/pkg/utils"
+	"github.com/stashapp/stash-box/pkg/webhook"
 )
 
 // InputSpecifiedFunc is function that returns true if the qualified field name
@@ -29,6 +31,19 @@ func (m *mutator) operation() models.OperationEnum {
 	return operation
 }
 
+// targetOperation returns the operation to apply to the edit target. Restore
+// edits of merges also revert the changes made to the merge target, which
+// are applied as a modification.
+func (m *mutator) targetOperation() models.OperationEnum {
+	operation := m.operation()
+	if operation == models.OperationEnumRestore {
+		if data := m.edit.GetData(); data != nil && data.New != nil {
+			return models.OperationEnumModify
+		}
+	}
+	return operation
+}
+
 func (m *mutator) CreateEdit() (*models.Edit, error) {
 	created, err := m.fac.Edit().Create(*m.edit)
 	if err != nil {
@@ -36,6 +51,7 @@ func (m *mutator) CreateEdit() (*models.Edit, error) {
 	}
 
 	m.edit = created
+	PublishEvent(m.fac, EventCreated, created)
 	return created, nil
 }
 
@@ -88,7 +104,6 @@ func ApplyEdit(fac models.Repo, editID uuid.UUID, immediate bool) (*models.Edit,
 			return err
 		}
 		if err := validateEditPrerequisites(fac, edit); err != nil {
-			edit.Fail()
 			return err
 		}
 
@@ -97,6 +112,23 @@ func ApplyEdit(fac models.Repo, editID uuid.UUID, immediate bool) (*models.Edit,
 		var targetType models.TargetTypeEnum
 		utils.ResolveEnumString(edit.TargetType, &targetType)
 
+		stale, err := isStale(fac, edit, targetType)
+		if err != nil {
+			return err
+		}
+		if stale {
+			// the edit was made against an outdated version of the target
+			edit.Stale = true
+			edit.Fail()
+			updatedEdit, err = eqb.Update(*edit)
+			if err != nil {
+				return err
+			}
+
+			PublishEvent(fac, EventStatusChanged, updatedEdit)
+			return notification.OnEditStatusChanged(fac, updatedEdit)
+		}
+
 		var applyer editApplyer
 		switch targetType {
 		case models.TargetTypeEnumTag:
@@ -111,10 +143,33 @@ func ApplyEdit(fac models.Repo, editID uuid.UUID, immediate bool) (*models.Edit,
 			return errors.New("Not implemented: " + edit.TargetType)
 		}
 
+		switch operation {
+		case models.OperationEnumMerge, models.OperationEnumDestroy:
+			if err := createSnapshot(fac, edit, targetType, operation); err != nil {
+				return err
+			}
+		case models.OperationEnumRestore:
+			if err := restoreSnapshot(fac, edit); err != nil {
+				return err
+			}
+		}
+
 		if err := applyer.apply(); err != nil {
 			return err
 		}
 
+		if err := recordRevisions(fac, edit, targetType, operation); err != nil {
+			return err
+		}
+
+		if err := markStaleEdits(fac, edit, targetType); err != nil {
+			return err
+		}
+
+		if err := enqueueWebhooks(fac, edit, targetType, operation); err != nil {
+			return err
+		}
+
 		if immediate {
 			edit.ImmediateAccept()
 		} else {
@@ -126,6 +181,11 @@ func ApplyEdit(fac models.Repo, editID uuid.UUID, immediate bool) (*models.Edit,
 			return err
 		}
 
+		PublishEvent(fac, EventStatusChanged, updatedEdit)
+		if err := notification.OnEditStatusChanged(fac, updatedEdit); err != nil {
+			return err
+		}
+
 		userPromotionThreshold := config.GetVotePromotionThreshold()
 		if userPromotionThreshold != nil {
 			err = user.PromoteUserVoteRights(fac, updatedEdit.UserID, *userPromotionThreshold)
@@ -137,6 +197,67 @@ func ApplyEdit(fac models.Repo, editID uuid.UUID, immediate bool) (*models.Edit,
 	return updatedEdit, err
 }
 
+// enqueueWebhooks queues webhook deliveries for the target of an applied edit.
+func enqueueWebhooks(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum, operation models.OperationEnum) error {
+	targetID, err := findTargetID(fac.Edit(), targetType, edit.ID)
+	if err != nil || targetID == nil {
+		return err
+	}
+
+	event := webhook.Event{
+		TargetType: targetType,
+		Operation:  operation,
+		TargetID:   *targetID,
+		EditID:     &edit.ID,
+	}
+	if data := edit.GetData(); data != nil {
+		event.MergeSourceIDs = data.MergeSources
+	}
+
+	return webhook.Enqueue(fac, event)
+}
+
+// recordRevisions records revisions of the entities changed by the applied
+// edit, including any merge sources.
+func recordRevisions(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum, operation models.OperationEnum) error {
+	targetID, err := findTargetID(fac.Edit(), targetType, edit.ID)
+	if err != nil || targetID == nil {
+		return err
+	}
+
+	event := revision.Event{
+		TargetType: targetType,
+		Operation:  operation,
+		TargetID:   *targetID,
+		UserID:     &edit.UserID,
+		EditID:     &edit.ID,
+	}
+	if err := revision.Record(fac, event); err != nil {
+		return err
+	}
+
+	if operation != models.OperationEnumMerge && operation != models.OperationEnumRestore {
+		return nil
+	}
+
+	data := edit.GetData()
+	if data == nil {
+		return nil
+	}
+	for _, id := range data.MergeSources {
+		sourceID, err := uuid.FromString(id)
+		if err != nil {
+			return err
+		}
+		event.TargetID = sourceID
+		if err := revision.Record(fac, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func CloseEdit(fac models.Repo, editID uuid.UUID, status models.VoteStatusEnum) (*models.Edit, error) {
 	var updatedEdit *models.Edit
 	err := fac.WithTxn(func() error {
@@ -150,7 +271,6 @@ func CloseEdit(fac models.Repo, editID uuid.UUID, status models.VoteStatusEnum)
 			return err
 		}
 		if err := validateEditPrerequisites(fac, edit); err != nil {
-			edit.Fail()
 			return err
 		}
 
@@ -166,8 +286,12 @@ func CloseEdit(fac models.Repo, editID uuid.UUID, status models.VoteStatusEnum)
 		}
 
 		updatedEdit, err = eqb.Update(*edit)
+		if err != nil {
+			return err
+		}
 
-		return err
+		PublishEvent(fac, EventStatusChanged, updatedEdit)
+		return notification.OnEditStatusChanged(fac, updatedEdit)
 	})
 
 	return updatedEdit, err
@@ -213,40 +337,3 @@ func urlCompare(subject []*models.URL, against []*models.URL) (added []*models.U
 	}
 	return
 }
-
-func ResolveVotingThreshold(fac models.Repo, edit *models.Edit) (models.VoteStatusEnum, error) {
-	threshold := config.GetVoteApplicationThreshold()
-	if threshold == 0 {
-		return models.VoteStatusEnumPending, nil
-	}
-
-	// For destructive edits we check if they've been open for a minimum period before applying
-	if edit.Operation == models.OperationEnumDestroy.String() || edit.Operation == models.OperationEnumMerge.String() {
-		if time.Since(edit.CreatedAt.Timestamp).Seconds() <= float64(config.GetMinDestructiveVotingPeriod()) {
-			return models.VoteStatusEnumPending, nil
-		}
-	}
-
-	votes, err := fac.Edit().GetVotes(edit.ID)
-	if err != nil {
-		return models.VoteStatusEnumPending, err
-	}
-
-	positive := 0
-	negative := 0
-	for _, vote := range votes {
-		if vote.Vote == models.VoteTypeEnumAccept.String() {
-			positive++
-		} else if vote.Vote == models.VoteTypeEnumReject.String() {
-			negative++
-		}
-	}
-
-	if positive >= threshold && negative == 0 {
-		return models.VoteStatusEnumAccepted, nil
-	} else if negative >= threshold && positive == 0 {
-		return models.VoteStatusEnumRejected, nil
-	}
-
-	return models.VoteStatusEnumPending, nil
-}
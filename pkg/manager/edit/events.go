@@ -0,0 +1,89 @@
+package edit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// EventType identifies a stage in the lifecycle of an edit.
+type EventType string
+
+const (
+	// EventCreated is published when a new edit is submitted.
+	EventCreated EventType = "CREATED"
+	// EventVoted is published when a vote is cast on an edit.
+	EventVoted EventType = "VOTED"
+	// EventAmended is published when the submitter amends an edit.
+	EventAmended EventType = "AMENDED"
+	// EventCommented is published when a comment is added to an edit.
+	EventCommented EventType = "COMMENTED"
+	// EventStatusChanged is published when an edit is applied or closed.
+	EventStatusChanged EventType = "STATUS_CHANGED"
+)
+
+// subscriber buffer size. Events are dropped for subscribers that fall
+// this far behind rather than blocking the publisher.
+const eventBufferSize = 100
+
+var (
+	eventMutex sync.Mutex
+	eventSubs  = make(map[EventType][]chan *models.Edit)
+)
+
+// Subscribe returns a channel that receives edits for the provided event
+// type. The channel is closed once the context is done.
+func Subscribe(ctx context.Context, event EventType) <-chan *models.Edit {
+	ret := make(chan *models.Edit, eventBufferSize)
+
+	eventMutex.Lock()
+	eventSubs[event] = append(eventSubs[event], ret)
+	eventMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe(event, ret)
+	}()
+
+	return ret
+}
+
+func unsubscribe(event EventType, toRemove chan *models.Edit) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+
+	subs := eventSubs[event]
+	for i, c := range subs {
+		if c == toRemove {
+			eventSubs[event] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	close(toRemove)
+}
+
+// PublishEvent broadcasts the edit to all subscribers of the event type once
+// the current transaction has been committed.
+func PublishEvent(fac models.Repo, event EventType, edit *models.Edit) {
+	if edit == nil {
+		return
+	}
+
+	fac.AddPostCommitHook(func() {
+		broadcast(event, edit)
+	})
+}
+
+func broadcast(event EventType, edit *models.Edit) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+
+	for _, c := range eventSubs[event] {
+		// don't block waiting on slow subscribers
+		select {
+		case c <- edit:
+		default:
+		}
+	}
+}
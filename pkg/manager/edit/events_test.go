@@ -0,0 +1,51 @@
+package edit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	created := Subscribe(ctx, EventCreated)
+	voted := Subscribe(ctx, EventVoted)
+
+	edit := &models.Edit{ID: uuid.Must(uuid.NewV4())}
+	broadcast(EventCreated, edit)
+
+	select {
+	case got := <-created:
+		if got.ID != edit.ID {
+			t.Errorf("received edit %s, want %s", got.ID, edit.ID)
+		}
+	default:
+		t.Error("expected edit on created channel")
+	}
+
+	select {
+	case <-voted:
+		t.Error("unexpected edit on voted channel")
+	default:
+	}
+
+	cancel()
+
+	// channels are closed once the context is done
+	if _, ok := <-created; ok {
+		t.Error("expected created channel to be closed")
+	}
+	if _, ok := <-voted; ok {
+		t.Error("expected voted channel to be closed")
+	}
+
+	eventMutex.Lock()
+	remaining := len(eventSubs[EventCreated]) + len(eventSubs[EventVoted])
+	eventMutex.Unlock()
+	if remaining != 0 {
+		t.Errorf("got %d remaining subscribers, want 0", remaining)
+	}
+}
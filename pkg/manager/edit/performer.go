@@ -94,6 +94,13 @@ func (m *PerformerEditProcessor) modifyEdit(input models.PerformerEditInput, inp
 	}
 	performerEdit.New.AddedImages, performerEdit.New.RemovedImages = utils.StrSliceCompare(input.Details.ImageIds, existingImages)
 
+	if err := m.validateRelationships(input.Details.Relationships, []string{performerID.String()}); err != nil {
+		return err
+	}
+	if err := m.diffRelationships(&performerEdit, performerID, input.Details.Relationships); err != nil {
+		return err
+	}
+
 	if input.Options != nil && input.Options.SetModifyAliases != nil {
 		performerEdit.SetModifyAliases = *input.Options.SetModifyAliases
 	}
@@ -179,6 +186,15 @@ func (m *PerformerEditProcessor) mergeEdit(input models.PerformerEditInput, inpu
 	}
 	performerEdit.New.AddedImages, performerEdit.New.RemovedImages = utils.StrSliceCompare(input.Details.ImageIds, existingImages)
 
+	// relationships between the merged performers are dropped on merge
+	excluded := append([]string{performerID.String()}, mergeSources...)
+	if err := m.validateRelationships(input.Details.Relationships, excluded); err != nil {
+		return err
+	}
+	if err := m.diffRelationships(&performerEdit, performerID, input.Details.Relationships); err != nil {
+		return err
+	}
+
 	if input.Options != nil && input.Options.SetMergeAliases != nil {
 		performerEdit.SetMergeAliases = *input.Options.SetMergeAliases
 	}
@@ -212,6 +228,13 @@ func (m *PerformerEditProcessor) createEdit(input models.PerformerEditInput, inp
 		performerEdit.New.AddedImages = input.Details.ImageIds
 	}
 
+	if len(input.Details.Relationships) != 0 || inputSpecified("relationships") {
+		if err := m.validateRelationships(input.Details.Relationships, nil); err != nil {
+			return err
+		}
+		performerEdit.New.AddedRelationships, _ = relationshipCompare(uuid.Nil, input.Details.Relationships, nil)
+	}
+
 	return m.edit.SetData(performerEdit)
 }
 
@@ -247,7 +270,10 @@ func (m *PerformerEditProcessor) CreateJoin(input models.PerformerEditInput) err
 func (m *PerformerEditProcessor) apply() error {
 	pqb := m.fac.Performer()
 	eqb := m.fac.Edit()
-	operation := m.operation()
+	operation := m.targetOperation()
+	if operation == models.OperationEnumRestore {
+		return nil
+	}
 	isCreate := operation == models.OperationEnumCreate
 
 	var performer *models.Performer
@@ -284,6 +310,83 @@ func (m *PerformerEditProcessor) apply() error {
 	return nil
 }
 
+func (m *PerformerEditProcessor) diffRelationships(performerEdit *models.PerformerEditData, performerID uuid.UUID, relationships []*models.PerformerRelationshipInput) error {
+	relations, err := m.fac.Performer().GetRelations(performerID)
+	if err != nil {
+		return err
+	}
+
+	performerEdit.New.AddedRelationships, performerEdit.New.RemovedRelationships = relationshipCompare(performerID, relationships, relations.ToInputs(performerID))
+	return nil
+}
+
+// validateRelationships returns an error if a relationship type is invalid,
+// or if a related performer is excluded, missing or deleted.
+func (m *PerformerEditProcessor) validateRelationships(relationships []*models.PerformerRelationshipInput, excluded []string) error {
+	pqb := m.fac.Performer()
+	for _, r := range relationships {
+		if !r.Type.IsValid() {
+			return errors.New("invalid relationship type: " + r.Type.String())
+		}
+
+		for _, id := range excluded {
+			if r.PerformerID == id {
+				return errors.New("performer cannot be related to performer " + id)
+			}
+		}
+
+		relatedID, err := uuid.FromString(r.PerformerID)
+		if err != nil {
+			return err
+		}
+		related, err := pqb.Find(relatedID)
+		if err != nil {
+			return err
+		}
+		if related == nil || related.Deleted {
+			return errors.New("related performer with id " + r.PerformerID + " not found")
+		}
+	}
+
+	return nil
+}
+
+// relationshipCompare compares relationships from the perspective of
+// performerID. Relationships are matched by their stored form, so that
+// MEMBER_OF and GROUP_MEMBER relationships of the same pair are distinct.
+func relationshipCompare(performerID uuid.UUID, subject []*models.PerformerRelationshipInput, against []*models.PerformerRelationshipInput) (added []*models.PerformerRelationshipInput, missing []*models.PerformerRelationshipInput) {
+	key := func(r *models.PerformerRelationshipInput) string {
+		return models.NewPerformerRelation(performerID, *r).ID()
+	}
+
+	subjectKeys := make(map[string]bool)
+	for _, s := range subject {
+		subjectKeys[key(s)] = true
+	}
+
+	againstKeys := make(map[string]bool)
+	for _, a := range against {
+		againstKeys[key(a)] = true
+	}
+
+	for _, s := range subject {
+		k := key(s)
+		if !againstKeys[k] {
+			added = append(added, s)
+			// ignore duplicates
+			againstKeys[k] = true
+		}
+	}
+
+	for _, a := range against {
+		if !subjectKeys[key(a)] {
+			missing = append(missing, a)
+		}
+	}
+
+	return
+}
+
 func bodyModCompare(subject []*models.BodyModification, against []*models.BodyModification) (added []*models.BodyModification, missing []*models.BodyModification) {
 	for _, s := range subject {
 		newMod := true
@@ -0,0 +1,179 @@
+package edit
+
+import (
+	"math"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+// GetVotingPolicy returns the voting rules applying to the edit.
+func GetVotingPolicy(edit *models.Edit) config.VotingPolicy {
+	return config.GetVotingPolicy(edit.TargetType, edit.Operation)
+}
+
+type voteTally struct {
+	positive float64
+	negative float64
+	// number of distinct users that voted for or against the edit
+	voters int
+}
+
+func (t voteTally) count() float64 {
+	return t.positive - t.negative
+}
+
+// tallyVotes sums the accept and reject votes. With weighted voting
+// enabled, each vote counts for the reputation weight of the voter.
+func tallyVotes(fac models.Repo, votes models.EditVotes) (voteTally, error) {
+	var ret voteTally
+	for _, vote := range votes {
+		if vote.Vote != models.VoteTypeEnumAccept.String() && vote.Vote != models.VoteTypeEnumReject.String() {
+			continue
+		}
+
+		weight := 1.0
+		if config.GetWeightedVoting() {
+			reputation, err := user.GetReputation(fac, vote.UserID)
+			if err != nil {
+				return ret, err
+			}
+			weight = reputation.Weight()
+		}
+
+		if vote.Vote == models.VoteTypeEnumAccept.String() {
+			ret.positive += weight
+		} else {
+			ret.negative += weight
+		}
+		ret.voters++
+	}
+	return ret, nil
+}
+
+// GetVoteCount returns the vote count of the edit, weighted by the
+// reputation of the voters if weighted voting is enabled.
+func GetVoteCount(fac models.Repo, edit *models.Edit) (float64, error) {
+	if !config.GetWeightedVoting() {
+		return float64(edit.VoteCount), nil
+	}
+
+	votes, err := fac.Edit().GetVotes(edit.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	tally, err := tallyVotes(fac, votes)
+	return tally.count(), err
+}
+
+// ResolveVotingThreshold returns the status of the edit once the votes cast
+// so far are taken into account. Edits are accepted or rejected before the
+// end of the voting period when the policy's application threshold of
+// unanimous votes is reached, once the minimum voting period has passed.
+func ResolveVotingThreshold(fac models.Repo, edit *models.Edit) (models.VoteStatusEnum, error) {
+	policy := GetVotingPolicy(edit)
+	return resolveVotingThreshold(fac, edit, policy)
+}
+
+func resolveVotingThreshold(fac models.Repo, edit *models.Edit, policy config.VotingPolicy) (models.VoteStatusEnum, error) {
+	threshold := policy.VoteApplicationThreshold
+	if threshold == 0 {
+		return models.VoteStatusEnumPending, nil
+	}
+
+	if time.Since(edit.CreatedAt.Timestamp).Seconds() <= float64(policy.MinVotingPeriod) {
+		return models.VoteStatusEnumPending, nil
+	}
+
+	votes, err := fac.Edit().GetVotes(edit.ID)
+	if err != nil {
+		return models.VoteStatusEnumPending, err
+	}
+
+	tally, err := tallyVotes(fac, votes)
+	if err != nil {
+		return models.VoteStatusEnumPending, err
+	}
+	if tally.voters < policy.Quorum {
+		return models.VoteStatusEnumPending, nil
+	}
+
+	if tally.positive >= float64(threshold) && tally.negative == 0 {
+		return models.VoteStatusEnumAccepted, nil
+	} else if tally.negative >= float64(threshold) && tally.positive == 0 {
+		return models.VoteStatusEnumRejected, nil
+	}
+
+	return models.VoteStatusEnumPending, nil
+}
+
+// ResolveCompletedEdit returns the status of the pending edit selected by
+// FindCompletedEdits. Once the voting period has ended, edits are accepted
+// if their vote count reaches the policy's approval threshold and enough
+// users voted to reach the quorum, and rejected otherwise. Before then,
+// edits are accepted once the minimum voting period has passed if their
+// vote count reaches the application threshold.
+func ResolveCompletedEdit(fac models.Repo, edit *models.Edit) (models.VoteStatusEnum, error) {
+	policy := GetVotingPolicy(edit)
+
+	votes, err := fac.Edit().GetVotes(edit.ID)
+	if err != nil {
+		return models.VoteStatusEnumPending, err
+	}
+	tally, err := tallyVotes(fac, votes)
+	if err != nil {
+		return models.VoteStatusEnumPending, err
+	}
+
+	age := time.Since(edit.CreatedAt.Timestamp).Seconds()
+	if age < float64(config.GetVotingPeriod()) {
+		if policy.VoteApplicationThreshold > 0 && tally.count() >= float64(policy.VoteApplicationThreshold) &&
+			age > float64(policy.MinVotingPeriod) && tally.voters >= policy.Quorum {
+			return models.VoteStatusEnumAccepted, nil
+		}
+		return resolveVotingThreshold(fac, edit, policy)
+	}
+
+	if tally.count() >= float64(policy.ApprovalThreshold) && tally.voters >= policy.Quorum {
+		return models.VoteStatusEnumAccepted, nil
+	}
+
+	return models.VoteStatusEnumRejected, nil
+}
+
+// FindCompletedEdits returns the pending edits which may be closed: those
+// whose voting period has ended, and those which may have reached the
+// application threshold of their policy after its minimum voting period.
+func FindCompletedEdits(fac models.Repo) ([]*models.Edit, error) {
+	votingPeriod := config.GetVotingPeriod()
+	minimumVotingPeriod := votingPeriod
+	minimumVotes := -1
+	for _, targetType := range models.AllTargetTypeEnum {
+		for _, operation := range models.AllOperationEnum {
+			policy := config.GetVotingPolicy(targetType.String(), operation.String())
+			if policy.VoteApplicationThreshold == 0 {
+				continue
+			}
+
+			if policy.MinVotingPeriod < minimumVotingPeriod {
+				minimumVotingPeriod = policy.MinVotingPeriod
+			}
+			if minimumVotes == -1 || policy.VoteApplicationThreshold < minimumVotes {
+				minimumVotes = policy.VoteApplicationThreshold
+			}
+		}
+	}
+
+	if minimumVotes == -1 {
+		// no policy applies edits before the end of the voting period
+		minimumVotes = 0
+	} else if config.GetWeightedVoting() {
+		// the stored vote count is unweighted, so cannot be used to filter
+		minimumVotes = math.MinInt32
+	}
+
+	return fac.Edit().FindCompletedEdits(votingPeriod, minimumVotingPeriod, minimumVotes)
+}
@@ -0,0 +1,262 @@
+package edit
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/notification"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var ErrAlreadyReverted = errors.New("edit has already been reverted")
+
+// RevertEdit creates a pending edit reverting the applied edit with the
+// provided id. Modify edits are reverted by a modify edit built from the old
+// details. Merge and destroy edits are reverted by a restore edit, which
+// restores the removed entities from the snapshot taken when the edit was
+// applied. Create edits are reverted by a destroy edit.
+func RevertEdit(fac models.Repo, currentUser *models.User, editID uuid.UUID, comment *string) (*models.Edit, error) {
+	var ret *models.Edit
+	err := fac.WithTxn(func() error {
+		eqb := fac.Edit()
+		reverted, err := eqb.Find(editID)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			return errors.New("edit not found")
+		}
+		if !reverted.Applied {
+			return errors.New("only applied edits can be reverted")
+		}
+
+		reverts, err := eqb.FindReverts(editID)
+		if err != nil {
+			return err
+		}
+		for _, e := range reverts {
+			switch e.Status {
+			case models.VoteStatusEnumPending.String(), models.VoteStatusEnumAccepted.String(), models.VoteStatusEnumImmediateAccepted.String():
+				return ErrAlreadyReverted
+			}
+		}
+
+		var targetType models.TargetTypeEnum
+		utils.ResolveEnumString(reverted.TargetType, &targetType)
+		targetID, err := findTargetID(eqb, targetType, editID)
+		if err != nil {
+			return err
+		}
+
+		var operation models.OperationEnum
+		utils.ResolveEnumString(reverted.Operation, &operation)
+
+		var revertOperation models.OperationEnum
+		var data types.JSONText
+		switch operation {
+		case models.OperationEnumCreate:
+			revertOperation = models.OperationEnumDestroy
+		case models.OperationEnumModify:
+			revertOperation = models.OperationEnumModify
+			data, err = invertEditData(reverted.Data, false)
+		case models.OperationEnumMerge:
+			revertOperation = models.OperationEnumRestore
+			data, err = invertEditData(reverted.Data, true)
+		case models.OperationEnumDestroy:
+			revertOperation = models.OperationEnumRestore
+		default:
+			return errors.New("edits with operation " + reverted.Operation + " cannot be reverted")
+		}
+		if err != nil {
+			return err
+		}
+
+		if operation != models.OperationEnumDestroy {
+			deleted, err := isTargetDeleted(fac, targetType, *targetID)
+			if err != nil {
+				return err
+			}
+			if deleted {
+				return errors.New("edit target has been deleted")
+			}
+		}
+
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+
+		newEdit := models.NewEdit(UUID, currentUser, targetType, &models.EditInput{Operation: revertOperation})
+		newEdit.Data = data
+		newEdit.RevertsID = uuid.NullUUID{UUID: editID, Valid: true}
+
+		m := mutator{fac: fac, edit: newEdit}
+		if _, err := m.CreateEdit(); err != nil {
+			return err
+		}
+
+		if err := createTargetJoin(eqb, targetType, newEdit.ID, *targetID); err != nil {
+			return err
+		}
+
+		if err := notification.OnEditCreated(fac, newEdit); err != nil {
+			return err
+		}
+
+		ret = m.edit
+		return m.CreateComment(currentUser, comment)
+	})
+
+	return ret, err
+}
+
+// invertEditData returns edit data applying the reverse of the provided
+// edit data. Old and new values are swapped, as are added and removed
+// values.
+func invertEditData(data types.JSONText, keepMergeSources bool) (types.JSONText, error) {
+	var editData map[string]json.RawMessage
+	if err := json.Unmarshal(data, &editData); err != nil {
+		return nil, err
+	}
+
+	newData := make(map[string]json.RawMessage)
+	oldData := make(map[string]json.RawMessage)
+	if v, ok := editData["new_data"]; ok {
+		if err := json.Unmarshal(v, &newData); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := editData["old_data"]; ok {
+		if err := json.Unmarshal(v, &oldData); err != nil {
+			return nil, err
+		}
+	}
+
+	invertedNew := make(map[string]json.RawMessage)
+	invertedOld := make(map[string]json.RawMessage)
+	for k, v := range newData {
+		switch {
+		case strings.HasPrefix(k, "added_"):
+			invertedNew["removed_"+strings.TrimPrefix(k, "added_")] = v
+		case strings.HasPrefix(k, "removed_"):
+			invertedNew["added_"+strings.TrimPrefix(k, "removed_")] = v
+		default:
+			invertedOld[k] = v
+		}
+	}
+	for k, v := range oldData {
+		if !strings.HasPrefix(k, "added_") && !strings.HasPrefix(k, "removed_") {
+			invertedNew[k] = v
+		}
+	}
+
+	ret := map[string]interface{}{
+		"new_data": invertedNew,
+		"old_data": invertedOld,
+	}
+	if v, ok := editData["merge_sources"]; ok && keepMergeSources {
+		ret["merge_sources"] = v
+	}
+
+	return json.Marshal(ret)
+}
+
+// createSnapshot records the join rows of the entities about to be removed
+// by the merge or destroy edit.
+func createSnapshot(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum, operation models.OperationEnum) error {
+	eqb := fac.Edit()
+	targetID, err := findTargetID(eqb, targetType, edit.ID)
+	if err != nil {
+		return err
+	}
+
+	if operation == models.OperationEnumDestroy {
+		return eqb.CreateSnapshot(edit.ID, targetType, []uuid.UUID{*targetID}, nil)
+	}
+
+	var sourceIDs []uuid.UUID
+	if data := edit.GetData(); data != nil {
+		for _, id := range data.MergeSources {
+			sourceID, err := uuid.FromString(id)
+			if err != nil {
+				return err
+			}
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+	}
+
+	return eqb.CreateSnapshot(edit.ID, targetType, sourceIDs, targetID)
+}
+
+// restoreSnapshot restores the entities removed by the edit reverted by the
+// restore edit.
+func restoreSnapshot(fac models.Repo, edit *models.Edit) error {
+	if !edit.RevertsID.Valid {
+		return errors.New("restore edit does not revert an edit")
+	}
+
+	eqb := fac.Edit()
+	reverted, err := eqb.Find(edit.RevertsID.UUID)
+	if err != nil {
+		return err
+	}
+	if reverted == nil || !reverted.Applied {
+		return errors.New("reverted edit is not applied")
+	}
+
+	return eqb.RestoreSnapshot(reverted.ID)
+}
+
+func findTargetID(eqb models.EditRepo, targetType models.TargetTypeEnum, editID uuid.UUID) (*uuid.UUID, error) {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		return eqb.FindTagID(editID)
+	case models.TargetTypeEnumPerformer:
+		return eqb.FindPerformerID(editID)
+	case models.TargetTypeEnumStudio:
+		return eqb.FindStudioID(editID)
+	case models.TargetTypeEnumScene:
+		return eqb.FindSceneID(editID)
+	default:
+		return nil, errors.New("Not implemented: " + targetType.String())
+	}
+}
+
+func createTargetJoin(eqb models.EditRepo, targetType models.TargetTypeEnum, editID uuid.UUID, targetID uuid.UUID) error {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		return eqb.CreateEditTag(models.EditTag{EditID: editID, TagID: targetID})
+	case models.TargetTypeEnumPerformer:
+		return eqb.CreateEditPerformer(models.EditPerformer{EditID: editID, PerformerID: targetID})
+	case models.TargetTypeEnumStudio:
+		return eqb.CreateEditStudio(models.EditStudio{EditID: editID, StudioID: targetID})
+	case models.TargetTypeEnumScene:
+		return eqb.CreateEditScene(models.EditScene{EditID: editID, SceneID: targetID})
+	default:
+		return errors.New("Not implemented: " + targetType.String())
+	}
+}
+
+func isTargetDeleted(fac models.Repo, targetType models.TargetTypeEnum, id uuid.UUID) (bool, error) {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		tag, err := fac.Tag().Find(id)
+		return tag == nil || tag.Deleted, err
+	case models.TargetTypeEnumPerformer:
+		performer, err := fac.Performer().Find(id)
+		return performer == nil || performer.Deleted, err
+	case models.TargetTypeEnumStudio:
+		studio, err := fac.Studio().Find(id)
+		return studio == nil || studio.Deleted, err
+	case models.TargetTypeEnumScene:
+		scene, err := fac.Scene().Find(id)
+		return scene == nil || scene.Deleted, err
+	default:
+		return false, errors.New("Not implemented: " + targetType.String())
+	}
+}
@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestInvertEditData(t *testing.T) {
+	data := types.JSONText(`{
+		"new_data": {"name": "new", "added_aliases": ["a"], "removed_aliases": ["b"]},
+		"old_data": {"name": "old", "description": "desc"},
+		"merge_sources": ["source"],
+		"set_merge_aliases": true
+	}`)
+
+	inverted, err := invertEditData(data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		New          map[string]interface{} `json:"new_data"`
+		Old          map[string]interface{} `json:"old_data"`
+		MergeSources []string               `json:"merge_sources"`
+		Flag         *bool                  `json:"set_merge_aliases"`
+	}
+	if err := json.Unmarshal(inverted, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.New["name"] != "old" || got.Old["name"] != "new" {
+		t.Errorf("name not swapped: new %v, old %v", got.New["name"], got.Old["name"])
+	}
+	if got.New["description"] != "desc" {
+		t.Errorf("description = %v, want desc", got.New["description"])
+	}
+	if _, ok := got.Old["description"]; ok {
+		t.Error("unexpected description in old data")
+	}
+	if added, ok := got.New["added_aliases"].([]interface{}); !ok || len(added) != 1 || added[0] != "b" {
+		t.Errorf("added_aliases = %v, want [b]", got.New["added_aliases"])
+	}
+	if removed, ok := got.New["removed_aliases"].([]interface{}); !ok || len(removed) != 1 || removed[0] != "a" {
+		t.Errorf("removed_aliases = %v, want [a]", got.New["removed_aliases"])
+	}
+	if got.MergeSources != nil {
+		t.Errorf("merge_sources = %v, want none", got.MergeSources)
+	}
+	if got.Flag != nil {
+		t.Error("unexpected set_merge_aliases flag")
+	}
+
+	inverted, err = invertEditData(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editData := (&models.Edit{Data: inverted}).GetData()
+	if editData == nil || len(editData.MergeSources) != 1 || editData.MergeSources[0] != "source" {
+		t.Errorf("merge sources not kept: %v", editData)
+	}
+}
@@ -57,6 +57,10 @@ func (m *SceneEditProcessor) modifyEdit(input models.SceneEditInput, inputSpecif
 		return errors.New("scene with id " + sceneID.String() + " not found")
 	}
 
+	if err := validateMarkers(input.Details.Markers, input.Details.Performers); err != nil {
+		return err
+	}
+
 	// perform a diff against the input and the current object
 	sceneEdit := input.Details.SceneEditFromDiff(*scene)
 
@@ -84,6 +88,10 @@ func (m *SceneEditProcessor) diffRelationships(sceneEdit *models.SceneEditData,
 		return err
 	}
 
+	if err := m.diffMarkers(sceneEdit, sceneID, input.Details.Markers); err != nil {
+		return err
+	}
+
 	return m.diffPerformers(sceneEdit, sceneID, input.Details.Performers)
 }
 
@@ -201,6 +209,79 @@ func performerAppearanceCompare(subject []*models.PerformerAppearanceInput, agai
 	return
 }
 
+func (m *SceneEditProcessor) diffMarkers(sceneEdit *models.SceneEditData, sceneID uuid.UUID, newMarkers []*models.SceneMarkerInput) error {
+	sqb := m.fac.Scene()
+
+	markers, err := sqb.GetMarkers(sceneID)
+	if err != nil {
+		return err
+	}
+	performers, err := sqb.GetMarkerPerformers(sceneID)
+	if err != nil {
+		return err
+	}
+
+	var existingMarkers []*models.SceneMarkerInput
+	for _, marker := range markers {
+		existingMarkers = append(existingMarkers, marker.ToInput(performers))
+	}
+
+	sceneEdit.New.AddedMarkers, sceneEdit.New.RemovedMarkers = markerCompare(newMarkers, existingMarkers)
+	return nil
+}
+
+// markerCompare returns the markers in subject that are not in against, and
+// the markers in against that are not in subject. Markers are compared by
+// value.
+func markerCompare(subject []*models.SceneMarkerInput, against []*models.SceneMarkerInput) (added []*models.SceneMarkerInput, missing []*models.SceneMarkerInput) {
+	counts := make(map[string]int)
+	for _, a := range against {
+		counts[a.Key()]++
+	}
+
+	for _, s := range subject {
+		key := s.Key()
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		added = append(added, s)
+	}
+
+	for _, a := range against {
+		key := a.Key()
+		if counts[key] > 0 {
+			counts[key]--
+			missing = append(missing, a)
+		}
+	}
+
+	return
+}
+
+// validateMarkers checks the marker times, and that marker performers are
+// performers of the scene.
+func validateMarkers(markers []*models.SceneMarkerInput, performers []*models.PerformerAppearanceInput) error {
+	scenePerformers := make(map[string]bool)
+	for _, p := range performers {
+		scenePerformers[p.PerformerID] = true
+	}
+
+	for _, marker := range markers {
+		if err := marker.Validate(); err != nil {
+			return err
+		}
+
+		for _, p := range marker.PerformerIds {
+			if !scenePerformers[p] {
+				return errors.New("marker performer " + p + " is not a performer of the scene")
+			}
+		}
+	}
+
+	return nil
+}
+
 func (m *SceneEditProcessor) diffImages(sceneEdit *models.SceneEditData, sceneID uuid.UUID, newImageIds []string) error {
 	iqb := m.fac.Image()
 	images, err := iqb.FindBySceneID(sceneID)
@@ -255,6 +336,10 @@ func (m *SceneEditProcessor) mergeEdit(input models.SceneEditInput, inputSpecifi
 		return errors.New("No merge sources found")
 	}
 
+	if err := validateMarkers(input.Details.Markers, input.Details.Performers); err != nil {
+		return err
+	}
+
 	// perform a diff against the input and the current object
 	sceneEdit := input.Details.SceneEditFromMerge(*scene, mergeSources)
 
@@ -266,12 +351,17 @@ func (m *SceneEditProcessor) mergeEdit(input models.SceneEditInput, inputSpecifi
 }
 
 func (m *SceneEditProcessor) createEdit(input models.SceneEditInput, inputSpecified InputSpecifiedFunc) error {
+	if err := validateMarkers(input.Details.Markers, input.Details.Performers); err != nil {
+		return err
+	}
+
 	sceneEdit := input.Details.SceneEditFromCreate()
 
 	sceneEdit.New.AddedUrls = input.Details.Urls
 	sceneEdit.New.AddedTags = input.Details.TagIds
 	sceneEdit.New.AddedImages = input.Details.ImageIds
 	sceneEdit.New.AddedPerformers = input.Details.Performers
+	sceneEdit.New.AddedMarkers = input.Details.Markers
 
 	return m.edit.SetData(sceneEdit)
 }
@@ -308,7 +398,10 @@ func (m *SceneEditProcessor) CreateJoin(input models.SceneEditInput) error {
 func (m *SceneEditProcessor) apply() error {
 	sqb := m.fac.Scene()
 	eqb := m.fac.Edit()
-	operation := m.operation()
+	operation := m.targetOperation()
+	if operation == models.OperationEnumRestore {
+		return nil
+	}
 	isCreate := operation == models.OperationEnumCreate
 
 	var scene *models.Scene
@@ -352,7 +445,7 @@ func (m *SceneEditProcessor) applyEdit(scene *models.Scene) (*models.Scene, erro
 		return nil, err
 	}
 
-	operation := m.operation()
+	operation := m.targetOperation()
 
 	switch operation {
 	case models.OperationEnumCreate:
@@ -450,9 +543,5 @@ func (m *SceneEditProcessor) mergeInto(sourceID uuid.UUID, targetID uuid.UUID) e
 		return errors.New("Merge target scene not found: " + targetID.String())
 	}
 
-	_, err = qb.SoftDelete(*scene)
-	if err != nil {
-		return err
-	}
 	return qb.MergeInto(scene, target)
 }
@@ -0,0 +1,64 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestMarkerCompare(t *testing.T) {
+	end := 60
+	title := "title"
+	intro := &models.SceneMarkerInput{Start: 0, End: &end, TagID: "tag1", PerformerIds: []string{"b", "a"}}
+	introReordered := &models.SceneMarkerInput{Start: 0, End: &end, TagID: "tag1", PerformerIds: []string{"a", "b"}}
+	titled := &models.SceneMarkerInput{Start: 0, End: &end, TagID: "tag1", Title: &title, PerformerIds: []string{"a", "b"}}
+	later := &models.SceneMarkerInput{Start: 120, TagID: "tag2"}
+
+	added, removed := markerCompare([]*models.SceneMarkerInput{introReordered, later}, []*models.SceneMarkerInput{intro})
+	if len(added) != 1 || added[0] != later {
+		t.Errorf("expected only the later marker to be added, got %v", added)
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected no removed markers, got %v", removed)
+	}
+
+	added, removed = markerCompare([]*models.SceneMarkerInput{titled}, []*models.SceneMarkerInput{intro, later})
+	if len(added) != 1 || added[0] != titled {
+		t.Errorf("expected the titled marker to be added, got %v", added)
+	}
+	if len(removed) != 2 {
+		t.Errorf("expected 2 removed markers, got %d", len(removed))
+	}
+
+	// duplicate markers are compared by count
+	added, removed = markerCompare([]*models.SceneMarkerInput{later, later}, []*models.SceneMarkerInput{later})
+	if len(added) != 1 || len(removed) != 0 {
+		t.Errorf("expected one added duplicate marker, got %d added and %d removed", len(added), len(removed))
+	}
+}
+
+func TestValidateMarkers(t *testing.T) {
+	end := 10
+	performers := []*models.PerformerAppearanceInput{{PerformerID: "a"}}
+
+	tests := []struct {
+		name    string
+		marker  models.SceneMarkerInput
+		wantErr bool
+	}{
+		{"valid", models.SceneMarkerInput{Start: 5, End: &end, PerformerIds: []string{"a"}}, false},
+		{"no end", models.SceneMarkerInput{Start: 5}, false},
+		{"negative start", models.SceneMarkerInput{Start: -1}, true},
+		{"end before start", models.SceneMarkerInput{Start: 10, End: &end}, true},
+		{"performer not in scene", models.SceneMarkerInput{Start: 0, PerformerIds: []string{"b"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMarkers([]*models.SceneMarkerInput{&tt.marker}, performers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMarkers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -188,7 +188,10 @@ func (m *StudioEditProcessor) CreateJoin(input models.StudioEditInput) error {
 func (m *StudioEditProcessor) apply() error {
 	sqb := m.fac.Studio()
 	eqb := m.fac.Edit()
-	operation := m.operation()
+	operation := m.targetOperation()
+	if operation == models.OperationEnumRestore {
+		return nil
+	}
 	isCreate := operation == models.OperationEnumCreate
 
 	var studio *models.Studio
@@ -171,7 +171,10 @@ func (m *TagEditProcessor) CreateJoin(input models.TagEditInput) error {
 func (m *TagEditProcessor) apply() error {
 	tqb := m.fac.Tag()
 	eqb := m.fac.Edit()
-	operation := m.operation()
+	operation := m.targetOperation()
+	if operation == models.OperationEnumRestore {
+		return nil
+	}
 	isCreate := operation == models.OperationEnumCreate
 
 	var tag *models.Tag
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stashapp/stash-box/pkg/email"
+	"github.com/stashapp/stash-box/pkg/image"
 	"github.com/stashapp/stash-box/pkg/logger"
 	"github.com/stashapp/stash-box/pkg/manager/config"
 	"github.com/stashapp/stash-box/pkg/manager/paths"
@@ -97,6 +98,10 @@ func initConfig() {
 		panic(err)
 	}
 
+	if err = image.ValidateVariants(config.GetImageVariants()); err != nil {
+		panic(err)
+	}
+
 	if newConfig {
 		fmt.Printf(`
 A new config file has been generated at %s.
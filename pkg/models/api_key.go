@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type APIKeyRepo interface {
+	Create(newKey APIKey) (*APIKey, error)
+	Destroy(id uuid.UUID) error
+	Find(id uuid.UUID) (*APIKey, error)
+	FindByUserID(userID uuid.UUID) (APIKeys, error)
+	// UpdateLastUsed sets the time the key was last used, if it is later
+	// than the current value.
+	UpdateLastUsed(id uuid.UUID, lastUsed time.Time) error
+}
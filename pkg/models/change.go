@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type ChangeRepo interface {
+	// Log records a change to the target entity. mergedIntoID is only set
+	// for merge operations.
+	Log(targetType TargetTypeEnum, operation OperationEnum, targetID uuid.UUID, mergedIntoID *uuid.UUID) error
+	// FindAfter returns up to limit changes with an ID greater than afterID,
+	// created at or after since if it is set, in the order they were made.
+	FindAfter(afterID int64, since *time.Time, limit int) (Changes, error)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "encoding/json"
+
+type DatasetRepo interface {
+	// Tables returns the tables included in a dataset, ordered such that
+	// each table only references the tables before it.
+	Tables() []string
+	// BeginSnapshot makes the current transaction read from a single
+	// snapshot of the database. It must be called before any other query in
+	// the transaction.
+	BeginSnapshot() error
+	// ExportRows calls fn with each row of the table, encoded as a JSON object.
+	ExportRows(table string, fn func(row json.RawMessage) error) error
+	// ImportRow inserts a row previously returned by ExportRows.
+	ImportRow(table string, row json.RawMessage) error
+}
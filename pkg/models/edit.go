@@ -17,13 +17,31 @@ type EditRepo interface {
 	FindSceneID(id uuid.UUID) (*uuid.UUID, error)
 	Count() (int, error)
 	Query(editFilter *EditFilterType, findFilter *QuerySpec) ([]*Edit, int, error)
+	QueryPage(editFilter *EditFilterType, findFilter *CursorQuerySpec) ([]*Edit, *Page, error)
 	CreateComment(newJoin EditComment) error
 	CreateVote(newJoin EditVote) error
 	GetComments(id uuid.UUID) (EditComments, error)
 	GetVotes(id uuid.UUID) (EditVotes, error)
+	// ResetVotes removes the votes cast on the edit.
+	ResetVotes(id uuid.UUID) error
+	CreateAmendment(newJoin EditAmendment) error
+	// GetAmendments returns the amendments of the edit, oldest first.
+	GetAmendments(id uuid.UUID) (EditAmendments, error)
 	FindByTagID(id uuid.UUID) ([]*Edit, error)
 	FindByPerformerID(id uuid.UUID) ([]*Edit, error)
 	FindByStudioID(id uuid.UUID) ([]*Edit, error)
 	FindBySceneID(id uuid.UUID) ([]*Edit, error)
 	FindCompletedEdits(int, int, int) ([]*Edit, error)
+	// FindReverts returns the edits reverting the edit with the provided id.
+	FindReverts(id uuid.UUID) ([]*Edit, error)
+	// FindPendingMergesBySourceID returns the pending merge edits with the
+	// provided id as one of their merge sources.
+	FindPendingMergesBySourceID(id uuid.UUID) ([]*Edit, error)
+	// CreateSnapshot records the join rows of the provided entities, which
+	// are about to be removed by the edit. targetID is the merge target, if
+	// any.
+	CreateSnapshot(editID uuid.UUID, targetType TargetTypeEnum, entityIDs []uuid.UUID, targetID *uuid.UUID) error
+	// RestoreSnapshot restores the entities removed by the edit, along with
+	// their join rows.
+	RestoreSnapshot(editID uuid.UUID) error
 }
@@ -0,0 +1,23 @@
+package models
+
+// Implies returns true if a key with the scope may perform operations
+// requiring the other scope.
+func (s APIKeyScopeEnum) Implies(other APIKeyScopeEnum) bool {
+	switch s {
+	case APIKeyScopeEnumEdit:
+		return true
+	case APIKeyScopeEnumSubmitFingerprints:
+		return other == APIKeyScopeEnumSubmitFingerprints || other == APIKeyScopeEnumRead
+	}
+
+	return s == other
+}
+
+// Roles returns the roles that a key with the scope may use.
+func (s APIKeyScopeEnum) Roles() []RoleEnum {
+	if s == APIKeyScopeEnumEdit {
+		return []RoleEnum{RoleEnumRead, RoleEnumVote, RoleEnumEdit}
+	}
+
+	return []RoleEnum{RoleEnumRead}
+}
@@ -23,3 +23,24 @@ func (ff QuerySpec) GetDirection() string {
 	}
 	return direction
 }
+
+func (ff CursorQuerySpec) GetSort(defaultSort string) string {
+	if ff.Sort == nil {
+		return defaultSort
+	}
+	return *ff.Sort
+}
+
+func (ff CursorQuerySpec) GetDirection() string {
+	if ff.Direction != nil && ff.Direction.IsValid() {
+		return ff.Direction.String()
+	}
+	return "DESC"
+}
+
+func (ff CursorQuerySpec) GetFirst(defaultFirst int) int {
+	if ff.First == nil || *ff.First < 1 {
+		return defaultFirst
+	}
+	return *ff.First
+}
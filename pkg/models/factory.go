@@ -15,10 +15,20 @@ type Repo interface {
 	Tag() TagRepo
 
 	Edit() EditRepo
+	Change() ChangeRepo
+	Revision() RevisionRepo
 
 	Joins() JoinsRepo
 
 	PendingActivation() PendingActivationRepo
 	Invite() InviteKeyRepo
 	User() UserRepo
+	APIKey() APIKeyRepo
+	Notification() NotificationRepo
+	Watch() WatchRepo
+
+	Webhook() WebhookRepo
+	Job() JobRepo
+
+	Dataset() DatasetRepo
 }
@@ -19,6 +19,23 @@ type EditTarget interface {
 	IsEditTarget()
 }
 
+type APIKeyCreateInput struct {
+	Name  string          `json:"name"`
+	Scope APIKeyScopeEnum `json:"scope"`
+	// Key is valid forever if not set
+	Expires *time.Time `json:"expires"`
+}
+
+type APIKeyCreateResult struct {
+	APIKey *APIKey `json:"api_key"`
+	// The key value. It cannot be retrieved again after creation
+	Key string `json:"key"`
+}
+
+type APIKeyRevokeInput struct {
+	ID string `json:"id"`
+}
+
 type ActivateNewUserInput struct {
 	Name          string `json:"name"`
 	Email         string `json:"email"`
@@ -45,6 +62,23 @@ type CancelEditInput struct {
 	ID string `json:"id"`
 }
 
+type ChangesSinceResultType struct {
+	Changes []*Change `json:"changes"`
+	// Opaque cursor to pass to the next changesSince query
+	Cursor string `json:"cursor"`
+	// True if there are more changes available after the returned cursor
+	HasMore bool `json:"has_more"`
+}
+
+type CursorQuerySpec struct {
+	// Number of results to return. Defaults to 25, maximum 1000
+	First *int `json:"first"`
+	// Return results after this cursor
+	After     *string            `json:"after"`
+	Sort      *string            `json:"sort"`
+	Direction *SortDirectionEnum `json:"direction"`
+}
+
 type DateCriterionInput struct {
 	Value    string            `json:"value"`
 	Modifier CriterionModifier `json:"modifier"`
@@ -55,6 +89,17 @@ type EditCommentInput struct {
 	Comment string `json:"comment"`
 }
 
+type EditConnection struct {
+	Edges      []*EditEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"page_info"`
+	TotalCount int         `json:"total_count"`
+}
+
+type EditEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Edit  `json:"node"`
+}
+
 type EditFilterType struct {
 	// Filter by user id
 	UserID *string `json:"user_id"`
@@ -160,8 +205,13 @@ type IDCriterionInput struct {
 }
 
 type ImageCreateInput struct {
+	// Source of the image. Downloaded and stored if no file is provided and fetch_remote_images is enabled
 	URL  *string         `json:"url"`
 	File *graphql.Upload `json:"file"`
+	// Performer the image is uploaded for. A warning is returned if the performer already has a similar image
+	PerformerID *string `json:"performer_id"`
+	// Studio the image is uploaded for. A warning is returned if the studio already has a similar image
+	StudioID *string `json:"studio_id"`
 }
 
 type ImageDestroyInput struct {
@@ -178,6 +228,20 @@ type IntCriterionInput struct {
 	Modifier CriterionModifier `json:"modifier"`
 }
 
+type Job struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Interval between scheduled runs. Null if the job only runs when triggered
+	Schedule *string `json:"schedule"`
+	Running  bool    `json:"running"`
+	LastRun  *JobRun `json:"last_run"`
+}
+
+type MarkNotificationsReadInput struct {
+	// Notifications to mark as read. All notifications are marked if omitted
+	Ids []string `json:"ids"`
+}
+
 type Measurements struct {
 	CupSize  *string `json:"cup_size"`
 	BandSize *int    `json:"band_size"`
@@ -202,6 +266,12 @@ type NewUserInput struct {
 	InviteKey *string `json:"invite_key"`
 }
 
+type PageInfo struct {
+	HasNextPage bool `json:"has_next_page"`
+	// Cursor of the last result, to be passed as after to fetch the next page
+	EndCursor *string `json:"end_cursor"`
+}
+
 type PerformerAppearance struct {
 	Performer *Performer `json:"performer"`
 	// Performing as alias
@@ -214,6 +284,12 @@ type PerformerAppearanceInput struct {
 	As *string `json:"as"`
 }
 
+type PerformerConnection struct {
+	Edges      []*PerformerEdge `json:"edges"`
+	PageInfo   *PageInfo        `json:"page_info"`
+	TotalCount int              `json:"total_count"`
+}
+
 type PerformerCreateInput struct {
 	Name            string              `json:"name"`
 	Disambiguation  *string             `json:"disambiguation"`
@@ -239,25 +315,31 @@ type PerformerDestroyInput struct {
 	ID string `json:"id"`
 }
 
+type PerformerEdge struct {
+	Cursor string     `json:"cursor"`
+	Node   *Performer `json:"node"`
+}
+
 type PerformerEditDetailsInput struct {
-	Name            *string             `json:"name"`
-	Disambiguation  *string             `json:"disambiguation"`
-	Aliases         []string            `json:"aliases"`
-	Gender          *GenderEnum         `json:"gender"`
-	Urls            []*URL              `json:"urls"`
-	Birthdate       *FuzzyDateInput     `json:"birthdate"`
-	Ethnicity       *EthnicityEnum      `json:"ethnicity"`
-	Country         *string             `json:"country"`
-	EyeColor        *EyeColorEnum       `json:"eye_color"`
-	HairColor       *HairColorEnum      `json:"hair_color"`
-	Height          *int                `json:"height"`
-	Measurements    *MeasurementsInput  `json:"measurements"`
-	BreastType      *BreastTypeEnum     `json:"breast_type"`
-	CareerStartYear *int                `json:"career_start_year"`
-	CareerEndYear   *int                `json:"career_end_year"`
-	Tattoos         []*BodyModification `json:"tattoos"`
-	Piercings       []*BodyModification `json:"piercings"`
-	ImageIds        []string            `json:"image_ids"`
+	Name            *string                       `json:"name"`
+	Disambiguation  *string                       `json:"disambiguation"`
+	Aliases         []string                      `json:"aliases"`
+	Gender          *GenderEnum                   `json:"gender"`
+	Urls            []*URL                        `json:"urls"`
+	Birthdate       *FuzzyDateInput               `json:"birthdate"`
+	Ethnicity       *EthnicityEnum                `json:"ethnicity"`
+	Country         *string                       `json:"country"`
+	EyeColor        *EyeColorEnum                 `json:"eye_color"`
+	HairColor       *HairColorEnum                `json:"hair_color"`
+	Height          *int                          `json:"height"`
+	Measurements    *MeasurementsInput            `json:"measurements"`
+	BreastType      *BreastTypeEnum               `json:"breast_type"`
+	CareerStartYear *int                          `json:"career_start_year"`
+	CareerEndYear   *int                          `json:"career_end_year"`
+	Tattoos         []*BodyModification           `json:"tattoos"`
+	Piercings       []*BodyModification           `json:"piercings"`
+	ImageIds        []string                      `json:"image_ids"`
+	Relationships   []*PerformerRelationshipInput `json:"relationships"`
 }
 
 type PerformerEditInput struct {
@@ -310,6 +392,26 @@ type PerformerFilterType struct {
 	CareerEndYear   *IntCriterionInput              `json:"career_end_year"`
 	Tattoos         *BodyModificationCriterionInput `json:"tattoos"`
 	Piercings       *BodyModificationCriterionInput `json:"piercings"`
+	// Filter to performers with a matching relationship
+	Relationship *PerformerRelationshipCriterionInput `json:"relationship"`
+}
+
+type PerformerRelationship struct {
+	Performer *Performer `json:"performer"`
+	// Relationship of the related performer to this performer
+	Type PerformerRelationshipType `json:"type"`
+}
+
+type PerformerRelationshipCriterionInput struct {
+	// Only include relationships with this performer
+	PerformerID *string `json:"performer_id"`
+	// Only include relationships of this type
+	Type *PerformerRelationshipType `json:"type"`
+}
+
+type PerformerRelationshipInput struct {
+	PerformerID string                    `json:"performer_id"`
+	Type        PerformerRelationshipType `json:"type"`
 }
 
 type PerformerUpdateInput struct {
@@ -376,10 +478,30 @@ type QueryUsersResultType struct {
 	Users []*User `json:"users"`
 }
 
+type QueryWebhookDeliveriesResultType struct {
+	Count      int                `json:"count"`
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
 type ResetPasswordInput struct {
 	Email string `json:"email"`
 }
 
+type RevertEditInput struct {
+	// Applied edit to revert
+	ID      string  `json:"id"`
+	Comment *string `json:"comment"`
+}
+
+type RevisionDiff struct {
+	From *Revision `json:"from"`
+	To   *Revision `json:"to"`
+	// Values of the changed fields in from
+	OldDetails EditDetails `json:"old_details"`
+	// Values of the changed fields in to, and the list entries added and removed
+	Details EditDetails `json:"details"`
+}
+
 type RevokeInviteInput struct {
 	UserID string `json:"user_id"`
 	Amount int    `json:"amount"`
@@ -390,6 +512,12 @@ type RoleCriterionInput struct {
 	Modifier CriterionModifier `json:"modifier"`
 }
 
+type SceneConnection struct {
+	Edges      []*SceneEdge `json:"edges"`
+	PageInfo   *PageInfo    `json:"page_info"`
+	TotalCount int          `json:"total_count"`
+}
+
 type SceneCreateInput struct {
 	Title        *string                     `json:"title"`
 	Details      *string                     `json:"details"`
@@ -408,6 +536,11 @@ type SceneDestroyInput struct {
 	ID string `json:"id"`
 }
 
+type SceneEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Scene `json:"node"`
+}
+
 type SceneEditDetailsInput struct {
 	Title      *string                     `json:"title"`
 	Details    *string                     `json:"details"`
@@ -417,6 +550,7 @@ type SceneEditDetailsInput struct {
 	Performers []*PerformerAppearanceInput `json:"performers"`
 	TagIds     []string                    `json:"tag_ids"`
 	ImageIds   []string                    `json:"image_ids"`
+	Markers    []*SceneMarkerInput         `json:"markers"`
 	Duration   *int                        `json:"duration"`
 	Director   *string                     `json:"director"`
 }
@@ -451,6 +585,26 @@ type SceneFilterType struct {
 	Fingerprints *MultiIDCriterionInput `json:"fingerprints"`
 }
 
+// Scene marker values of an edit
+type SceneMarkerDetails struct {
+	Title      *string      `json:"title"`
+	Start      int          `json:"start"`
+	End        *int         `json:"end"`
+	Tag        *Tag         `json:"tag"`
+	Performers []*Performer `json:"performers"`
+}
+
+type SceneMarkerInput struct {
+	Title *string `json:"title"`
+	// Start time in seconds
+	Start int `json:"start"`
+	// End time in seconds
+	End   *int   `json:"end"`
+	TagID string `json:"tag_id"`
+	// Must be performers of the scene
+	PerformerIds []string `json:"performer_ids"`
+}
+
 type SceneUpdateInput struct {
 	ID           string                      `json:"id"`
 	Title        *string                     `json:"title"`
@@ -466,6 +620,12 @@ type SceneUpdateInput struct {
 	Director     *string                     `json:"director"`
 }
 
+type SimilarImage struct {
+	Image *Image `json:"image"`
+	// Hamming distance between the perceptual hashes of the images
+	Distance int `json:"distance"`
+}
+
 type StashBoxConfig struct {
 	HostURL                    string `json:"host_url"`
 	RequireInvite              bool   `json:"require_invite"`
@@ -475,6 +635,12 @@ type StashBoxConfig struct {
 	VotingPeriod               int    `json:"voting_period"`
 	MinDestructiveVotingPeriod int    `json:"min_destructive_voting_period"`
 	VoteCronInterval           string `json:"vote_cron_interval"`
+	// Votes are weighted by the reputation of the voter
+	WeightedVoting bool `json:"weighted_voting"`
+	// Users may log in with an external OpenID Connect provider at /oidc/login
+	OidcLogin bool `json:"oidc_login"`
+	// Voting rules for each target type and operation
+	VotingPolicies []*VotingPolicy `json:"voting_policies"`
 }
 
 type StringCriterionInput struct {
@@ -482,6 +648,12 @@ type StringCriterionInput struct {
 	Modifier CriterionModifier `json:"modifier"`
 }
 
+type StudioConnection struct {
+	Edges      []*StudioEdge `json:"edges"`
+	PageInfo   *PageInfo     `json:"page_info"`
+	TotalCount int           `json:"total_count"`
+}
+
 type StudioCreateInput struct {
 	Name     string   `json:"name"`
 	Urls     []*URL   `json:"urls"`
@@ -493,6 +665,11 @@ type StudioDestroyInput struct {
 	ID string `json:"id"`
 }
 
+type StudioEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   *Studio `json:"node"`
+}
+
 type StudioEditDetailsInput struct {
 	Name     *string  `json:"name"`
 	Urls     []*URL   `json:"urls"`
@@ -525,6 +702,25 @@ type StudioUpdateInput struct {
 	ImageIds []string `json:"image_ids"`
 }
 
+type TOTPCodeInput struct {
+	// Code from the authenticator app
+	Code string `json:"code"`
+}
+
+type TOTPDisableInput struct {
+	// Code from the authenticator app, or a recovery code. Not required for admins disabling another user's two-factor authentication
+	Code *string `json:"code"`
+	// Defaults to the current user
+	UserID *string `json:"user_id"`
+}
+
+type TOTPEnrollResult struct {
+	// Base32 encoded secret, to be entered into an authenticator app
+	Secret string `json:"secret"`
+	// otpauth URI of the secret, to be displayed as a QR code
+	URI string `json:"uri"`
+}
+
 type TagCategoryCreateInput struct {
 	Name        string       `json:"name"`
 	Group       TagGroupEnum `json:"group"`
@@ -542,6 +738,12 @@ type TagCategoryUpdateInput struct {
 	Description *string       `json:"description"`
 }
 
+type TagConnection struct {
+	Edges      []*TagEdge `json:"edges"`
+	PageInfo   *PageInfo  `json:"page_info"`
+	TotalCount int        `json:"total_count"`
+}
+
 type TagCreateInput struct {
 	Name        string   `json:"name"`
 	Description *string  `json:"description"`
@@ -553,6 +755,11 @@ type TagDestroyInput struct {
 	ID string `json:"id"`
 }
 
+type TagEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Tag   `json:"node"`
+}
+
 type TagEditDetailsInput struct {
 	Name        *string  `json:"name"`
 	Description *string  `json:"description"`
@@ -638,6 +845,19 @@ type UserFilterType struct {
 	InvitedBy *string `json:"invited_by"`
 }
 
+type UserReputation struct {
+	// Share of outcomes agreeing with the user, between 0 and 1. Users without history score 0.5
+	Score float64 `json:"score"`
+	// Weight of the user's votes when weighted voting is enabled, between 0 and 2
+	Weight float64 `json:"weight"`
+	// Accept and reject votes on closed edits matching the outcome
+	MatchingVotes int `json:"matching_votes"`
+	// Accept and reject votes on closed edits opposing the outcome
+	OpposingVotes int `json:"opposing_votes"`
+	AcceptedEdits int `json:"accepted_edits"`
+	RejectedEdits int `json:"rejected_edits"`
+}
+
 type UserUpdateInput struct {
 	ID   string  `json:"id"`
 	Name *string `json:"name"`
@@ -661,6 +881,113 @@ type Version struct {
 	Version   string `json:"version"`
 }
 
+type VotingPolicy struct {
+	TargetType TargetTypeEnum `json:"target_type"`
+	Operation  OperationEnum  `json:"operation"`
+	// Duration, in seconds, before votes may close the edit
+	MinVotingPeriod int `json:"min_voting_period"`
+	// Number of unanimous votes required for immediate approval. 0 if disabled
+	VoteApplicationThreshold int `json:"vote_application_threshold"`
+	// Vote count required for approval at the end of the voting period
+	ApprovalThreshold int `json:"approval_threshold"`
+	// Number of distinct users that must vote for or against the edit for it to be approved
+	Quorum int `json:"quorum"`
+	// Roles allowed to cast immediate accept and reject votes
+	ImmediateVoteRoles []RoleEnum `json:"immediate_vote_roles"`
+}
+
+type WatchInput struct {
+	TargetType TargetTypeEnum `json:"target_type"`
+	ID         string         `json:"id"`
+}
+
+type WatchedActivity struct {
+	// Scenes featuring watched performers, studios or tags, newest first
+	Scenes []*Scene `json:"scenes"`
+	// Pending edits targeting watched entities, newest first
+	Edits []*Edit `json:"edits"`
+}
+
+type WebhookCreateInput struct {
+	URL string `json:"url"`
+	// Used to sign the payload. Sent as HMAC-SHA256 in the X-StashBox-Signature header
+	Secret      string           `json:"secret"`
+	Active      *bool            `json:"active"`
+	TargetTypes []TargetTypeEnum `json:"target_types"`
+	Operations  []OperationEnum  `json:"operations"`
+}
+
+type WebhookDeliveryFilterType struct {
+	WebhookID  *string                    `json:"webhook_id"`
+	TargetType *TargetTypeEnum            `json:"target_type"`
+	TargetID   *string                    `json:"target_id"`
+	Operation  *OperationEnum             `json:"operation"`
+	Status     *WebhookDeliveryStatusEnum `json:"status"`
+}
+
+type WebhookDestroyInput struct {
+	ID string `json:"id"`
+}
+
+type WebhookRedeliverInput struct {
+	ID string `json:"id"`
+}
+
+type WebhookUpdateInput struct {
+	ID          string           `json:"id"`
+	URL         *string          `json:"url"`
+	Secret      *string          `json:"secret"`
+	Active      *bool            `json:"active"`
+	TargetTypes []TargetTypeEnum `json:"target_types"`
+	Operations  []OperationEnum  `json:"operations"`
+}
+
+type APIKeyScopeEnum string
+
+const (
+	// May only read data
+	APIKeyScopeEnumRead APIKeyScopeEnum = "READ"
+	// May read data and submit fingerprints
+	APIKeyScopeEnumSubmitFingerprints APIKeyScopeEnum = "SUBMIT_FINGERPRINTS"
+	// May read data, submit fingerprints, and create and vote on edits
+	APIKeyScopeEnumEdit APIKeyScopeEnum = "EDIT"
+)
+
+var AllAPIKeyScopeEnum = []APIKeyScopeEnum{
+	APIKeyScopeEnumRead,
+	APIKeyScopeEnumSubmitFingerprints,
+	APIKeyScopeEnumEdit,
+}
+
+func (e APIKeyScopeEnum) IsValid() bool {
+	switch e {
+	case APIKeyScopeEnumRead, APIKeyScopeEnumSubmitFingerprints, APIKeyScopeEnumEdit:
+		return true
+	}
+	return false
+}
+
+func (e APIKeyScopeEnum) String() string {
+	return string(e)
+}
+
+func (e *APIKeyScopeEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = APIKeyScopeEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid APIKeyScopeEnum", str)
+	}
+	return nil
+}
+
+func (e APIKeyScopeEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type BreastTypeEnum string
 
 const (
@@ -1160,6 +1487,109 @@ func (e HairColorEnum) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type JobStatusEnum string
+
+const (
+	JobStatusEnumRunning   JobStatusEnum = "RUNNING"
+	JobStatusEnumSucceeded JobStatusEnum = "SUCCEEDED"
+	JobStatusEnumFailed    JobStatusEnum = "FAILED"
+	JobStatusEnumCancelled JobStatusEnum = "CANCELLED"
+)
+
+var AllJobStatusEnum = []JobStatusEnum{
+	JobStatusEnumRunning,
+	JobStatusEnumSucceeded,
+	JobStatusEnumFailed,
+	JobStatusEnumCancelled,
+}
+
+func (e JobStatusEnum) IsValid() bool {
+	switch e {
+	case JobStatusEnumRunning, JobStatusEnumSucceeded, JobStatusEnumFailed, JobStatusEnumCancelled:
+		return true
+	}
+	return false
+}
+
+func (e JobStatusEnum) String() string {
+	return string(e)
+}
+
+func (e *JobStatusEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JobStatusEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JobStatusEnum", str)
+	}
+	return nil
+}
+
+func (e JobStatusEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NotificationTypeEnum string
+
+const (
+	// A comment was added to an edit submitted or voted on by the user
+	NotificationTypeEnumEditComment NotificationTypeEnum = "EDIT_COMMENT"
+	// A reject vote was cast on an edit submitted by the user
+	NotificationTypeEnumEditDownVote NotificationTypeEnum = "EDIT_DOWN_VOTE"
+	// An edit submitted or voted on by the user was applied
+	NotificationTypeEnumEditApplied NotificationTypeEnum = "EDIT_APPLIED"
+	// An edit submitted or voted on by the user was rejected
+	NotificationTypeEnumEditRejected NotificationTypeEnum = "EDIT_REJECTED"
+	// An edit submitted or voted on by the user failed to apply
+	NotificationTypeEnumEditFailed NotificationTypeEnum = "EDIT_FAILED"
+	// An edit voted on by the user was amended, resetting its votes
+	NotificationTypeEnumEditAmended NotificationTypeEnum = "EDIT_AMENDED"
+	// An edit was submitted for an entity watched by the user
+	NotificationTypeEnumWatchedEntityEdit NotificationTypeEnum = "WATCHED_ENTITY_EDIT"
+)
+
+var AllNotificationTypeEnum = []NotificationTypeEnum{
+	NotificationTypeEnumEditComment,
+	NotificationTypeEnumEditDownVote,
+	NotificationTypeEnumEditApplied,
+	NotificationTypeEnumEditRejected,
+	NotificationTypeEnumEditFailed,
+	NotificationTypeEnumEditAmended,
+	NotificationTypeEnumWatchedEntityEdit,
+}
+
+func (e NotificationTypeEnum) IsValid() bool {
+	switch e {
+	case NotificationTypeEnumEditComment, NotificationTypeEnumEditDownVote, NotificationTypeEnumEditApplied, NotificationTypeEnumEditRejected, NotificationTypeEnumEditFailed, NotificationTypeEnumEditAmended, NotificationTypeEnumWatchedEntityEdit:
+		return true
+	}
+	return false
+}
+
+func (e NotificationTypeEnum) String() string {
+	return string(e)
+}
+
+func (e *NotificationTypeEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationTypeEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationTypeEnum", str)
+	}
+	return nil
+}
+
+func (e NotificationTypeEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type OperationEnum string
 
 const (
@@ -1167,6 +1597,8 @@ const (
 	OperationEnumModify  OperationEnum = "MODIFY"
 	OperationEnumDestroy OperationEnum = "DESTROY"
 	OperationEnumMerge   OperationEnum = "MERGE"
+	// Restores the entities removed by a reverted merge or destroy edit
+	OperationEnumRestore OperationEnum = "RESTORE"
 )
 
 var AllOperationEnum = []OperationEnum{
@@ -1174,11 +1606,12 @@ var AllOperationEnum = []OperationEnum{
 	OperationEnumModify,
 	OperationEnumDestroy,
 	OperationEnumMerge,
+	OperationEnumRestore,
 }
 
 func (e OperationEnum) IsValid() bool {
 	switch e {
-	case OperationEnumCreate, OperationEnumModify, OperationEnumDestroy, OperationEnumMerge:
+	case OperationEnumCreate, OperationEnumModify, OperationEnumDestroy, OperationEnumMerge, OperationEnumRestore:
 		return true
 	}
 	return false
@@ -1205,6 +1638,54 @@ func (e OperationEnum) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type PerformerRelationshipType string
+
+const (
+	// Alternate identity of the same person
+	PerformerRelationshipTypeSamePerson PerformerRelationshipType = "SAME_PERSON"
+	// Related performer is a member of this group or duo act
+	PerformerRelationshipTypeGroupMember PerformerRelationshipType = "GROUP_MEMBER"
+	// This performer is a member of the related group or duo act
+	PerformerRelationshipTypeMemberOf PerformerRelationshipType = "MEMBER_OF"
+	PerformerRelationshipTypeSibling  PerformerRelationshipType = "SIBLING"
+)
+
+var AllPerformerRelationshipType = []PerformerRelationshipType{
+	PerformerRelationshipTypeSamePerson,
+	PerformerRelationshipTypeGroupMember,
+	PerformerRelationshipTypeMemberOf,
+	PerformerRelationshipTypeSibling,
+}
+
+func (e PerformerRelationshipType) IsValid() bool {
+	switch e {
+	case PerformerRelationshipTypeSamePerson, PerformerRelationshipTypeGroupMember, PerformerRelationshipTypeMemberOf, PerformerRelationshipTypeSibling:
+		return true
+	}
+	return false
+}
+
+func (e PerformerRelationshipType) String() string {
+	return string(e)
+}
+
+func (e *PerformerRelationshipType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PerformerRelationshipType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PerformerRelationshipType", str)
+	}
+	return nil
+}
+
+func (e PerformerRelationshipType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type RoleEnum string
 
 const (
@@ -1486,3 +1967,46 @@ func (e *VoteTypeEnum) UnmarshalGQL(v interface{}) error {
 func (e VoteTypeEnum) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type WebhookDeliveryStatusEnum string
+
+const (
+	WebhookDeliveryStatusEnumPending   WebhookDeliveryStatusEnum = "PENDING"
+	WebhookDeliveryStatusEnumSucceeded WebhookDeliveryStatusEnum = "SUCCEEDED"
+	WebhookDeliveryStatusEnumFailed    WebhookDeliveryStatusEnum = "FAILED"
+)
+
+var AllWebhookDeliveryStatusEnum = []WebhookDeliveryStatusEnum{
+	WebhookDeliveryStatusEnumPending,
+	WebhookDeliveryStatusEnumSucceeded,
+	WebhookDeliveryStatusEnumFailed,
+}
+
+func (e WebhookDeliveryStatusEnum) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusEnumPending, WebhookDeliveryStatusEnumSucceeded, WebhookDeliveryStatusEnumFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatusEnum) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatusEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatusEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatusEnum", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatusEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
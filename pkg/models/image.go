@@ -16,6 +16,31 @@ type ImageRepo interface {
 	FindByPerformerID(performerID uuid.UUID) (Images, error)
 	FindByStudioID(studioID uuid.UUID) ([]*Image, error)
 	FindIdsByStudioIds(ids []uuid.UUID) ([][]uuid.UUID, []error)
+	Update(updatedImage Image) (*Image, error)
+
+	// CreateVariant stores the variant, replacing an existing variant of the
+	// same name.
+	CreateVariant(newVariant ImageVariant) error
+	FindVariant(imageID uuid.UUID, name string) (*ImageVariant, error)
+	FindVariants(imageID uuid.UUID) (ImageVariants, error)
+	FindVariantsByImageIDs(imageIDs []uuid.UUID) (ImageVariants, error)
+	// FindMissingVariants returns stored raster images with ids after
+	// afterID that lack any of the named variants, ordered by id.
+	FindMissingVariants(names []string, afterID uuid.UUID, limit int) ([]*Image, error)
+
+	// FindSimilar returns up to limit images with perceptual hashes within
+	// distance of phash, closest first. If performerID or studioID are set,
+	// only images of that performer or studio are returned.
+	FindSimilar(phash int64, distance int, performerID *uuid.UUID, studioID *uuid.UUID, limit int) (Images, error)
+	// FindMissingPHashes returns stored raster images with ids after afterID
+	// that have no perceptual hash, ordered by id.
+	FindMissingPHashes(afterID uuid.UUID, limit int) ([]*Image, error)
+	// FindURLOnly returns images with ids after afterID that have a URL but
+	// no stored file, ordered by id.
+	FindURLOnly(afterID uuid.UUID, limit int) ([]*Image, error)
+	// FindStored returns images with ids after afterID that have a stored
+	// file, ordered by id.
+	FindStored(afterID uuid.UUID, limit int) ([]*Image, error)
 }
 
 type ImageCreator interface {
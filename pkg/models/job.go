@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+type JobRepo interface {
+	CreateRun(newRun JobRun) (*JobRun, error)
+	UpdateRun(updatedRun JobRun) (*JobRun, error)
+	FindRun(id uuid.UUID) (*JobRun, error)
+	// FindRuns returns the runs of the named job, or of all jobs if job is
+	// nil, newest first.
+	FindRuns(job *string, limit int) (JobRuns, error)
+	FindLastRun(job string) (*JobRun, error)
+	// FailRunning marks runs left running by a previous process as failed.
+	FailRunning(reason string) error
+	// PruneRuns deletes all but the newest keep runs of the named job.
+	PruneRuns(job string, keep int) error
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type APIKey struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
+	Name       string          `db:"name" json:"name"`
+	Scope      string          `db:"scope" json:"scope"`
+	ExpiresAt  sql.NullTime    `db:"expires_at" json:"expires_at"`
+	LastUsedAt sql.NullTime    `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func (p APIKey) GetID() uuid.UUID {
+	return p.ID
+}
+
+func (p *APIKey) CopyFromCreateInput(input APIKeyCreateInput) {
+	p.Name = input.Name
+	p.Scope = input.Scope.String()
+	if input.Expires != nil {
+		p.ExpiresAt = sql.NullTime{Time: *input.Expires, Valid: true}
+	}
+}
+
+// IsExpired returns true if the key has an expiry time before t.
+func (p APIKey) IsExpired(t time.Time) bool {
+	return p.ExpiresAt.Valid && p.ExpiresAt.Time.Before(t)
+}
+
+type APIKeys []*APIKey
+
+func (p APIKeys) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *APIKeys) Add(o interface{}) {
+	*p = append(*p, o.(*APIKey))
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+type Change struct {
+	ID           int64           `db:"id" json:"id"`
+	TargetType   string          `db:"target_type" json:"target_type"`
+	TargetID     uuid.UUID       `db:"target_id" json:"target_id"`
+	Operation    string          `db:"operation" json:"operation"`
+	MergedIntoID uuid.NullUUID   `db:"merged_into_id" json:"merged_into_id"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+type Changes []*Change
+
+func (p Changes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Changes) Add(o interface{}) {
+	*p = append(*p, o.(*Change))
+}
@@ -20,6 +20,11 @@ type Edit struct {
 	Data       types.JSONText  `db:"data" json:"data"`
 	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt  SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	// RevertsID is the id of the applied edit that this edit reverts
+	RevertsID uuid.NullUUID `db:"reverts_id" json:"reverts_id"`
+	// Stale is set when a conflicting edit has been applied, and the old
+	// values of this edit no longer match the target
+	Stale bool `db:"stale" json:"stale"`
 }
 
 type EditComment struct {
@@ -37,6 +42,17 @@ type EditVote struct {
 	Vote      string          `db:"vote" json:"vote"`
 }
 
+// EditAmendment records a change to the details of a pending edit by its
+// submitter.
+type EditAmendment struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	UserID    uuid.NullUUID   `db:"user_id" json:"user_id"`
+	OldData   types.JSONText  `db:"old_data" json:"old_data"`
+	Data      types.JSONText  `db:"data" json:"data"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
 func NewEdit(UUID uuid.UUID, user *User, targetType TargetTypeEnum, input *EditInput) *Edit {
 	currentTime := time.Now()
 
@@ -67,6 +83,19 @@ func NewEditComment(UUID uuid.UUID, user *User, edit *Edit, text string) *EditCo
 	return ret
 }
 
+func NewEditAmendment(UUID uuid.UUID, user *User, edit *Edit, oldData types.JSONText) *EditAmendment {
+	ret := &EditAmendment{
+		ID:        UUID,
+		EditID:    edit.ID,
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		OldData:   oldData,
+		Data:      edit.Data,
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	return ret
+}
+
 func (e Edit) GetID() uuid.UUID {
 	return e.ID
 }
@@ -277,33 +306,35 @@ type TagEditData struct {
 func (PerformerEdit) IsEditDetails() {}
 
 type PerformerEdit struct {
-	Name              *string             `json:"name,omitempty"`
-	Disambiguation    *string             `json:"disambiguation,omitempty"`
-	AddedAliases      []string            `json:"added_aliases,omitempty"`
-	RemovedAliases    []string            `json:"removed_aliases,omitempty"`
-	Gender            *string             `json:"gender,omitempty"`
-	AddedUrls         []*URL              `json:"added_urls,omitempty"`
-	RemovedUrls       []*URL              `json:"removed_urls,omitempty"`
-	Birthdate         *string             `json:"birthdate,omitempty"`
-	BirthdateAccuracy *string             `json:"birthdate_accuracy,omitempty"`
-	Ethnicity         *string             `json:"ethnicity,omitempty"`
-	Country           *string             `json:"country,omitempty"`
-	EyeColor          *string             `json:"eye_color,omitempty"`
-	HairColor         *string             `json:"hair_color,omitempty"`
-	Height            *int64              `json:"height,omitempty"`
-	CupSize           *string             `json:"cup_size,omitempty"`
-	BandSize          *int64              `json:"band_size,omitempty"`
-	WaistSize         *int64              `json:"waist_size,omitempty"`
-	HipSize           *int64              `json:"hip_size,omitempty"`
-	BreastType        *string             `json:"breast_type,omitempty"`
-	CareerStartYear   *int64              `json:"career_start_year,omitempty"`
-	CareerEndYear     *int64              `json:"career_end_year,omitempty"`
-	AddedTattoos      []*BodyModification `json:"added_tattoos,omitempty"`
-	RemovedTattoos    []*BodyModification `json:"removed_tattoos,omitempty"`
-	AddedPiercings    []*BodyModification `json:"added_piercings,omitempty"`
-	RemovedPiercings  []*BodyModification `json:"removed_piercings,omitempty"`
-	AddedImages       []string            `json:"added_images,omitempty"`
-	RemovedImages     []string            `json:"removed_images,omitempty"`
+	Name                 *string                       `json:"name,omitempty"`
+	Disambiguation       *string                       `json:"disambiguation,omitempty"`
+	AddedAliases         []string                      `json:"added_aliases,omitempty"`
+	RemovedAliases       []string                      `json:"removed_aliases,omitempty"`
+	Gender               *string                       `json:"gender,omitempty"`
+	AddedUrls            []*URL                        `json:"added_urls,omitempty"`
+	RemovedUrls          []*URL                        `json:"removed_urls,omitempty"`
+	Birthdate            *string                       `json:"birthdate,omitempty"`
+	BirthdateAccuracy    *string                       `json:"birthdate_accuracy,omitempty"`
+	Ethnicity            *string                       `json:"ethnicity,omitempty"`
+	Country              *string                       `json:"country,omitempty"`
+	EyeColor             *string                       `json:"eye_color,omitempty"`
+	HairColor            *string                       `json:"hair_color,omitempty"`
+	Height               *int64                        `json:"height,omitempty"`
+	CupSize              *string                       `json:"cup_size,omitempty"`
+	BandSize             *int64                        `json:"band_size,omitempty"`
+	WaistSize            *int64                        `json:"waist_size,omitempty"`
+	HipSize              *int64                        `json:"hip_size,omitempty"`
+	BreastType           *string                       `json:"breast_type,omitempty"`
+	CareerStartYear      *int64                        `json:"career_start_year,omitempty"`
+	CareerEndYear        *int64                        `json:"career_end_year,omitempty"`
+	AddedTattoos         []*BodyModification           `json:"added_tattoos,omitempty"`
+	RemovedTattoos       []*BodyModification           `json:"removed_tattoos,omitempty"`
+	AddedPiercings       []*BodyModification           `json:"added_piercings,omitempty"`
+	RemovedPiercings     []*BodyModification           `json:"removed_piercings,omitempty"`
+	AddedImages          []string                      `json:"added_images,omitempty"`
+	RemovedImages        []string                      `json:"removed_images,omitempty"`
+	AddedRelationships   []*PerformerRelationshipInput `json:"added_relationships,omitempty"`
+	RemovedRelationships []*PerformerRelationshipInput `json:"removed_relationships,omitempty"`
 }
 
 type PerformerEditData struct {
@@ -348,6 +379,8 @@ type SceneEdit struct {
 	RemovedImages       []string                    `json:"removed_images,omitempty"`
 	AddedFingerprints   []*FingerprintEditInput     `json:"added_fingerprints,omitempty"`
 	RemovedFingerprints []*FingerprintEditInput     `json:"removed_fingerprints,omitempty"`
+	AddedMarkers        []*SceneMarkerInput         `json:"added_markers,omitempty"`
+	RemovedMarkers      []*SceneMarkerInput         `json:"removed_markers,omitempty"`
 	Duration            *int64                      `json:"duration,omitempty"`
 	Director            *string                     `json:"director,omitempty"`
 }
@@ -378,6 +411,18 @@ func (p *EditComments) Add(o interface{}) {
 	*p = append(*p, o.(*EditComment))
 }
 
+type EditAmendments []*EditAmendment
+
+func (p EditAmendments) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditAmendments) Add(o interface{}) {
+	*p = append(*p, o.(*EditAmendment))
+}
+
 type EditVotes []*EditVote
 
 func (p EditVotes) Each(fn func(interface{})) {
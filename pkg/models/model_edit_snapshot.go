@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// EditSnapshot holds the join rows of the entities removed by an applied
+// merge or destroy edit.
+type EditSnapshot struct {
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	Data      types.JSONText  `db:"data" json:"data"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func (s *EditSnapshot) SetData(data EditSnapshotData) error {
+	buffer, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	s.Data = buffer
+	return nil
+}
+
+func (s *EditSnapshot) GetData() (*EditSnapshotData, error) {
+	data := EditSnapshotData{}
+	if err := json.Unmarshal(s.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+type EditSnapshots []*EditSnapshot
+
+func (p EditSnapshots) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditSnapshots) Add(o interface{}) {
+	*p = append(*p, o.(*EditSnapshot))
+}
+
+type EditSnapshotData struct {
+	TargetType string `json:"target_type"`
+	// EntityIDs are the ids of the merge sources or the destroyed entity
+	EntityIDs []uuid.UUID `json:"entity_ids"`
+	// TargetID is the id of the merge target
+	TargetID *uuid.UUID          `json:"target_id,omitempty"`
+	Tables   []*EditSnapshotRows `json:"tables"`
+}
+
+// EditSnapshotRows are the rows of a table referencing an entity, as JSON
+// objects.
+type EditSnapshotRows struct {
+	Table    string          `json:"table"`
+	Column   string          `json:"column"`
+	EntityID uuid.UUID       `json:"entity_id"`
+	Rows     json.RawMessage `json:"rows"`
+	// TargetRows are the rows referencing the merge target before the merge
+	TargetRows json.RawMessage `json:"target_rows,omitempty"`
+}
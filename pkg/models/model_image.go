@@ -13,6 +13,7 @@ type Image struct {
 	Checksum  string         `db:"checksum" json:"checksum"`
 	Width     int64          `db:"width" json:"width"`
 	Height    int64          `db:"height" json:"height"`
+	PHash     sql.NullInt64  `db:"phash" json:"phash"`
 }
 
 func (p Image) GetID() uuid.UUID {
@@ -75,3 +76,23 @@ func (p *Image) CopyFromCreateInput(input ImageCreateInput) {
 func (p *Image) CopyFromUpdateInput(input ImageUpdateInput) {
 	CopyFull(p, input)
 }
+
+type ImageVariant struct {
+	ImageID uuid.UUID `db:"image_id" json:"image_id"`
+	Name    string    `db:"name" json:"name"`
+	Format  string    `db:"format" json:"format"`
+	Width   int64     `db:"width" json:"width"`
+	Height  int64     `db:"height" json:"height"`
+}
+
+type ImageVariants []*ImageVariant
+
+func (p ImageVariants) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *ImageVariants) Add(o interface{}) {
+	*p = append(*p, o.(*ImageVariant))
+}
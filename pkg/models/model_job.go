@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type JobRun struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	Job        string          `db:"job" json:"job"`
+	Status     string          `db:"status" json:"status"`
+	Progress   sql.NullFloat64 `db:"progress" json:"progress"`
+	Error      sql.NullString  `db:"error" json:"error"`
+	UserID     uuid.NullUUID   `db:"user_id" json:"user_id"`
+	StartedAt  SQLiteTimestamp `db:"started_at" json:"started_at"`
+	FinishedAt sql.NullTime    `db:"finished_at" json:"finished_at"`
+}
+
+func (p JobRun) GetID() uuid.UUID {
+	return p.ID
+}
+
+func NewJobRun(UUID uuid.UUID, job string, userID *uuid.UUID) *JobRun {
+	ret := &JobRun{
+		ID:        UUID,
+		Job:       job,
+		Status:    JobStatusEnumRunning.String(),
+		StartedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+	if userID != nil {
+		ret.UserID = uuid.NullUUID{UUID: *userID, Valid: true}
+	}
+
+	return ret
+}
+
+// Finish records the outcome of the run.
+func (p *JobRun) Finish(status JobStatusEnum, err error) {
+	p.Status = status.String()
+	if err != nil {
+		p.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+	p.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+type JobRuns []*JobRun
+
+func (p JobRuns) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *JobRuns) Add(o interface{}) {
+	*p = append(*p, o.(*JobRun))
+}
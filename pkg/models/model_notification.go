@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type Notification struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	Type      string          `db:"type" json:"type"`
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	Read      bool            `db:"read" json:"read"`
+	Emailed   bool            `db:"emailed" json:"emailed"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewNotification(UUID uuid.UUID, userID uuid.UUID, notificationType NotificationTypeEnum, editID uuid.UUID) *Notification {
+	return &Notification{
+		ID:        UUID,
+		UserID:    userID,
+		Type:      notificationType.String(),
+		EditID:    editID,
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+func (n Notification) GetID() uuid.UUID {
+	return n.ID
+}
+
+type Notifications []*Notification
+
+func (p Notifications) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Notifications) Add(o interface{}) {
+	*p = append(*p, o.(*Notification))
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+// PerformerRelation is a stored relationship between two performers.
+// Symmetric relationships are stored once, with the lower performer id
+// first. Group membership is stored as GROUP_MEMBER, with the group first.
+type PerformerRelation struct {
+	PerformerID        uuid.UUID `db:"performer_id" json:"performer_id"`
+	RelatedPerformerID uuid.UUID `db:"related_performer_id" json:"related_performer_id"`
+	Type               string    `db:"type" json:"type"`
+}
+
+func (r PerformerRelation) ID() string {
+	return r.PerformerID.String() + r.RelatedPerformerID.String() + r.Type
+}
+
+// ToInput returns the relationship from the perspective of the provided
+// performer.
+func (r PerformerRelation) ToInput(performerID uuid.UUID) *PerformerRelationshipInput {
+	relType := PerformerRelationshipType(r.Type)
+	otherID := r.RelatedPerformerID
+	if r.RelatedPerformerID == performerID {
+		otherID = r.PerformerID
+		if relType == PerformerRelationshipTypeGroupMember {
+			relType = PerformerRelationshipTypeMemberOf
+		}
+	}
+
+	return &PerformerRelationshipInput{
+		PerformerID: otherID.String(),
+		Type:        relType,
+	}
+}
+
+type PerformerRelations []*PerformerRelation
+
+func (p PerformerRelations) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p PerformerRelations) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *PerformerRelations) Add(o interface{}) {
+	*p = append(*p, o.(*PerformerRelation))
+}
+
+func (p *PerformerRelations) Remove(id string) {
+	for i, v := range *p {
+		if (*v).ID() == id {
+			(*p)[i] = (*p)[len(*p)-1]
+			*p = (*p)[:len(*p)-1]
+			break
+		}
+	}
+}
+
+// ToInputs returns the relationships from the perspective of the provided
+// performer.
+func (p PerformerRelations) ToInputs(performerID uuid.UUID) []*PerformerRelationshipInput {
+	var ret []*PerformerRelationshipInput
+	for _, r := range p {
+		ret = append(ret, r.ToInput(performerID))
+	}
+	return ret
+}
+
+// NewPerformerRelation returns the stored form of a relationship given from
+// the perspective of performerID.
+func NewPerformerRelation(performerID uuid.UUID, input PerformerRelationshipInput) *PerformerRelation {
+	otherID, _ := uuid.FromString(input.PerformerID)
+
+	ret := &PerformerRelation{
+		PerformerID:        performerID,
+		RelatedPerformerID: otherID,
+		Type:               input.Type.String(),
+	}
+
+	switch input.Type {
+	case PerformerRelationshipTypeGroupMember:
+	case PerformerRelationshipTypeMemberOf:
+		ret.PerformerID, ret.RelatedPerformerID = otherID, performerID
+		ret.Type = PerformerRelationshipTypeGroupMember.String()
+	default:
+		if otherID.String() < performerID.String() {
+			ret.PerformerID, ret.RelatedPerformerID = otherID, performerID
+		}
+	}
+
+	return ret
+}
+
+func CreatePerformerRelations(performerID uuid.UUID, inputs []*PerformerRelationshipInput) PerformerRelations {
+	var ret PerformerRelations
+	for _, input := range inputs {
+		ret = append(ret, NewPerformerRelation(performerID, *input))
+	}
+	return ret
+}
+
+// StoredTypes returns the stored relationship types matching the
+// relationship type from the perspective of a performer. outgoing types are
+// stored with the performer first, incoming types with the performer second.
+func (e PerformerRelationshipType) StoredTypes() (outgoing []string, incoming []string) {
+	switch e {
+	case PerformerRelationshipTypeGroupMember:
+		return []string{e.String()}, nil
+	case PerformerRelationshipTypeMemberOf:
+		return nil, []string{PerformerRelationshipTypeGroupMember.String()}
+	default:
+		return []string{e.String()}, []string{e.String()}
+	}
+}
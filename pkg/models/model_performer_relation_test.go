@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPerformerRelation(t *testing.T) {
+	low := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+	high := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000002")
+
+	tests := []struct {
+		name      string
+		performer uuid.UUID
+		input     PerformerRelationshipInput
+		want      PerformerRelation
+	}{
+		{
+			"symmetric from lower",
+			low,
+			PerformerRelationshipInput{PerformerID: high.String(), Type: PerformerRelationshipTypeSibling},
+			PerformerRelation{PerformerID: low, RelatedPerformerID: high, Type: "SIBLING"},
+		},
+		{
+			"symmetric from higher",
+			high,
+			PerformerRelationshipInput{PerformerID: low.String(), Type: PerformerRelationshipTypeSamePerson},
+			PerformerRelation{PerformerID: low, RelatedPerformerID: high, Type: "SAME_PERSON"},
+		},
+		{
+			"group member",
+			high,
+			PerformerRelationshipInput{PerformerID: low.String(), Type: PerformerRelationshipTypeGroupMember},
+			PerformerRelation{PerformerID: high, RelatedPerformerID: low, Type: "GROUP_MEMBER"},
+		},
+		{
+			"member of",
+			low,
+			PerformerRelationshipInput{PerformerID: high.String(), Type: PerformerRelationshipTypeMemberOf},
+			PerformerRelation{PerformerID: high, RelatedPerformerID: low, Type: "GROUP_MEMBER"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPerformerRelation(tt.performer, tt.input)
+			assert.Equal(t, tt.want, *got)
+
+			// converting back gives the original perspective
+			assert.Equal(t, tt.input, *got.ToInput(tt.performer))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Revision is the state of an entity after a change to it. Data holds the
+// entity details, with list fields holding the full contents in the added
+// fields.
+type Revision struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	TargetType string          `db:"target_type" json:"target_type"`
+	TargetID   uuid.UUID       `db:"target_id" json:"target_id"`
+	Operation  string          `db:"operation" json:"operation"`
+	Data       types.JSONText  `db:"data" json:"data"`
+	UserID     uuid.NullUUID   `db:"user_id" json:"user_id"`
+	EditID     uuid.NullUUID   `db:"edit_id" json:"edit_id"`
+	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func (p Revision) GetID() uuid.UUID {
+	return p.ID
+}
+
+func NewRevision(UUID uuid.UUID, targetType TargetTypeEnum, operation OperationEnum, targetID uuid.UUID) *Revision {
+	return &Revision{
+		ID:         UUID,
+		TargetType: targetType.String(),
+		TargetID:   targetID,
+		Operation:  operation.String(),
+		CreatedAt:  SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+func (p *Revision) SetData(data EditDetails) error {
+	buffer, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	p.Data = buffer
+	return nil
+}
+
+// GetDetails returns the entity details recorded in the revision.
+func (p *Revision) GetDetails() (EditDetails, error) {
+	var ret EditDetails
+	switch TargetTypeEnum(p.TargetType) {
+	case TargetTypeEnumTag:
+		ret = &TagEdit{}
+	case TargetTypeEnumPerformer:
+		ret = &PerformerEdit{}
+	case TargetTypeEnumStudio:
+		ret = &StudioEdit{}
+	case TargetTypeEnumScene:
+		ret = &SceneEdit{}
+	default:
+		return nil, errors.New("unsupported target type: " + p.TargetType)
+	}
+
+	if err := json.Unmarshal(p.Data, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type Revisions []*Revision
+
+func (p Revisions) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Revisions) Add(o interface{}) {
+	*p = append(*p, o.(*Revision))
+}
@@ -35,6 +35,19 @@ func (p *Scenes) Add(o interface{}) {
 	*p = append(*p, o.(*Scene))
 }
 
+// SceneDuplicatePair is a pair of scenes with matching fingerprints.
+type SceneDuplicatePair struct {
+	SceneID     uuid.UUID `db:"scene_id"`
+	DuplicateID uuid.UUID `db:"duplicate_id"`
+}
+
+// SceneDuplicateCluster is a group of scenes that are likely duplicates of
+// each other. SuggestedTarget is the scene the others should be merged into.
+type SceneDuplicateCluster struct {
+	Scenes          []*Scene
+	SuggestedTarget *Scene
+}
+
 type SceneURL struct {
 	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
 	URL     string    `db:"url" json:"url"`
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type SceneMarker struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	SceneID      uuid.UUID       `db:"scene_id" json:"scene_id"`
+	TagID        uuid.UUID       `db:"tag_id" json:"tag_id"`
+	Title        sql.NullString  `db:"title" json:"title"`
+	StartSeconds int             `db:"start_seconds" json:"start_seconds"`
+	EndSeconds   sql.NullInt64   `db:"end_seconds" json:"end_seconds"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+func (m SceneMarker) GetID() uuid.UUID {
+	return m.ID
+}
+
+type SceneMarkers []*SceneMarker
+
+func (m SceneMarkers) Each(fn func(interface{})) {
+	for _, v := range m {
+		fn(*v)
+	}
+}
+
+func (m *SceneMarkers) Add(o interface{}) {
+	*m = append(*m, o.(*SceneMarker))
+}
+
+type SceneMarkerPerformer struct {
+	MarkerID    uuid.UUID `db:"marker_id" json:"marker_id"`
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+}
+
+type SceneMarkerPerformers []*SceneMarkerPerformer
+
+func (p SceneMarkerPerformers) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p SceneMarkerPerformers) EachPtr(fn func(interface{})) {
+	for _, v := range p {
+		fn(v)
+	}
+}
+
+func (p *SceneMarkerPerformers) Add(o interface{}) {
+	*p = append(*p, o.(*SceneMarkerPerformer))
+}
+
+// ToInput returns the marker as an input value, with the ids of the
+// performers in the provided joins that belong to the marker.
+func (m SceneMarker) ToInput(performers SceneMarkerPerformers) *SceneMarkerInput {
+	ret := &SceneMarkerInput{
+		TagID: m.TagID.String(),
+		Start: m.StartSeconds,
+	}
+
+	if m.Title.Valid {
+		title := m.Title.String
+		ret.Title = &title
+	}
+	if m.EndSeconds.Valid {
+		end := int(m.EndSeconds.Int64)
+		ret.End = &end
+	}
+
+	for _, p := range performers {
+		if p.MarkerID == m.ID {
+			ret.PerformerIds = append(ret.PerformerIds, p.PerformerID.String())
+		}
+	}
+
+	return ret
+}
+
+// Key returns a string identifying the marker by value. Markers in edits are
+// matched to existing markers by their key.
+func (i SceneMarkerInput) Key() string {
+	title := ""
+	if i.Title != nil {
+		title = *i.Title
+	}
+
+	end := ""
+	if i.End != nil {
+		end = fmt.Sprint(*i.End)
+	}
+
+	var performers []string
+	seen := make(map[string]bool)
+	for _, p := range i.PerformerIds {
+		if !seen[p] {
+			seen[p] = true
+			performers = append(performers, p)
+		}
+	}
+	sort.Strings(performers)
+
+	return fmt.Sprintf("%d|%s|%s|%s|%s", i.Start, end, i.TagID, strings.Join(performers, ","), title)
+}
+
+// Validate returns an error if the marker times are invalid.
+func (i SceneMarkerInput) Validate() error {
+	if i.Start < 0 {
+		return fmt.Errorf("marker start must not be negative: %d", i.Start)
+	}
+	if i.End != nil && *i.End <= i.Start {
+		return fmt.Errorf("marker end must be after start: %d-%d", i.Start, *i.End)
+	}
+
+	return nil
+}
+
+// CreateSceneMarker returns a new marker for the scene from the input, along
+// with its performer joins.
+func CreateSceneMarker(sceneID uuid.UUID, input SceneMarkerInput) (*SceneMarker, SceneMarkerPerformers, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := SQLiteTimestamp{Timestamp: time.Now()}
+	marker := &SceneMarker{
+		ID:           id,
+		SceneID:      sceneID,
+		TagID:        uuid.FromStringOrNil(input.TagID),
+		StartSeconds: input.Start,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if input.Title != nil {
+		marker.Title = sql.NullString{String: *input.Title, Valid: true}
+	}
+	if input.End != nil {
+		marker.EndSeconds = sql.NullInt64{Int64: int64(*input.End), Valid: true}
+	}
+
+	var performers SceneMarkerPerformers
+	seen := make(map[uuid.UUID]bool)
+	for _, p := range input.PerformerIds {
+		performerID := uuid.FromStringOrNil(p)
+		if seen[performerID] {
+			continue
+		}
+		seen[performerID] = true
+
+		performers = append(performers, &SceneMarkerPerformer{
+			MarkerID:    id,
+			PerformerID: performerID,
+		})
+	}
+
+	return marker, performers, nil
+}
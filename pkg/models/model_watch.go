@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type Watch struct {
+	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
+	TargetType string          `db:"target_type" json:"target_type"`
+	TargetID   uuid.UUID       `db:"target_id" json:"target_id"`
+	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewWatch(userID uuid.UUID, targetType TargetTypeEnum, targetID uuid.UUID) *Watch {
+	return &Watch{
+		UserID:     userID,
+		TargetType: targetType.String(),
+		TargetID:   targetID,
+		CreatedAt:  SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+type Watches []*Watch
+
+func (p Watches) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Watches) Add(o interface{}) {
+	*p = append(*p, o.(*Watch))
+}
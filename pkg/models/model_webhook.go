@@ -0,0 +1,207 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+type Webhook struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	URL       string          `db:"url" json:"url"`
+	Secret    string          `db:"secret" json:"secret"`
+	Active    bool            `db:"active" json:"active"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+func (p Webhook) GetID() uuid.UUID {
+	return p.ID
+}
+
+func (p *Webhook) CopyFromCreateInput(input WebhookCreateInput) {
+	p.URL = input.URL
+	p.Secret = input.Secret
+	p.Active = input.Active == nil || *input.Active
+}
+
+func (p *Webhook) CopyFromUpdateInput(input WebhookUpdateInput) {
+	if input.URL != nil {
+		p.URL = *input.URL
+	}
+	if input.Secret != nil {
+		p.Secret = *input.Secret
+	}
+	if input.Active != nil {
+		p.Active = *input.Active
+	}
+}
+
+type Webhooks []*Webhook
+
+func (p Webhooks) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Webhooks) Add(o interface{}) {
+	*p = append(*p, o.(*Webhook))
+}
+
+type WebhookTargetType struct {
+	WebhookID  uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	TargetType string    `db:"target_type" json:"target_type"`
+}
+
+type WebhookTargetTypes []*WebhookTargetType
+
+func (p WebhookTargetTypes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *WebhookTargetTypes) Add(o interface{}) {
+	*p = append(*p, o.(*WebhookTargetType))
+}
+
+func (p WebhookTargetTypes) ToTargetTypes() []TargetTypeEnum {
+	ret := []TargetTypeEnum{}
+	for _, v := range p {
+		ret = append(ret, TargetTypeEnum(v.TargetType))
+	}
+
+	return ret
+}
+
+func CreateWebhookTargetTypes(webhookID uuid.UUID, targetTypes []TargetTypeEnum) WebhookTargetTypes {
+	var ret WebhookTargetTypes
+
+	for _, targetType := range targetTypes {
+		ret = append(ret, &WebhookTargetType{
+			WebhookID:  webhookID,
+			TargetType: targetType.String(),
+		})
+	}
+
+	return ret
+}
+
+type WebhookOperation struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	Operation string    `db:"operation" json:"operation"`
+}
+
+type WebhookOperations []*WebhookOperation
+
+func (p WebhookOperations) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *WebhookOperations) Add(o interface{}) {
+	*p = append(*p, o.(*WebhookOperation))
+}
+
+func (p WebhookOperations) ToOperations() []OperationEnum {
+	ret := []OperationEnum{}
+	for _, v := range p {
+		ret = append(ret, OperationEnum(v.Operation))
+	}
+
+	return ret
+}
+
+func CreateWebhookOperations(webhookID uuid.UUID, operations []OperationEnum) WebhookOperations {
+	var ret WebhookOperations
+
+	for _, operation := range operations {
+		ret = append(ret, &WebhookOperation{
+			WebhookID: webhookID,
+			Operation: operation.String(),
+		})
+	}
+
+	return ret
+}
+
+type WebhookDelivery struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	WebhookID     uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	TargetType    string          `db:"target_type" json:"target_type"`
+	Operation     string          `db:"operation" json:"operation"`
+	TargetID      uuid.UUID       `db:"target_id" json:"target_id"`
+	EditID        uuid.NullUUID   `db:"edit_id" json:"edit_id"`
+	Payload       types.JSONText  `db:"payload" json:"payload"`
+	Status        string          `db:"status" json:"status"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	ResponseCode  sql.NullInt64   `db:"response_code" json:"response_code"`
+	Error         sql.NullString  `db:"error" json:"error"`
+	NextAttemptAt SQLiteTimestamp `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt sql.NullTime    `db:"last_attempt_at" json:"last_attempt_at"`
+	CreatedAt     SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func (p WebhookDelivery) GetID() uuid.UUID {
+	return p.ID
+}
+
+func NewWebhookDelivery(UUID uuid.UUID, webhook *Webhook, targetType TargetTypeEnum, operation OperationEnum, targetID uuid.UUID, payload []byte) *WebhookDelivery {
+	currentTime := time.Now()
+
+	return &WebhookDelivery{
+		ID:            UUID,
+		WebhookID:     webhook.ID,
+		TargetType:    targetType.String(),
+		Operation:     operation.String(),
+		TargetID:      targetID,
+		Payload:       payload,
+		Status:        WebhookDeliveryStatusEnumPending.String(),
+		NextAttemptAt: SQLiteTimestamp{Timestamp: currentTime},
+		CreatedAt:     SQLiteTimestamp{Timestamp: currentTime},
+	}
+}
+
+// Succeed marks the delivery as successfully delivered.
+func (p *WebhookDelivery) Succeed(responseCode int) {
+	p.Status = WebhookDeliveryStatusEnumSucceeded.String()
+	p.ResponseCode = sql.NullInt64{Int64: int64(responseCode), Valid: true}
+	p.Error = sql.NullString{}
+}
+
+// Retry records a failed attempt and schedules the next one. If there are
+// no attempts remaining, the delivery is marked as failed instead.
+func (p *WebhookDelivery) Retry(responseCode int, err string, maxAttempts int, nextAttempt time.Time) {
+	p.ResponseCode = sql.NullInt64{Int64: int64(responseCode), Valid: responseCode != 0}
+	p.Error = sql.NullString{String: err, Valid: true}
+
+	if p.Attempts >= maxAttempts {
+		p.Status = WebhookDeliveryStatusEnumFailed.String()
+		return
+	}
+
+	p.NextAttemptAt = SQLiteTimestamp{Timestamp: nextAttempt}
+}
+
+// Reset requeues the delivery for immediate redelivery.
+func (p *WebhookDelivery) Reset() {
+	p.Status = WebhookDeliveryStatusEnumPending.String()
+	p.Attempts = 0
+	p.NextAttemptAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+type WebhookDeliveries []*WebhookDelivery
+
+func (p WebhookDeliveries) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *WebhookDeliveries) Add(o interface{}) {
+	*p = append(*p, o.(*WebhookDelivery))
+}
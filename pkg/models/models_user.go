@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"fmt"
 
 	"github.com/gofrs/uuid"
@@ -19,6 +20,11 @@ type User struct {
 	LastAPICall  SQLiteTimestamp `db:"last_api_call" json:"last_api_call"`
 	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	// TOTPSecret is set when the user starts enrolment, and TOTPEnabled once
+	// it is verified
+	TOTPSecret   sql.NullString `db:"totp_secret" json:"totp_secret"`
+	TOTPEnabled  bool           `db:"totp_enabled" json:"totp_enabled"`
+	TOTPLastStep int64          `db:"totp_last_step" json:"totp_last_step"`
 }
 
 func (p User) GetID() uuid.UUID {
@@ -29,6 +35,7 @@ func (p *User) RemoveSensitiveFields() {
 	p.PasswordHash = ""
 	p.Email = ""
 	p.APIKey = ""
+	p.TOTPSecret = sql.NullString{}
 	p.APICalls = -1
 	p.InviteTokens = -1
 }
@@ -71,6 +78,44 @@ func (p UserRoles) ToRoles() []RoleEnum {
 	return ret
 }
 
+// UserIdentity links a user to the subject of an external identity
+// provider.
+type UserIdentity struct {
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	Issuer    string          `db:"issuer" json:"issuer"`
+	Subject   string          `db:"subject" json:"subject"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+type UserIdentities []*UserIdentity
+
+func (p UserIdentities) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserIdentities) Add(o interface{}) {
+	*p = append(*p, o.(*UserIdentity))
+}
+
+type UserRecoveryCode struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	CodeHash string    `db:"code_hash" json:"code_hash"`
+}
+
+type UserRecoveryCodes []*UserRecoveryCode
+
+func (p UserRecoveryCodes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserRecoveryCodes) Add(o interface{}) {
+	*p = append(*p, o.(*UserRecoveryCode))
+}
+
 func CreateUserRoles(userID uuid.UUID, roles []RoleEnum) UserRoles {
 	var ret UserRoles
 
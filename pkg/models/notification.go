@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+type NotificationRepo interface {
+	Create(newNotification Notification) (*Notification, error)
+	// FindByUser returns the notifications of the user, newest first.
+	FindByUser(userID uuid.UUID, unreadOnly bool, limit int) (Notifications, error)
+	CountUnread(userID uuid.UUID) (int, error)
+	// MarkRead marks the notifications of the user with the provided ids as
+	// read. All notifications of the user are marked if ids is nil.
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) error
+	// FindUnemailed returns the unread notifications not yet included in an
+	// email digest, oldest first.
+	FindUnemailed() (Notifications, error)
+	MarkEmailed(ids []uuid.UUID) error
+}
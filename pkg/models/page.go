@@ -0,0 +1,21 @@
+package models
+
+// Page is the result of a cursor paginated query.
+type Page struct {
+	// Cursors contains the cursor of each returned result, in order.
+	Cursors     []string
+	HasNextPage bool
+	// Count is the total number of results matching the query.
+	Count int
+}
+
+// PageInfo returns the page info for the page.
+func (p Page) PageInfo() *PageInfo {
+	ret := &PageInfo{
+		HasNextPage: p.HasNextPage,
+	}
+	if len(p.Cursors) > 0 {
+		ret.EndCursor = &p.Cursors[len(p.Cursors)-1]
+	}
+	return ret
+}
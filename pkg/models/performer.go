@@ -19,6 +19,7 @@ type PerformerRepo interface {
 	FindByIds(ids []uuid.UUID) ([]*Performer, []error)
 	Count() (int, error)
 	Query(performerFilter *PerformerFilterType, findFilter *QuerySpec) ([]*Performer, int)
+	QueryPage(performerFilter *PerformerFilterType, findFilter *CursorQuerySpec) ([]*Performer, *Page, error)
 	GetAliases(id uuid.UUID) (PerformerAliases, error)
 	GetImages(id uuid.UUID) (PerformersImages, error)
 	GetAllAliases(ids []uuid.UUID) ([][]string, []error)
@@ -27,6 +28,10 @@ type PerformerRepo interface {
 	GetTattoos(id uuid.UUID) (PerformerBodyMods, error)
 	GetAllTattoos(ids []uuid.UUID) ([][]*BodyModification, []error)
 	GetPiercings(id uuid.UUID) (PerformerBodyMods, error)
+	// GetRelations returns the relationships the performer is part of, in
+	// either direction.
+	GetRelations(id uuid.UUID) (PerformerRelations, error)
+	GetAllRelations(ids []uuid.UUID) ([][]*PerformerRelation, []error)
 	GetAllPiercings(ids []uuid.UUID) ([][]*BodyModification, []error)
 	SearchPerformers(term string, limit int) (Performers, error)
 	ApplyEdit(edit Edit, operation OperationEnum, performer *Performer) (*Performer, error)
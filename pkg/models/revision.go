@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type RevisionRepo interface {
+	Create(newRevision Revision) (*Revision, error)
+	Find(id uuid.UUID) (*Revision, error)
+	// FindByTarget returns the revisions of the target entity, newest first.
+	FindByTarget(targetType TargetTypeEnum, targetID uuid.UUID) (Revisions, error)
+	// FindAt returns the latest revision of the target entity created at or
+	// before the provided time.
+	FindAt(targetType TargetTypeEnum, targetID uuid.UUID, at time.Time) (*Revision, error)
+}
@@ -13,12 +13,18 @@ type SceneRepo interface {
 	UpdateFingerprints(sceneID uuid.UUID, updatedJoins SceneFingerprints) error
 	DestroyFingerprints(sceneID uuid.UUID, toDelete SceneFingerprints) error
 	Find(id uuid.UUID) (*Scene, error)
+	FindByIds(ids []uuid.UUID) ([]*Scene, []error)
 	FindByFingerprint(algorithm FingerprintAlgorithm, hash string) ([]*Scene, error)
 	FindByFingerprints(fingerprints []string) ([]*Scene, error)
 	FindByFullFingerprints(fingerprints []*FingerprintQueryInput) ([]*Scene, error)
 	FindByTitle(name string) ([]*Scene, error)
+
+	// FindDuplicatePairs returns the pairs of non-deleted scenes that share an
+	// MD5 or OSHASH hash, or have PHASHes within distance of each other.
+	FindDuplicatePairs(distance int, studioID *uuid.UUID) ([]*SceneDuplicatePair, error)
 	Count() (int, error)
 	Query(sceneFilter *SceneFilterType, findFilter *QuerySpec) ([]*Scene, int)
+	QueryPage(sceneFilter *SceneFilterType, findFilter *CursorQuerySpec) ([]*Scene, *Page, error)
 	GetFingerprints(id uuid.UUID) (SceneFingerprints, error)
 
 	// GetAllFingerprints returns fingerprints for each of the scene ids provided.
@@ -26,6 +32,11 @@ type SceneRepo interface {
 	GetAllFingerprints(currentUserID uuid.UUID, ids []uuid.UUID) ([][]*Fingerprint, []error)
 	GetPerformers(id uuid.UUID) (PerformersScenes, error)
 	GetAllAppearances(ids []uuid.UUID) ([]PerformersScenes, []error)
+	GetMarkers(id uuid.UUID) (SceneMarkers, error)
+	GetAllMarkers(ids []uuid.UUID) ([][]*SceneMarker, []error)
+	GetMarkerPerformers(id uuid.UUID) (SceneMarkerPerformers, error)
+	GetAllMarkerPerformerIDs(markerIDs []uuid.UUID) ([][]uuid.UUID, []error)
+	DeleteMarkers(id uuid.UUID) error
 	GetURLs(id uuid.UUID) ([]*URL, error)
 	GetAllURLs(ids []uuid.UUID) ([][]*URL, []error)
 	SearchScenes(term string, limit int) ([]*Scene, error)
@@ -13,6 +13,7 @@ type StudioRepo interface {
 	FindByParentID(id uuid.UUID) (Studios, error)
 	Count() (int, error)
 	Query(studioFilter *StudioFilterType, findFilter *QuerySpec) (Studios, int)
+	QueryPage(studioFilter *StudioFilterType, findFilter *CursorQuerySpec) (Studios, *Page, error)
 	GetURLs(id uuid.UUID) ([]*URL, error)
 	GetAllURLs(ids []uuid.UUID) ([][]*URL, []error)
 	CountByPerformer(performerID uuid.UUID) ([]*PerformerStudio, error)
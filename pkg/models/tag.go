@@ -17,6 +17,7 @@ type TagRepo interface {
 	FindByName(name string) (*Tag, error)
 	Count() (int, error)
 	Query(tagFilter *TagFilterType, findFilter *QuerySpec) ([]*Tag, int, error)
+	QueryPage(tagFilter *TagFilterType, findFilter *CursorQuerySpec) ([]*Tag, *Page, error)
 	GetAliases(id uuid.UUID) ([]string, error)
 	ApplyEdit(edit Edit, operation OperationEnum, tag *Tag) (*Tag, error)
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 )
 
@@ -13,12 +15,26 @@ type UserRepo interface {
 	Destroy(id uuid.UUID) error
 	CreateRoles(newJoins UserRoles) error
 	UpdateRoles(studioID uuid.UUID, updatedJoins UserRoles) error
+	CreateIdentities(newJoins UserIdentities) error
+	GetIdentities(id uuid.UUID) (UserIdentities, error)
+	GetRecoveryCodes(id uuid.UUID) (UserRecoveryCodes, error)
+	UpdateRecoveryCodes(id uuid.UUID, updatedJoins UserRecoveryCodes) error
 
 	Count() (int, error)
 	Query(userFilter *UserFilterType, findFilter *QuerySpec) (Users, int)
 	GetRoles(id uuid.UUID) (UserRoles, error)
+	// AddAPICalls adds calls to the user's API call count for the period
+	// starting at bucket, and updates the time of their last API call.
+	AddAPICalls(id uuid.UUID, bucket time.Time, calls int, lastCall time.Time) error
+	// UpdateAPICallTotals sets the API call count of each user to the
+	// number of calls made since the provided time.
+	UpdateAPICallTotals(since time.Time) error
 	CountVotesByType(id uuid.UUID) (*UserVoteCount, error)
 	CountEditsByStatus(id uuid.UUID) (*UserEditCount, error)
+	// CountVoteOutcomes returns the number of accept and reject votes cast
+	// by the user on closed edits that matched, and that opposed, the final
+	// outcome of the edit.
+	CountVoteOutcomes(id uuid.UUID) (matching int, opposing int, err error)
 }
 
 // UserFinder is an interface to find and update User objects.
@@ -26,4 +42,7 @@ type UserFinder interface {
 	Find(id uuid.UUID) (*User, error)
 	FindByName(name string) (*User, error)
 	FindByEmail(email string) (*User, error)
+	// FindByIdentity returns the user linked to the subject of the external
+	// identity provider with the provided issuer.
+	FindByIdentity(issuer string, subject string) (*User, error)
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type WatchRepo interface {
+	// Create adds the entity to the watchlist of the user. Watching an
+	// entity twice has no effect.
+	Create(newWatch Watch) error
+	Destroy(userID uuid.UUID, targetType TargetTypeEnum, targetID uuid.UUID) error
+	// FindByUser returns the watchlist of the user, newest first.
+	FindByUser(userID uuid.UUID) (Watches, error)
+	// FindWatchers returns the ids of the users watching the entity.
+	FindWatchers(targetType TargetTypeEnum, targetID uuid.UUID) ([]uuid.UUID, error)
+	// FindWatchedScenes returns the scenes created after since that feature
+	// a performer, studio or tag watched by the user, newest first.
+	FindWatchedScenes(userID uuid.UUID, since *time.Time, limit int) (Scenes, error)
+	// FindWatchedEdits returns the pending edits created after since that
+	// target an entity watched by the user, newest first.
+	FindWatchedEdits(userID uuid.UUID, since *time.Time, limit int) (Edits, error)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type WebhookRepo interface {
+	Create(newWebhook Webhook) (*Webhook, error)
+	Update(updatedWebhook Webhook) (*Webhook, error)
+	Destroy(id uuid.UUID) error
+	Find(id uuid.UUID) (*Webhook, error)
+	FindAll() (Webhooks, error)
+	// FindActiveByEvent returns the active webhooks whose filters match the
+	// provided target type and operation.
+	FindActiveByEvent(targetType TargetTypeEnum, operation OperationEnum) (Webhooks, error)
+
+	CreateTargetTypes(newJoins WebhookTargetTypes) error
+	UpdateTargetTypes(webhookID uuid.UUID, updatedJoins WebhookTargetTypes) error
+	GetTargetTypes(id uuid.UUID) (WebhookTargetTypes, error)
+	CreateOperations(newJoins WebhookOperations) error
+	UpdateOperations(webhookID uuid.UUID, updatedJoins WebhookOperations) error
+	GetOperations(id uuid.UUID) (WebhookOperations, error)
+
+	CreateDelivery(newDelivery WebhookDelivery) (*WebhookDelivery, error)
+	UpdateDelivery(updatedDelivery WebhookDelivery) (*WebhookDelivery, error)
+	FindDelivery(id uuid.UUID) (*WebhookDelivery, error)
+	// ClaimDueDeliveries returns pending deliveries whose next attempt is
+	// due, oldest first, and moves their next attempt to claimUntil so that
+	// they are not returned again until then. Deliveries claimed by another
+	// transaction are skipped.
+	ClaimDueDeliveries(limit int, claimUntil time.Time) (WebhookDeliveries, error)
+	QueryDeliveries(filter *WebhookDeliveryFilterType, findFilter *QuerySpec) (WebhookDeliveries, int, error)
+}
@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/email"
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var typeDescriptions = map[models.NotificationTypeEnum]string{
+	models.NotificationTypeEnumEditComment:       "New comment on edit",
+	models.NotificationTypeEnumEditDownVote:      "Edit received a no vote",
+	models.NotificationTypeEnumEditApplied:       "Edit was applied",
+	models.NotificationTypeEnumEditRejected:      "Edit was rejected",
+	models.NotificationTypeEnumEditFailed:        "Edit failed to apply",
+	models.NotificationTypeEnumEditAmended:       "Edit you voted on was amended",
+	models.NotificationTypeEnumWatchedEntityEdit: "New edit on watched entity",
+}
+
+// SendDigests emails each user a digest of their unread notifications that
+// were not included in a previous digest. Notifications are only marked as
+// emailed once the digest has been sent, so that failed digests are retried.
+// Users without an email address are skipped.
+func SendDigests(fac models.Repo, em *email.Manager) error {
+	notifications, err := fac.Notification().FindUnemailed()
+	if err != nil {
+		return err
+	}
+
+	var userIDs []uuid.UUID
+	byUser := make(map[uuid.UUID]models.Notifications)
+	for _, n := range notifications {
+		if _, found := byUser[n.UserID]; !found {
+			userIDs = append(userIDs, n.UserID)
+		}
+		byUser[n.UserID] = append(byUser[n.UserID], n)
+	}
+
+	for _, userID := range userIDs {
+		u, err := fac.User().Find(userID)
+		if err != nil {
+			return err
+		}
+
+		// notifications of users without an email address are marked as
+		// emailed without sending, so that they are not fetched again
+		userNotifications := byUser[userID]
+		if u != nil && user.HasEmail(u) {
+			subject := fmt.Sprintf("Subject: %d new stash-box notifications", len(userNotifications))
+			if err := em.Send(u.Email, subject, digestBody(config.GetHostURL(), userNotifications)); err != nil {
+				logger.Errorf("Failed to send notification digest to user %s: %s", userID.String(), err.Error())
+				continue
+			}
+		}
+
+		var ids []uuid.UUID
+		for _, n := range userNotifications {
+			ids = append(ids, n.ID)
+		}
+		if err := fac.WithTxn(func() error {
+			return fac.Notification().MarkEmailed(ids)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func digestBody(hostURL string, notifications models.Notifications) string {
+	var b strings.Builder
+	b.WriteString("You have new notifications:\r\n\r\n")
+	for _, n := range notifications {
+		var notificationType models.NotificationTypeEnum
+		utils.ResolveEnumString(n.Type, &notificationType)
+
+		description, found := typeDescriptions[notificationType]
+		if !found {
+			description = n.Type
+		}
+
+		fmt.Fprintf(&b, "- %s: %s/edits/%s\r\n", description, hostURL, n.EditID.String())
+	}
+
+	return b.String()
+}
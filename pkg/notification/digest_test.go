@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestDigestBody(t *testing.T) {
+	commentEditID := uuid.Must(uuid.NewV4())
+	appliedEditID := uuid.Must(uuid.NewV4())
+
+	notifications := models.Notifications{
+		models.NewNotification(uuid.Must(uuid.NewV4()), uuid.Nil, models.NotificationTypeEnumEditComment, commentEditID),
+		models.NewNotification(uuid.Must(uuid.NewV4()), uuid.Nil, models.NotificationTypeEnumEditApplied, appliedEditID),
+	}
+
+	body := digestBody("https://stashdb.org", notifications)
+
+	lines := []string{
+		"- New comment on edit: https://stashdb.org/edits/" + commentEditID.String(),
+		"- Edit was applied: https://stashdb.org/edits/" + appliedEditID.String(),
+	}
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\r\n") {
+			t.Errorf("digest body missing line %q:\n%s", line, body)
+		}
+	}
+
+	if strings.Index(body, lines[0]) > strings.Index(body, lines[1]) {
+		t.Errorf("digest body not in notification order:\n%s", body)
+	}
+}
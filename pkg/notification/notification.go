@@ -0,0 +1,169 @@
+// Package notification stores per-user notifications of events on edits,
+// and sends digests of unread notifications by email.
+package notification
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+// OnEditCreated notifies the users watching the target or merge sources of
+// the new edit. It must be called once the target joins of the edit have
+// been created.
+func OnEditCreated(fac models.Repo, edit *models.Edit) error {
+	var targetType models.TargetTypeEnum
+	utils.ResolveEnumString(edit.TargetType, &targetType)
+
+	targetIDs, err := editTargetIDs(fac, edit, targetType)
+	if err != nil {
+		return err
+	}
+
+	var recipients []uuid.UUID
+	for _, id := range targetIDs {
+		watchers, err := fac.Watch().FindWatchers(targetType, id)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, watchers...)
+	}
+
+	return notify(fac, models.NotificationTypeEnumWatchedEntityEdit, edit, recipients, edit.UserID)
+}
+
+// OnEditVote notifies the submitter of the edit of reject votes.
+func OnEditVote(fac models.Repo, edit *models.Edit, vote *models.EditVote) error {
+	if vote.Vote != models.VoteTypeEnumReject.String() {
+		return nil
+	}
+
+	return notify(fac, models.NotificationTypeEnumEditDownVote, edit, []uuid.UUID{edit.UserID}, vote.UserID)
+}
+
+// OnEditComment notifies the submitter and voters of the edit of the
+// comment.
+func OnEditComment(fac models.Repo, edit *models.Edit, comment *models.EditComment) error {
+	recipients, err := participants(fac, edit)
+	if err != nil {
+		return err
+	}
+
+	return notify(fac, models.NotificationTypeEnumEditComment, edit, recipients, comment.UserID)
+}
+
+// OnEditStatusChanged notifies the submitter and voters of the edit once it
+// has been applied, rejected or has failed.
+func OnEditStatusChanged(fac models.Repo, edit *models.Edit) error {
+	var notificationType models.NotificationTypeEnum
+	switch edit.Status {
+	case models.VoteStatusEnumAccepted.String(), models.VoteStatusEnumImmediateAccepted.String():
+		notificationType = models.NotificationTypeEnumEditApplied
+	case models.VoteStatusEnumRejected.String(), models.VoteStatusEnumImmediateRejected.String():
+		notificationType = models.NotificationTypeEnumEditRejected
+	case models.VoteStatusEnumFailed.String():
+		notificationType = models.NotificationTypeEnumEditFailed
+	default:
+		return nil
+	}
+
+	recipients, err := participants(fac, edit)
+	if err != nil {
+		return err
+	}
+
+	return notify(fac, notificationType, edit, recipients, uuid.Nil)
+}
+
+// OnEditAmended notifies the voters of the edit that it has been amended. It
+// must be called before the votes of the edit are reset.
+func OnEditAmended(fac models.Repo, edit *models.Edit) error {
+	votes, err := fac.Edit().GetVotes(edit.ID)
+	if err != nil {
+		return err
+	}
+
+	var recipients []uuid.UUID
+	for _, vote := range votes {
+		recipients = append(recipients, vote.UserID)
+	}
+
+	return notify(fac, models.NotificationTypeEnumEditAmended, edit, recipients, edit.UserID)
+}
+
+// participants returns the submitter and voters of the edit.
+func participants(fac models.Repo, edit *models.Edit) ([]uuid.UUID, error) {
+	votes, err := fac.Edit().GetVotes(edit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []uuid.UUID{edit.UserID}
+	for _, vote := range votes {
+		ret = append(ret, vote.UserID)
+	}
+	return ret, nil
+}
+
+// notify creates a notification for each distinct recipient other than the
+// user causing the event.
+func notify(fac models.Repo, notificationType models.NotificationTypeEnum, edit *models.Edit, recipients []uuid.UUID, actorID uuid.UUID) error {
+	qb := fac.Notification()
+	seen := map[uuid.UUID]bool{actorID: true}
+	for _, userID := range recipients {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+
+		if _, err := qb.Create(*models.NewNotification(UUID, userID, notificationType, edit.ID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// editTargetIDs returns the target and merge sources of the edit. Create
+// edits have no target.
+func editTargetIDs(fac models.Repo, edit *models.Edit, targetType models.TargetTypeEnum) ([]uuid.UUID, error) {
+	if edit.Operation == models.OperationEnumCreate.String() {
+		return nil, nil
+	}
+
+	eqb := fac.Edit()
+	var targetID *uuid.UUID
+	var err error
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		targetID, err = eqb.FindTagID(edit.ID)
+	case models.TargetTypeEnumPerformer:
+		targetID, err = eqb.FindPerformerID(edit.ID)
+	case models.TargetTypeEnumStudio:
+		targetID, err = eqb.FindStudioID(edit.ID)
+	case models.TargetTypeEnumScene:
+		targetID, err = eqb.FindSceneID(edit.ID)
+	}
+	if err != nil || targetID == nil {
+		return nil, err
+	}
+
+	ret := []uuid.UUID{*targetID}
+	if data := edit.GetData(); data != nil {
+		for _, id := range data.MergeSources {
+			sourceID, err := uuid.FromString(id)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, sourceID)
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,328 @@
+// Package oidc implements the OpenID Connect authorization code flow used to
+// log in with an external identity provider.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	discoveryPath  = "/.well-known/openid-configuration"
+	requestTimeout = 10 * time.Second
+	// responses larger than this are rejected
+	maxResponseSize = 1 << 20
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrUnknownKey     = errors.New("id token signed with unknown key")
+)
+
+// Config holds the client settings registered with the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect provider discovered from its issuer URL.
+type Provider struct {
+	config   Config
+	metadata providerMetadata
+	client   *http.Client
+
+	keysMutex sync.Mutex
+	keys      map[string]*rsa.PublicKey
+}
+
+// Claims are the verified claims of an ID token.
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	raw           jwt.MapClaims
+}
+
+// String returns the value of a string claim, or an empty string if the
+// claim is not set or is not a string.
+func (c Claims) String(name string) string {
+	s, _ := c.raw[name].(string)
+	return s
+}
+
+// Discover fetches the provider configuration from the issuer.
+func Discover(ctx context.Context, config Config) (*Provider, error) {
+	p := &Provider{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+
+	discoveryURL := strings.TrimSuffix(config.Issuer, "/") + discoveryPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.doJSON(req, &p.metadata); err != nil {
+		return nil, fmt.Errorf("error fetching provider configuration: %w", err)
+	}
+
+	// the issuer must match exactly, to prevent token substitution
+	if p.metadata.Issuer != config.Issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match configured issuer %q", p.metadata.Issuer, config.Issuer)
+	}
+	if p.metadata.AuthorizationEndpoint == "" || p.metadata.TokenEndpoint == "" || p.metadata.JWKSURI == "" {
+		return nil, errors.New("provider configuration is missing required endpoints")
+	}
+
+	return p, nil
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.metadata.Issuer
+}
+
+// AuthCodeURL returns the URL of the provider's login page. The provider
+// redirects back to redirectURL with the state and an authorization code.
+func (p *Provider) AuthCodeURL(redirectURL string, state string, nonce string) string {
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.config.ClientID},
+		"redirect_uri":  {redirectURL},
+		"scope":         {strings.Join(p.config.Scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + sep + v.Encode()
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange exchanges an authorization code for an ID token, and returns its
+// verified claims. nonce must match the nonce passed to AuthCodeURL.
+func (p *Provider) Exchange(ctx context.Context, code string, redirectURL string, nonce string) (*Claims, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURL},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var token tokenResponse
+	if err := p.doJSON(req, &token); err != nil {
+		if token.Error != "" {
+			return nil, fmt.Errorf("token request failed: %s %s", token.Error, token.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id token")
+	}
+
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// Verify verifies the signature and claims of an ID token.
+func (p *Provider) Verify(ctx context.Context, idToken string, nonce string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unsupported signing method %s", t.Method.Alg())
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	})
+	if err != nil {
+		// return key lookup errors as is
+		var ve *jwt.ValidationError
+		if errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorUnverifiable != 0 && ve.Inner != nil {
+			return nil, ve.Inner
+		}
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(p.metadata.Issuer, true) {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidIDToken)
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidIDToken)
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: missing expiry", ErrInvalidIDToken)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	ret := &Claims{
+		raw: claims,
+	}
+	ret.Issuer = ret.String("iss")
+	ret.Subject = ret.String("sub")
+	ret.Email = ret.String("email")
+	ret.EmailVerified, _ = claims["email_verified"].(bool)
+
+	if ret.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return ret, nil
+}
+
+func (p *Provider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.keysMutex.Lock()
+	defer p.keysMutex.Unlock()
+
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+
+	// the provider may have rotated its keys
+	if err := p.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// findKey returns the key with the provided id. Tokens without a key id may
+// only be used if the provider has a single key.
+func (p *Provider) findKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+
+	return p.keys[kid]
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (p *Provider) fetchKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadata.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.doJSON(req, &keySet); err != nil {
+		return fmt.Errorf("error fetching provider keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range keySet.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		key, err := parseRSAKey(k)
+		if err != nil {
+			return err
+		}
+		keys[k.Kid] = key
+	}
+
+	p.keys = keys
+	return nil
+}
+
+func parseRSAKey(k jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus for key %s: %w", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent for key %s: %w", k.Kid, err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent for key %s", k.Kid)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// doJSON performs the request, decoding the JSON response body into output.
+// Error responses are decoded too, since token errors are returned as JSON.
+func (p *Provider) doJSON(req *http.Request, output interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	decodeErr := json.Unmarshal(body, output)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return decodeErr
+}
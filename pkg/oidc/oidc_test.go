@@ -0,0 +1,234 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testClientID     = "client"
+	testClientSecret = "secret"
+	testCode         = "code"
+	testNonce        = "nonce"
+	testKeyID        = "key1"
+)
+
+// mockProvider is a minimal OpenID Connect provider, issuing the ID token
+// returned by claims for the test authorization code.
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims func() jwt.MapClaims
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockProvider{
+		key: key,
+	}
+	m.claims = m.defaultClaims
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(providerMetadata{
+			Issuer:                m.server.URL,
+			AuthorizationEndpoint: m.server.URL + "/authorize",
+			TokenEndpoint:         m.server.URL + "/token",
+			JWKSURI:               m.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []jsonWebKey{{
+				Kty: "RSA",
+				Kid: testKeyID,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != testClientID || secret != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_client"})
+			return
+		}
+		if r.FormValue("code") != testCode || r.FormValue("grant_type") != "authorization_code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_grant"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(tokenResponse{IDToken: m.sign(t, m.claims())})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+func (m *mockProvider) defaultClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                m.server.URL,
+		"sub":                "subject",
+		"aud":                []string{testClientID},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              testNonce,
+		"email":              "user@example.com",
+		"email_verified":     true,
+		"preferred_username": "user",
+	}
+}
+
+func (m *mockProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	ret, err := token.SignedString(m.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func (m *mockProvider) discover(t *testing.T) *Provider {
+	p, err := Discover(context.Background(), Config{
+		Issuer:       m.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"openid", "email"},
+	})
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	return p
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.discover(t)
+
+	u, err := url.Parse(p.AuthCodeURL("http://localhost/callback", "state", testNonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := u.Query()
+	want := map[string]string{
+		"response_type": "code",
+		"client_id":     testClientID,
+		"redirect_uri":  "http://localhost/callback",
+		"scope":         "openid email",
+		"state":         "state",
+		"nonce":         testNonce,
+	}
+	for k, v := range want {
+		if got := q.Get(k); got != v {
+			t.Errorf("%s: got %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.discover(t)
+
+	claims, err := p.Exchange(context.Background(), testCode, "http://localhost/callback", testNonce)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Issuer != m.server.URL {
+		t.Errorf("Issuer: got %q, want %q", claims.Issuer, m.server.URL)
+	}
+	if claims.Subject != "subject" {
+		t.Errorf("Subject: got %q, want %q", claims.Subject, "subject")
+	}
+	if claims.Email != "user@example.com" || !claims.EmailVerified {
+		t.Errorf("Email: got %q (verified %v)", claims.Email, claims.EmailVerified)
+	}
+	if got := claims.String("preferred_username"); got != "user" {
+		t.Errorf("preferred_username: got %q, want %q", got, "user")
+	}
+
+	if _, err := p.Exchange(context.Background(), "wrong", "http://localhost/callback", testNonce); err == nil {
+		t.Error("expected error for invalid code")
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.discover(t)
+
+	tests := []struct {
+		name   string
+		modify func(c jwt.MapClaims)
+		nonce  string
+	}{
+		{"issuer", func(c jwt.MapClaims) { c["iss"] = "https://example.com" }, testNonce},
+		{"audience", func(c jwt.MapClaims) { c["aud"] = "other" }, testNonce},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, testNonce},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }, testNonce},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }, testNonce},
+		{"nonce", func(c jwt.MapClaims) {}, "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := m.defaultClaims()
+			tt.modify(claims)
+
+			if _, err := p.Verify(context.Background(), m.sign(t, claims), tt.nonce); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, m.defaultClaims())
+		token.Header["kid"] = "key2"
+		signed, err := token.SignedString(otherKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := p.Verify(context.Background(), signed, testNonce); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("got %v, want %v", err, ErrUnknownKey)
+		}
+	})
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	m := newMockProvider(t)
+
+	_, err := Discover(context.Background(), Config{
+		Issuer:   m.server.URL + "/other",
+		ClientID: testClientID,
+	})
+	if err == nil {
+		t.Error("expected error for mismatched issuer")
+	}
+}
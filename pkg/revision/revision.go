@@ -0,0 +1,390 @@
+// Package revision records the state of entities after each change to them,
+// so that their history can be browsed and compared.
+package revision
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// Event describes a change to an entity.
+type Event struct {
+	TargetType models.TargetTypeEnum
+	Operation  models.OperationEnum
+	TargetID   uuid.UUID
+	// UserID is the user making the change, or the author of the applied
+	// edit.
+	UserID *uuid.UUID
+	// EditID is set when the change was made by applying an edit.
+	EditID *uuid.UUID
+}
+
+// Record stores a revision holding the current state of the changed entity.
+// It must be called in the transaction making the change, once all of its
+// writes have been made.
+func Record(fac models.Repo, event Event) error {
+	details, err := CurrentDetails(fac, event.TargetType, event.TargetID)
+	if err != nil {
+		return err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	revision := models.NewRevision(UUID, event.TargetType, event.Operation, event.TargetID)
+	if event.UserID != nil {
+		revision.UserID = uuid.NullUUID{UUID: *event.UserID, Valid: true}
+	}
+	if event.EditID != nil {
+		revision.EditID = uuid.NullUUID{UUID: *event.EditID, Valid: true}
+	}
+	if err := revision.SetData(details); err != nil {
+		return err
+	}
+
+	_, err = fac.Revision().Create(*revision)
+	return err
+}
+
+// CurrentDetails returns the current state of the entity as edit details.
+// List fields hold the full contents in the added fields.
+func CurrentDetails(fac models.Repo, targetType models.TargetTypeEnum, id uuid.UUID) (models.EditDetails, error) {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		return tagDetails(fac, id)
+	case models.TargetTypeEnumPerformer:
+		return performerDetails(fac, id)
+	case models.TargetTypeEnumStudio:
+		return studioDetails(fac, id)
+	case models.TargetTypeEnumScene:
+		return sceneDetails(fac, id)
+	default:
+		return nil, errors.New("Not implemented: " + targetType.String())
+	}
+}
+
+func tagDetails(fac models.Repo, id uuid.UUID) (*models.TagEdit, error) {
+	tqb := fac.Tag()
+	tag, err := tqb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, models.NotFoundError(id)
+	}
+
+	// diffing against empty input yields every set field as old data
+	ret := models.TagEditDetailsInput{}.TagEditFromDiff(*tag).Old
+
+	aliases, err := tqb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedAliases = sortedStrings(aliases)
+
+	return ret, nil
+}
+
+func performerDetails(fac models.Repo, id uuid.UUID) (*models.PerformerEdit, error) {
+	pqb := fac.Performer()
+	performer, err := pqb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if performer == nil {
+		return nil, models.NotFoundError(id)
+	}
+
+	ret := models.PerformerEditDetailsInput{}.PerformerEditFromDiff(*performer).Old
+
+	aliases, err := pqb.GetAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedAliases = sortedStrings(aliases.ToAliases())
+
+	urls, err := pqb.GetURLs(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedUrls = urls
+
+	tattoos, err := pqb.GetTattoos(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedTattoos = tattoos.ToBodyModifications()
+
+	piercings, err := pqb.GetPiercings(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedPiercings = piercings.ToBodyModifications()
+
+	images, err := fac.Image().FindByPerformerID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.AddedImages = append(ret.AddedImages, image.ID.String())
+	}
+	sort.Strings(ret.AddedImages)
+
+	relations, err := pqb.GetRelations(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedRelationships = relations.ToInputs(id)
+
+	return ret, nil
+}
+
+func studioDetails(fac models.Repo, id uuid.UUID) (*models.StudioEdit, error) {
+	sqb := fac.Studio()
+	studio, err := sqb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if studio == nil {
+		return nil, models.NotFoundError(id)
+	}
+
+	ret := models.StudioEditDetailsInput{}.StudioEditFromDiff(*studio).Old
+
+	urls, err := sqb.GetURLs(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedUrls = urls
+
+	images, err := fac.Image().FindByStudioID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.AddedImages = append(ret.AddedImages, image.ID.String())
+	}
+	sort.Strings(ret.AddedImages)
+
+	return ret, nil
+}
+
+func sceneDetails(fac models.Repo, id uuid.UUID) (*models.SceneEdit, error) {
+	sqb := fac.Scene()
+	scene, err := sqb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, models.NotFoundError(id)
+	}
+
+	ret := models.SceneEditDetailsInput{}.SceneEditFromDiff(*scene).Old
+
+	urls, err := sqb.GetURLs(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.AddedUrls = urls
+
+	performers, err := sqb.GetPerformers(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, performer := range performers {
+		appearance := &models.PerformerAppearanceInput{
+			PerformerID: performer.PerformerID.String(),
+		}
+		if performer.As.Valid {
+			as := performer.As.String
+			appearance.As = &as
+		}
+		ret.AddedPerformers = append(ret.AddedPerformers, appearance)
+	}
+
+	tags, err := fac.Tag().FindBySceneID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		ret.AddedTags = append(ret.AddedTags, tag.ID.String())
+	}
+	sort.Strings(ret.AddedTags)
+
+	images, err := fac.Image().FindBySceneID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		ret.AddedImages = append(ret.AddedImages, image.ID.String())
+	}
+	sort.Strings(ret.AddedImages)
+
+	markers, err := sqb.GetMarkers(id)
+	if err != nil {
+		return nil, err
+	}
+	markerPerformers, err := sqb.GetMarkerPerformers(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, marker := range markers {
+		ret.AddedMarkers = append(ret.AddedMarkers, marker.ToInput(markerPerformers))
+	}
+
+	return ret, nil
+}
+
+func sortedStrings(values []string) []string {
+	ret := append([]string{}, values...)
+	sort.Strings(ret)
+	return ret
+}
+
+// Diff returns the changes between two revisions of the same entity, in
+// the form of edit details. Changed fields hold the value in from in old, and
+// the value in to in new. List entries only in to are added, and those only
+// in from are removed.
+func Diff(from *models.Revision, to *models.Revision) (old models.EditDetails, new models.EditDetails, err error) {
+	if from.TargetType != to.TargetType || from.TargetID != to.TargetID {
+		return nil, nil, errors.New("revisions are not of the same entity")
+	}
+
+	oldData, newData, err := diffData(from.Data, to.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oldRevision := models.Revision{TargetType: from.TargetType, Data: oldData}
+	if old, err = oldRevision.GetDetails(); err != nil {
+		return nil, nil, err
+	}
+	newRevision := models.Revision{TargetType: to.TargetType, Data: newData}
+	if new, err = newRevision.GetDetails(); err != nil {
+		return nil, nil, err
+	}
+
+	return old, new, nil
+}
+
+const addedPrefix = "added_"
+const removedPrefix = "removed_"
+
+func diffData(from []byte, to []byte) ([]byte, []byte, error) {
+	var fromFields, toFields map[string]json.RawMessage
+	if err := json.Unmarshal(from, &fromFields); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(to, &toFields); err != nil {
+		return nil, nil, err
+	}
+
+	oldFields := make(map[string]json.RawMessage)
+	newFields := make(map[string]json.RawMessage)
+
+	// fields are omitted when unset
+	keys := make(map[string]bool)
+	for k := range fromFields {
+		keys[k] = true
+	}
+	for k := range toFields {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		fromValue, toValue := fromFields[k], toFields[k]
+		if strings.HasPrefix(k, addedPrefix) {
+			added, removed, err := diffList(fromValue, toValue)
+			if err != nil {
+				return nil, nil, err
+			}
+			name := strings.TrimPrefix(k, addedPrefix)
+			if added != nil {
+				newFields[addedPrefix+name] = added
+			}
+			if removed != nil {
+				newFields[removedPrefix+name] = removed
+			}
+			continue
+		}
+
+		if isNull(fromValue) && isNull(toValue) || bytes.Equal(fromValue, toValue) {
+			continue
+		}
+		if !isNull(fromValue) {
+			oldFields[k] = fromValue
+		}
+		if !isNull(toValue) {
+			newFields[k] = toValue
+		}
+	}
+
+	oldData, err := json.Marshal(oldFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	newData, err := json.Marshal(newFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return oldData, newData, nil
+}
+
+// diffList returns the entries only in to, and the entries only in from.
+// Entries are compared by their JSON encoding. Nil is returned in place of
+// an empty list.
+func diffList(from json.RawMessage, to json.RawMessage) (json.RawMessage, json.RawMessage, error) {
+	var fromEntries, toEntries []json.RawMessage
+	if !isNull(from) {
+		if err := json.Unmarshal(from, &fromEntries); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !isNull(to) {
+		if err := json.Unmarshal(to, &toEntries); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	added, err := listMissing(toEntries, fromEntries)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed, err := listMissing(fromEntries, toEntries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return added, removed, nil
+}
+
+func listMissing(subject []json.RawMessage, against []json.RawMessage) (json.RawMessage, error) {
+	existing := make(map[string]bool)
+	for _, v := range against {
+		existing[string(v)] = true
+	}
+
+	var missing []json.RawMessage
+	for _, v := range subject {
+		if !existing[string(v)] {
+			missing = append(missing, v)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(missing)
+}
+
+func isNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
@@ -0,0 +1,73 @@
+package revision
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func newTestRevision(t *testing.T, targetID uuid.UUID, details *models.PerformerEdit) *models.Revision {
+	t.Helper()
+	ret := models.NewRevision(uuid.Must(uuid.NewV4()), models.TargetTypeEnumPerformer, models.OperationEnumModify, targetID)
+	if err := ret.SetData(details); err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestDiff(t *testing.T) {
+	name := "name"
+	newName := "new name"
+	country := "AU"
+	height := int64(170)
+	targetID := uuid.Must(uuid.NewV4())
+
+	from := newTestRevision(t, targetID, &models.PerformerEdit{
+		Name:         &name,
+		Country:      &country,
+		Height:       &height,
+		AddedAliases: []string{"a", "b"},
+		AddedUrls:    []*models.URL{{URL: "http://example.org", Type: "HOME"}},
+	})
+	to := newTestRevision(t, targetID, &models.PerformerEdit{
+		Name:         &newName,
+		Height:       &height,
+		AddedAliases: []string{"b", "c"},
+		AddedUrls:    []*models.URL{{URL: "http://example.org", Type: "HOME"}},
+	})
+
+	old, new, err := Diff(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantOld := &models.PerformerEdit{
+		Name:    &name,
+		Country: &country,
+	}
+	if !reflect.DeepEqual(old, wantOld) {
+		t.Errorf("old = %+v, want %+v", old, wantOld)
+	}
+
+	wantNew := &models.PerformerEdit{
+		Name:           &newName,
+		AddedAliases:   []string{"c"},
+		RemovedAliases: []string{"a"},
+	}
+	if !reflect.DeepEqual(new, wantNew) {
+		t.Errorf("new = %+v, want %+v", new, wantNew)
+	}
+}
+
+func TestDiffDifferentTargets(t *testing.T) {
+	name := "name"
+	from := newTestRevision(t, uuid.Must(uuid.NewV4()), &models.PerformerEdit{Name: &name})
+	to := newTestRevision(t, uuid.Must(uuid.NewV4()), &models.PerformerEdit{Name: &name})
+
+	if _, _, err := Diff(from, to); err == nil {
+		t.Error("expected error comparing revisions of different entities")
+	}
+}
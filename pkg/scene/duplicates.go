@@ -0,0 +1,165 @@
+package scene
+
+import (
+	"sort"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// FindDuplicates returns clusters of non-deleted scenes with matching
+// fingerprints, largest first. PHASHes within distance of each other are
+// considered matching. If studioID is set, only scenes of that studio are
+// considered. At most limit clusters are returned.
+func FindDuplicates(fac models.Repo, distance int, studioID *uuid.UUID, limit int) ([]*models.SceneDuplicateCluster, error) {
+	qb := fac.Scene()
+
+	pairs, err := qb.FindDuplicatePairs(distance, studioID)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := clusterDuplicates(pairs)
+	if len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+
+	var ids []uuid.UUID
+	for _, c := range clusters {
+		ids = append(ids, c...)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	scenes, errs := qb.FindByIds(ids)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fingerprints, errs := qb.GetAllFingerprints(uuid.Nil, ids)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	candidates := make(map[uuid.UUID]mergeCandidate)
+	for i, scene := range scenes {
+		if scene == nil {
+			continue
+		}
+
+		c := mergeCandidate{scene: scene}
+		for _, fp := range fingerprints[i] {
+			c.submissions += fp.Submissions
+		}
+		candidates[scene.ID] = c
+	}
+
+	var ret []*models.SceneDuplicateCluster
+	for _, cluster := range clusters {
+		var clusterCandidates []mergeCandidate
+		for _, id := range cluster {
+			if c, ok := candidates[id]; ok {
+				clusterCandidates = append(clusterCandidates, c)
+			}
+		}
+
+		if len(clusterCandidates) < 2 {
+			continue
+		}
+
+		sortMergeCandidates(clusterCandidates)
+
+		result := &models.SceneDuplicateCluster{
+			SuggestedTarget: clusterCandidates[0].scene,
+		}
+		for _, c := range clusterCandidates {
+			result.Scenes = append(result.Scenes, c.scene)
+		}
+		ret = append(ret, result)
+	}
+
+	return ret, nil
+}
+
+// clusterDuplicates groups the scenes of the pairs into connected clusters,
+// ordered by size descending. Scene ids within a cluster are sorted.
+func clusterDuplicates(pairs []*models.SceneDuplicatePair) [][]uuid.UUID {
+	parent := make(map[uuid.UUID]uuid.UUID)
+
+	var find func(id uuid.UUID) uuid.UUID
+	find = func(id uuid.UUID) uuid.UUID {
+		p, ok := parent[id]
+		if !ok {
+			parent[id] = id
+			return id
+		}
+		if p == id {
+			return id
+		}
+
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+
+	for _, pair := range pairs {
+		a := find(pair.SceneID)
+		b := find(pair.DuplicateID)
+		if a != b {
+			parent[b] = a
+		}
+	}
+
+	groups := make(map[uuid.UUID][]uuid.UUID)
+	for id := range parent {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	var ret [][]uuid.UUID
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].String() < group[j].String()
+		})
+		ret = append(ret, group)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if len(ret[i]) != len(ret[j]) {
+			return len(ret[i]) > len(ret[j])
+		}
+		return ret[i][0].String() < ret[j][0].String()
+	})
+
+	return ret
+}
+
+type mergeCandidate struct {
+	scene       *models.Scene
+	submissions int
+}
+
+// sortMergeCandidates orders the candidates by suitability as a merge
+// target: the scene with the most fingerprint submissions first, then the
+// oldest scene.
+func sortMergeCandidates(candidates []mergeCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.submissions != b.submissions {
+			return a.submissions > b.submissions
+		}
+
+		aTime, bTime := a.scene.CreatedAt.Timestamp, b.scene.CreatedAt.Timestamp
+		if !aTime.Equal(bTime) {
+			return aTime.Before(bTime)
+		}
+
+		return a.scene.ID.String() < b.scene.ID.String()
+	})
+}
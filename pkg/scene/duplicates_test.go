@@ -0,0 +1,76 @@
+package scene
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func TestClusterDuplicates(t *testing.T) {
+	ids := make([]uuid.UUID, 6)
+	for i := range ids {
+		ids[i] = uuid.Must(uuid.NewV4())
+	}
+
+	pairs := []*models.SceneDuplicatePair{
+		{SceneID: ids[0], DuplicateID: ids[1]},
+		{SceneID: ids[1], DuplicateID: ids[2]},
+		{SceneID: ids[3], DuplicateID: ids[4]},
+		{SceneID: ids[2], DuplicateID: ids[0]},
+	}
+
+	clusters := clusterDuplicates(pairs)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+
+	if len(clusters[0]) != 3 {
+		t.Errorf("expected largest cluster first with 3 scenes, got %d", len(clusters[0]))
+	}
+	if len(clusters[1]) != 2 {
+		t.Errorf("expected second cluster with 2 scenes, got %d", len(clusters[1]))
+	}
+
+	for _, c := range clusters {
+		for _, id := range c {
+			if id == ids[5] {
+				t.Errorf("unmatched scene should not be clustered")
+			}
+		}
+	}
+
+	if len(clusterDuplicates(nil)) != 0 {
+		t.Errorf("expected no clusters without pairs")
+	}
+}
+
+func TestSortMergeCandidates(t *testing.T) {
+	now := time.Now()
+	newScene := func(age time.Duration) *models.Scene {
+		return &models.Scene{
+			ID:        uuid.Must(uuid.NewV4()),
+			CreatedAt: models.SQLiteTimestamp{Timestamp: now.Add(-age)},
+		}
+	}
+
+	oldest := newScene(time.Hour * 3)
+	older := newScene(time.Hour * 2)
+	popular := newScene(time.Hour)
+
+	candidates := []mergeCandidate{
+		{scene: oldest, submissions: 1},
+		{scene: popular, submissions: 5},
+		{scene: older, submissions: 1},
+	}
+
+	sortMergeCandidates(candidates)
+
+	want := []*models.Scene{popular, oldest, older}
+	for i, c := range candidates {
+		if c.scene != want[i] {
+			t.Errorf("candidate %d: got scene %s, want %s", i, c.scene.ID, want[i].ID)
+		}
+	}
+}
@@ -36,6 +36,14 @@ func (f *repo) Edit() models.EditRepo {
 	return newEditQueryBuilder(f.txnState)
 }
 
+func (f *repo) Change() models.ChangeRepo {
+	return newChangeQueryBuilder(f.txnState)
+}
+
+func (f *repo) Revision() models.RevisionRepo {
+	return newRevisionQueryBuilder(f.txnState)
+}
+
 func (f *repo) Joins() models.JoinsRepo {
 	return newJoinsQueryBuilder(f.txnState)
 }
@@ -51,3 +59,27 @@ func (f *repo) Invite() models.InviteKeyRepo {
 func (f *repo) User() models.UserRepo {
 	return newUserQueryBuilder(f.txnState)
 }
+
+func (f *repo) APIKey() models.APIKeyRepo {
+	return newAPIKeyQueryBuilder(f.txnState)
+}
+
+func (f *repo) Notification() models.NotificationRepo {
+	return newNotificationQueryBuilder(f.txnState)
+}
+
+func (f *repo) Watch() models.WatchRepo {
+	return newWatchQueryBuilder(f.txnState)
+}
+
+func (f *repo) Webhook() models.WebhookRepo {
+	return newWebhookQueryBuilder(f.txnState)
+}
+
+func (f *repo) Job() models.JobRepo {
+	return newJobQueryBuilder(f.txnState)
+}
+
+func (f *repo) Dataset() models.DatasetRepo {
+	return newDatasetQueryBuilder(f.txnState)
+}
@@ -0,0 +1,213 @@
+package sqlx
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	defaultCursorPageSize = 25
+	maxCursorPageSize     = 1000
+)
+
+// keysetCursor identifies the position of a row in a keyset paginated
+// query. Value is the value of the sort column, and ID breaks ties between
+// rows with the same value.
+type keysetCursor struct {
+	Sort  string    `json:"s"`
+	Value *string   `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodeCursor(c keysetCursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string) (*keysetCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var ret keysetCursor
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &ret, nil
+}
+
+// keysetValue returns the value of the field with the provided db tag,
+// formatted so that it can be used as a query argument.
+func keysetValue(obj interface{}, column string) (*string, error) {
+	v := reflect.ValueOf(obj)
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.Split(v.Type().Field(i).Tag.Get("db"), ",")[0]
+		if key != column {
+			continue
+		}
+
+		var ret string
+		switch t := v.Field(i).Interface().(type) {
+		case string:
+			ret = t
+		case sql.NullString:
+			if !t.Valid {
+				return nil, nil
+			}
+			ret = t.String
+		case models.SQLiteDate:
+			if !t.Valid {
+				return nil, nil
+			}
+			ret = t.String
+		case models.SQLiteTimestamp:
+			ret = t.Timestamp.Format(time.RFC3339Nano)
+		default:
+			return nil, fmt.Errorf("unsupported keyset column type for %s", column)
+		}
+
+		return &ret, nil
+	}
+
+	return nil, fmt.Errorf("keyset column %s not found", column)
+}
+
+// queryPage runs the query, returning the page of rows after the cursor in
+// findFilter, ordered by the sort column followed by id. Only sorts in
+// sortColumns are supported.
+func (q dbi) queryPage(query *queryBuilder, findFilter *models.CursorQuerySpec, defaultSort string, sortColumns []string, output Models) (*models.Page, error) {
+	if findFilter == nil {
+		findFilter = &models.CursorQuerySpec{}
+	}
+
+	sort := findFilter.GetSort(defaultSort)
+	validSort := false
+	for _, c := range sortColumns {
+		if c == sort {
+			validSort = true
+		}
+	}
+	if !validSort {
+		return nil, fmt.Errorf("unsupported sort %s for cursor pagination", sort)
+	}
+
+	count, err := q.Count(*query)
+	if err != nil {
+		return nil, err
+	}
+
+	tableName := query.Table.Name()
+	column := getColumn(tableName, sort)
+	idColumn := getColumn(tableName, "id")
+	direction := findFilter.GetDirection()
+	op := ">"
+	if direction == "DESC" {
+		op = "<"
+	}
+
+	if findFilter.After != nil && *findFilter.After != "" {
+		cursor, err := decodeCursor(*findFilter.After)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != sort {
+			return nil, ErrInvalidCursor
+		}
+
+		// nulls are sorted last
+		if cursor.Value == nil {
+			query.AddWhere("(" + column + " IS NULL AND " + idColumn + " " + op + " ?)")
+			query.AddArg(cursor.ID)
+		} else {
+			query.AddWhere("(" + column + " " + op + " ? OR (" + column + " = ? AND " + idColumn + " " + op + " ?) OR " + column + " IS NULL)")
+			query.AddArg(*cursor.Value, *cursor.Value, cursor.ID)
+		}
+	}
+
+	first := findFilter.GetFirst(defaultCursorPageSize)
+	if first > maxCursorPageSize {
+		first = maxCursorPageSize
+	}
+
+	// fetch an extra row to determine if there is a next page
+	query.SortAndPagination = " ORDER BY " + column + " " + direction + q.txn.dialect.NullsLast() + ", " + idColumn + " " + direction +
+		" LIMIT " + strconv.Itoa(first+1)
+
+	page := &pageModels{
+		output: output,
+		sort:   sort,
+		first:  first,
+	}
+	if err := q.RawQuery(query.Table, query.buildQuery(), query.args, page); err != nil {
+		return nil, err
+	}
+	if page.err != nil {
+		return nil, page.err
+	}
+
+	return &models.Page{
+		Cursors:     page.cursors,
+		HasNextPage: page.hasNextPage,
+		Count:       count,
+	}, nil
+}
+
+// pageModels adds up to first rows to output, generating a cursor for each
+// row, and records if there are any further rows.
+type pageModels struct {
+	output Models
+	sort   string
+	first  int
+
+	cursors     []string
+	hasNextPage bool
+	err         error
+}
+
+func (p *pageModels) Add(obj interface{}) {
+	if p.err != nil {
+		return
+	}
+	if len(p.cursors) == p.first {
+		p.hasNextPage = true
+		return
+	}
+
+	value, err := keysetValue(reflect.Indirect(reflect.ValueOf(obj)).Interface(), p.sort)
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	cursor, err := encodeCursor(keysetCursor{
+		Sort:  p.sort,
+		Value: value,
+		ID:    obj.(Model).GetID(),
+	})
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	p.cursors = append(p.cursors, cursor)
+	p.output.Add(obj)
+}
@@ -0,0 +1,57 @@
+package sqlx
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var apiKeyDBTable = newTable("api_keys", func() interface{} {
+	return &models.APIKey{}
+})
+
+type apiKeyQueryBuilder struct {
+	dbi *dbi
+}
+
+func newAPIKeyQueryBuilder(txn *txnState) models.APIKeyRepo {
+	return &apiKeyQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *apiKeyQueryBuilder) toModel(ro interface{}) *models.APIKey {
+	if ro != nil {
+		return ro.(*models.APIKey)
+	}
+
+	return nil
+}
+
+func (qb *apiKeyQueryBuilder) Create(newKey models.APIKey) (*models.APIKey, error) {
+	ret, err := qb.dbi.Insert(apiKeyDBTable, newKey)
+	return qb.toModel(ret), err
+}
+
+func (qb *apiKeyQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, apiKeyDBTable)
+}
+
+func (qb *apiKeyQueryBuilder) Find(id uuid.UUID) (*models.APIKey, error) {
+	ret, err := qb.dbi.Find(id, apiKeyDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *apiKeyQueryBuilder) FindByUserID(userID uuid.UUID) (models.APIKeys, error) {
+	query := "SELECT * FROM " + apiKeyDBTable.Name() + " WHERE user_id = ? ORDER BY created_at ASC"
+	var output models.APIKeys
+	err := qb.dbi.RawQuery(apiKeyDBTable, query, []interface{}{userID}, &output)
+	return output, err
+}
+
+func (qb *apiKeyQueryBuilder) UpdateLastUsed(id uuid.UUID, lastUsed time.Time) error {
+	query := "UPDATE " + apiKeyDBTable.Name() + " SET last_used_at = ? WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)"
+	t := models.SQLiteTimestamp{Timestamp: lastUsed}
+	return qb.dbi.RawExec(query, []interface{}{t, id, t})
+}
@@ -0,0 +1,76 @@
+package sqlx
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var changeDBTable = newTable("change_log", func() interface{} {
+	return &models.Change{}
+})
+
+// changeLogLockID identifies the advisory lock that serializes writes to
+// the change log.
+const changeLogLockID = 0x6368616e6765 // "change"
+
+type changeQueryBuilder struct {
+	dbi *dbi
+}
+
+func newChangeQueryBuilder(txn *txnState) models.ChangeRepo {
+	return &changeQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *changeQueryBuilder) Log(targetType models.TargetTypeEnum, operation models.OperationEnum, targetID uuid.UUID, mergedIntoID *uuid.UUID) error {
+	mergedInto := uuid.NullUUID{}
+	if mergedIntoID != nil {
+		mergedInto = uuid.NullUUID{UUID: *mergedIntoID, Valid: true}
+	}
+
+	// Ids are taken from a sequence when inserting, but become visible when
+	// the transaction commits, so a reader could see a later id before an
+	// earlier one and skip it by moving its cursor past it. Holding the lock
+	// until commit makes transactions take ids in the order they commit.
+	if err := qb.dbi.RawExec(`SELECT pg_advisory_xact_lock(?)`, []interface{}{changeLogLockID}); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO ` + changeDBTable.Name() + ` (target_type, target_id, operation, merged_into_id, created_at) VALUES (?, ?, ?, ?, ?)`
+	args := []interface{}{
+		targetType.String(),
+		targetID,
+		operation.String(),
+		mergedInto,
+		models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	return qb.dbi.RawExec(query, args)
+}
+
+func (qb *changeQueryBuilder) FindAfter(afterID int64, since *time.Time, limit int) (models.Changes, error) {
+	query := `SELECT * FROM ` + changeDBTable.Name() + ` WHERE id > ?`
+	args := []interface{}{afterID}
+
+	if since != nil {
+		query += ` AND created_at >= ?`
+		args = append(args, models.SQLiteTimestamp{Timestamp: *since})
+	}
+
+	query += ` ORDER BY id ASC LIMIT ?`
+	args = append(args, limit)
+
+	var output models.Changes
+	err := qb.dbi.RawQuery(changeDBTable, query, args, &output)
+	return output, err
+}
+
+// logChange records a change in the change log as part of the current
+// transaction.
+func logChange(txn *txnState, targetType models.TargetTypeEnum, operation models.OperationEnum, targetID uuid.UUID, mergedIntoID *uuid.UUID) error {
+	return newChangeQueryBuilder(txn).Log(targetType, operation, targetID, mergedIntoID)
+}
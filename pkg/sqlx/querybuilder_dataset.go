@@ -0,0 +1,127 @@
+package sqlx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// datasetTables are ordered so that referenced rows are always imported
+// before the rows referencing them.
+var datasetTables = []string{
+	"tag_categories",
+	"tags",
+	"tag_aliases",
+	"tag_redirects",
+	"studios",
+	"studio_urls",
+	"studio_redirects",
+	"performers",
+	"performer_aliases",
+	"performer_urls",
+	"performer_tattoos",
+	"performer_piercings",
+	"performer_redirects",
+	"performer_relationships",
+	"images",
+	"performer_images",
+	"studio_images",
+	"scenes",
+	"scene_urls",
+	"scene_fingerprints",
+	"scene_performers",
+	"scene_tags",
+	"scene_images",
+	"scene_markers",
+	"scene_marker_performers",
+	"scene_redirects",
+}
+
+var datasetExportQueries = map[string]string{
+	// parent studios must be exported before their children
+	"studios": `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM studios WHERE parent_studio_id IS NULL
+			UNION ALL
+			SELECT S.id, T.depth + 1 FROM studios S JOIN tree T ON S.parent_studio_id = T.id
+		)
+		SELECT row_to_json(S) FROM studios S JOIN tree T ON S.id = T.id ORDER BY T.depth
+	`,
+	// fingerprint submissions are tied to users, which are not exported, so
+	// they are combined into a single row per fingerprint
+	"scene_fingerprints": `
+		SELECT json_build_object(
+			'scene_id', scene_id,
+			'hash', hash,
+			'algorithm', algorithm,
+			'duration', MAX(duration),
+			'submissions', SUM(submissions),
+			'created_at', MIN(created_at),
+			'updated_at', MAX(updated_at)
+		)
+		FROM scene_fingerprints
+		GROUP BY scene_id, algorithm, hash
+	`,
+}
+
+type datasetQueryBuilder struct {
+	dbi *dbi
+}
+
+func newDatasetQueryBuilder(txn *txnState) models.DatasetRepo {
+	return &datasetQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *datasetQueryBuilder) Tables() []string {
+	return datasetTables
+}
+
+func (qb *datasetQueryBuilder) validateTable(table string) error {
+	for _, t := range datasetTables {
+		if t == table {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("table %s is not part of the dataset", table)
+}
+
+func (qb *datasetQueryBuilder) BeginSnapshot() error {
+	_, err := qb.dbi.db().Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY")
+	return err
+}
+
+func (qb *datasetQueryBuilder) ExportRows(table string, fn func(row json.RawMessage) error) error {
+	if err := qb.validateTable(table); err != nil {
+		return err
+	}
+
+	query, ok := datasetExportQueries[table]
+	if !ok {
+		query = fmt.Sprintf("SELECT row_to_json(T) FROM %s T", table)
+	}
+
+	return qb.dbi.queryFunc(query, nil, func(rows *sqlx.Rows) error {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+
+		return fn(row)
+	})
+}
+
+func (qb *datasetQueryBuilder) ImportRow(table string, row json.RawMessage) error {
+	if err := qb.validateTable(table); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %[1]s SELECT * FROM json_populate_record(NULL::%[1]s, ?)", table)
+	_, err := qb.dbi.db().Exec(qb.dbi.db().Rebind(query), string(row))
+	return err
+}
@@ -18,6 +18,7 @@ const (
 	sceneEditTable     = "scene_edits"
 	commentTable       = "edit_comments"
 	voteTable          = "edit_votes"
+	amendmentTable     = "edit_amendments"
 )
 
 var (
@@ -48,6 +49,10 @@ var (
 	editVoteTable = newTableJoin(editTable, voteTable, editJoinKey, func() interface{} {
 		return &models.EditVote{}
 	})
+
+	editAmendmentTable = newTableJoin(editTable, amendmentTable, editJoinKey, func() interface{} {
+		return &models.EditAmendment{}
+	})
 )
 
 type editQueryBuilder struct {
@@ -195,13 +200,39 @@ func (qb *editQueryBuilder) Count() (int, error) {
 }
 
 func (qb *editQueryBuilder) Query(editFilter *models.EditFilterType, findFilter *models.QuerySpec) ([]*models.Edit, int, error) {
-	if editFilter == nil {
-		editFilter = &models.EditFilterType{}
-	}
 	if findFilter == nil {
 		findFilter = &models.QuerySpec{}
 	}
 
+	query, err := qb.buildQuery(editFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query.SortAndPagination = qb.getEditSort(findFilter) + getPagination(findFilter)
+
+	var edits models.Edits
+	countResult, err := qb.dbi.Query(*query, &edits)
+
+	return edits, countResult, err
+}
+
+func (qb *editQueryBuilder) QueryPage(editFilter *models.EditFilterType, findFilter *models.CursorQuerySpec) ([]*models.Edit, *models.Page, error) {
+	query, err := qb.buildQuery(editFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var edits models.Edits
+	page, err := qb.dbi.queryPage(query, findFilter, "updated_at", []string{"created_at", "updated_at"}, &edits)
+	return edits, page, err
+}
+
+func (qb *editQueryBuilder) buildQuery(editFilter *models.EditFilterType) (*queryBuilder, error) {
+	if editFilter == nil {
+		editFilter = &models.EditFilterType{}
+	}
+
 	query := newQueryBuilder(editDBTable)
 
 	if q := editFilter.UserID; q != nil && *q != "" {
@@ -211,10 +242,10 @@ func (qb *editQueryBuilder) Query(editFilter *models.EditFilterType, findFilter
 	if q := editFilter.TargetID; q != nil {
 		targetID, err := uuid.FromString(*q)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		if editFilter.TargetType == nil || *editFilter.TargetType == "" {
-			return nil, 0, errors.New("TargetType is required when TargetID filter is used")
+			return nil, errors.New("TargetType is required when TargetID filter is used")
 		}
 		if *editFilter.TargetType == models.TargetTypeEnumTag {
 			query.AddJoin(editTagTable.table, editTagTable.Name()+".edit_id = edits.id")
@@ -229,7 +260,7 @@ func (qb *editQueryBuilder) Query(editFilter *models.EditFilterType, findFilter
 			query.AddJoin(editSceneTable.table, editSceneTable.Name()+".edit_id = edits.id")
 			query.AddWhere("(" + editSceneTable.Name() + ".scene_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?)")
 		} else {
-			return nil, 0, fmt.Errorf("TargetType is not yet supported: %s", *editFilter.TargetType)
+			return nil, fmt.Errorf("TargetType is not yet supported: %s", *editFilter.TargetType)
 		}
 		jsonID, _ := json.Marshal(targetID)
 		query.AddArg(targetID, jsonID)
@@ -247,12 +278,7 @@ func (qb *editQueryBuilder) Query(editFilter *models.EditFilterType, findFilter
 		query.Eq("applied", *q)
 	}
 
-	query.SortAndPagination = qb.getEditSort(findFilter) + getPagination(findFilter)
-
-	var edits models.Edits
-	countResult, err := qb.dbi.Query(*query, &edits)
-
-	return edits, countResult, err
+	return query, nil
 }
 
 func (qb *editQueryBuilder) getEditSort(findFilter *models.QuerySpec) string {
@@ -300,6 +326,30 @@ func (qb *editQueryBuilder) GetVotes(id uuid.UUID) (models.EditVotes, error) {
 	return joins, err
 }
 
+func (qb *editQueryBuilder) ResetVotes(id uuid.UUID) error {
+	if err := qb.dbi.DeleteJoins(editVoteTable, id); err != nil {
+		return err
+	}
+
+	// the vote count is only updated by triggers on insert and update
+	query := `UPDATE edits SET votes = 0 WHERE id = ?`
+	args := []interface{}{id}
+	return qb.dbi.RawExec(query, args)
+}
+
+func (qb *editQueryBuilder) CreateAmendment(newJoin models.EditAmendment) error {
+	return qb.dbi.InsertJoin(editAmendmentTable, newJoin, nil)
+}
+
+func (qb *editQueryBuilder) GetAmendments(id uuid.UUID) (models.EditAmendments, error) {
+	query := selectStatement(editAmendmentTable.table) + " WHERE edit_id = ? ORDER BY created_at"
+	args := []interface{}{id}
+
+	joins := models.EditAmendments{}
+	err := qb.dbi.RawQuery(editAmendmentTable.table, query, args, &joins)
+	return joins, err
+}
+
 func (qb *editQueryBuilder) findByJoin(id uuid.UUID, table tableJoin, idColumn string) ([]*models.Edit, error) {
 	query := fmt.Sprintf(`
 SELECT edits.* FROM edits
@@ -347,3 +397,21 @@ func (qb *editQueryBuilder) FindCompletedEdits(votingPeriod int, minimumVotingPe
 	args := []interface{}{votingPeriod, minimumVotes, minimumVotingPeriod}
 	return qb.queryEdits(query, args)
 }
+
+func (qb *editQueryBuilder) FindReverts(id uuid.UUID) ([]*models.Edit, error) {
+	query := `SELECT edits.* FROM edits WHERE reverts_id = ? ORDER BY created_at`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+func (qb *editQueryBuilder) FindPendingMergesBySourceID(id uuid.UUID) ([]*models.Edit, error) {
+	query := `
+		SELECT edits.* FROM edits
+		WHERE status = 'PENDING'
+		AND operation = 'MERGE'
+		AND data->'merge_sources' @> jsonb_build_array(?::text)
+		ORDER BY created_at
+	`
+	args := []interface{}{id.String()}
+	return qb.queryEdits(query, args)
+}
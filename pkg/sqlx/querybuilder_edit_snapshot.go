@@ -0,0 +1,368 @@
+package sqlx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var editSnapshotTable = newTableJoin(editTable, "edit_snapshots", editJoinKey, func() interface{} {
+	return &models.EditSnapshot{}
+})
+
+// snapshotTable is a table with rows referencing an entity, which are
+// removed or reassigned when the entity is destroyed or merged.
+type snapshotTable struct {
+	table string
+	// column referencing the entity
+	column string
+	// keys are the columns identifying a row
+	keys []string
+	// filter selects rows referencing the entity indirectly, instead of
+	// column. Each placeholder is bound to the entity id.
+	filter string
+	// merged is true if merges reassign the rows to the merge target
+	merged bool
+	// unordered is true if the first two keys may be stored in either order
+	unordered bool
+}
+
+func (t snapshotTable) keyColumn() bool {
+	for _, k := range t.keys {
+		if k == t.column {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotTables lists the tables to snapshot for each target type. Tables
+// are restored in order.
+var snapshotTables = map[models.TargetTypeEnum][]snapshotTable{
+	models.TargetTypeEnumTag: {
+		{table: "tag_aliases", column: "tag_id", keys: []string{"tag_id", "alias"}},
+		{table: "scene_tags", column: "tag_id", keys: []string{"scene_id", "tag_id"}, merged: true},
+		{table: "scene_markers", column: "tag_id", keys: []string{"id"}, merged: true},
+		{
+			table:  "scene_marker_performers",
+			column: "marker_id",
+			keys:   []string{"marker_id", "performer_id"},
+			filter: "marker_id IN (SELECT id FROM scene_markers WHERE tag_id = ?)",
+		},
+		{table: "tag_redirects", column: "target_id", keys: []string{"source_id"}, merged: true},
+	},
+	models.TargetTypeEnumPerformer: {
+		{table: "performer_aliases", column: "performer_id", keys: []string{"performer_id", "alias"}},
+		{table: "performer_urls", column: "performer_id", keys: []string{"performer_id", "url"}},
+		{table: "performer_tattoos", column: "performer_id", keys: []string{"performer_id", "location"}},
+		{table: "performer_piercings", column: "performer_id", keys: []string{"performer_id", "location"}},
+		{table: "performer_images", column: "performer_id", keys: []string{"performer_id", "image_id"}},
+		{table: "scene_performers", column: "performer_id", keys: []string{"scene_id", "performer_id"}, merged: true},
+		{table: "scene_marker_performers", column: "performer_id", keys: []string{"marker_id", "performer_id"}, merged: true},
+		{table: "performer_relationships", column: "performer_id", keys: []string{"performer_id", "related_performer_id", "type"}, merged: true, unordered: true},
+		{table: "performer_relationships", column: "related_performer_id", keys: []string{"performer_id", "related_performer_id", "type"}, merged: true, unordered: true},
+		{table: "performer_redirects", column: "target_id", keys: []string{"source_id"}, merged: true},
+	},
+	models.TargetTypeEnumStudio: {
+		{table: "studio_urls", column: "studio_id", keys: []string{"studio_id", "url"}},
+		{table: "studio_images", column: "studio_id", keys: []string{"studio_id", "image_id"}},
+		{table: "scenes", column: "studio_id", keys: []string{"id"}, merged: true},
+		{table: "studio_redirects", column: "target_id", keys: []string{"source_id"}, merged: true},
+	},
+	models.TargetTypeEnumScene: {
+		{table: "scene_urls", column: "scene_id", keys: []string{"scene_id", "url"}},
+		{table: "scene_fingerprints", column: "scene_id", keys: []string{"scene_id", "algorithm", "hash", "user_id"}},
+		{table: "scene_images", column: "scene_id", keys: []string{"scene_id", "image_id"}},
+		{table: "scene_performers", column: "scene_id", keys: []string{"scene_id", "performer_id"}},
+		{table: "scene_tags", column: "scene_id", keys: []string{"scene_id", "tag_id"}},
+		{table: "scene_markers", column: "scene_id", keys: []string{"id"}},
+		{
+			table:  "scene_marker_performers",
+			column: "marker_id",
+			keys:   []string{"marker_id", "performer_id"},
+			filter: "marker_id IN (SELECT id FROM scene_markers WHERE scene_id = ?)",
+		},
+		{table: "scene_redirects", column: "target_id", keys: []string{"source_id"}, merged: true},
+	},
+}
+
+var snapshotEntityTables = map[models.TargetTypeEnum]struct {
+	table    string
+	redirect string
+}{
+	models.TargetTypeEnumTag:       {tagTable, "tag_redirects"},
+	models.TargetTypeEnumPerformer: {performerTable, "performer_redirects"},
+	models.TargetTypeEnumStudio:    {studioTable, "studio_redirects"},
+	models.TargetTypeEnumScene:     {sceneTable, "scene_redirects"},
+}
+
+func findSnapshotTable(targetType models.TargetTypeEnum, table string, column string) *snapshotTable {
+	for _, t := range snapshotTables[targetType] {
+		if t.table == table && t.column == column {
+			ret := t
+			return &ret
+		}
+	}
+	return nil
+}
+
+// selectRows returns the rows of the table referencing the entity as a JSON
+// array.
+func (qb *editQueryBuilder) selectRows(t snapshotTable, entityID uuid.UUID) (json.RawMessage, error) {
+	where := t.filter
+	if where == "" {
+		where = t.column + " = ?"
+	}
+
+	var args []interface{}
+	for i := 0; i < strings.Count(where, "?"); i++ {
+		args = append(args, entityID)
+	}
+
+	query := `SELECT COALESCE(json_agg(R), '[]') FROM ` + t.table + ` R WHERE ` + where
+	var ret json.RawMessage
+	err := qb.dbi.queryFunc(query, args, func(rows *sqlx.Rows) error {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return err
+		}
+		ret = json.RawMessage(s)
+		return nil
+	})
+	return ret, err
+}
+
+func (qb *editQueryBuilder) CreateSnapshot(editID uuid.UUID, targetType models.TargetTypeEnum, entityIDs []uuid.UUID, targetID *uuid.UUID) error {
+	tables, ok := snapshotTables[targetType]
+	if !ok {
+		return fmt.Errorf("unsupported target type: %s", targetType)
+	}
+
+	data := models.EditSnapshotData{
+		TargetType: targetType.String(),
+		EntityIDs:  entityIDs,
+		TargetID:   targetID,
+	}
+
+	for _, entityID := range entityIDs {
+		for _, t := range tables {
+			rows, err := qb.selectRows(t, entityID)
+			if err != nil {
+				return err
+			}
+
+			tableRows := &models.EditSnapshotRows{
+				Table:    t.table,
+				Column:   t.column,
+				EntityID: entityID,
+				Rows:     rows,
+			}
+
+			if targetID != nil && t.merged {
+				tableRows.TargetRows, err = qb.selectRows(t, *targetID)
+				if err != nil {
+					return err
+				}
+			}
+
+			data.Tables = append(data.Tables, tableRows)
+		}
+	}
+
+	snapshot := models.EditSnapshot{
+		EditID:    editID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+	if err := snapshot.SetData(data); err != nil {
+		return err
+	}
+
+	return qb.dbi.InsertJoin(editSnapshotTable, snapshot, nil)
+}
+
+func (qb *editQueryBuilder) RestoreSnapshot(editID uuid.UUID) error {
+	snapshots := models.EditSnapshots{}
+	if err := qb.dbi.FindJoins(editSnapshotTable, editID, &snapshots); err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return errors.New("no snapshot recorded for edit " + editID.String())
+	}
+
+	data, err := snapshots[0].GetData()
+	if err != nil {
+		return err
+	}
+
+	targetType := models.TargetTypeEnum(data.TargetType)
+	entity, ok := snapshotEntityTables[targetType]
+	if !ok {
+		return fmt.Errorf("unsupported target type: %s", data.TargetType)
+	}
+
+	now := models.SQLiteTimestamp{Timestamp: time.Now()}
+	for _, id := range data.EntityIDs {
+		query := `UPDATE ` + entity.table + ` SET deleted = FALSE, updated_at = ? WHERE id = ?`
+		if err := qb.dbi.RawExec(query, []interface{}{now, id}); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM ` + entity.redirect + ` WHERE source_id = ?`
+		if err := qb.dbi.RawExec(query, []interface{}{id}); err != nil {
+			return err
+		}
+
+		if err := logChange(qb.dbi.txn, targetType, models.OperationEnumRestore, id, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, rows := range data.Tables {
+		t := findSnapshotTable(targetType, rows.Table, rows.Column)
+		if t == nil {
+			return fmt.Errorf("unsupported snapshot table: %s.%s", rows.Table, rows.Column)
+		}
+
+		if err := qb.restoreRows(*t, rows, data.TargetID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *editQueryBuilder) restoreRows(t snapshotTable, rows *models.EditSnapshotRows, targetID *uuid.UUID) error {
+	sourceRows, err := decodeSnapshotRows(rows.Rows)
+	if err != nil {
+		return err
+	}
+
+	// remove the rows that were reassigned to the merge target
+	if targetID != nil && t.merged && t.keyColumn() {
+		targetRows, err := decodeSnapshotRows(rows.TargetRows)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]bool)
+		for _, row := range targetRows {
+			existing[t.rowKey(row)] = true
+		}
+
+		for _, row := range sourceRows {
+			moved := make(map[string]interface{})
+			for k, v := range row {
+				moved[k] = v
+			}
+			moved[t.column] = targetID.String()
+
+			if !existing[t.rowKey(moved)] {
+				if err := qb.deleteRow(t, moved); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, row := range sourceRows {
+		if err := qb.insertRow(t, row); err != nil {
+			return err
+		}
+
+		// reassign rows which were not deleted
+		if t.filter == "" && !t.keyColumn() {
+			where, args := t.rowWhere(row, false)
+			query := `UPDATE ` + t.table + ` SET ` + t.column + ` = ? WHERE ` + where
+			args = append([]interface{}{rows.EntityID}, args...)
+			if err := qb.dbi.RawExec(query, args); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// insertRow inserts the row unless a row with the same keys exists.
+func (qb *editQueryBuilder) insertRow(t snapshotTable, row map[string]interface{}) error {
+	value, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	var match []string
+	for _, k := range t.keys {
+		match = append(match, "E."+k+" IS NOT DISTINCT FROM R."+k)
+	}
+
+	query := `INSERT INTO ` + t.table + `
+		SELECT R.* FROM json_populate_record(NULL::` + t.table + `, ?) R
+		WHERE NOT EXISTS (SELECT 1 FROM ` + t.table + ` E WHERE ` + strings.Join(match, " AND ") + `)
+		ON CONFLICT DO NOTHING`
+	return qb.dbi.RawExec(query, []interface{}{string(value)})
+}
+
+func (qb *editQueryBuilder) deleteRow(t snapshotTable, row map[string]interface{}) error {
+	where, args := t.rowWhere(row, t.unordered)
+	query := `DELETE FROM ` + t.table + ` WHERE ` + where
+	return qb.dbi.RawExec(query, args)
+}
+
+// rowWhere returns a where clause matching the keys of the row.
+func (t snapshotTable) rowWhere(row map[string]interface{}, unordered bool) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for _, k := range t.keys {
+		clauses = append(clauses, k+" IS NOT DISTINCT FROM ?")
+		args = append(args, row[k])
+	}
+	where := "(" + strings.Join(clauses, " AND ") + ")"
+
+	if unordered {
+		swapped := make(map[string]interface{})
+		for k, v := range row {
+			swapped[k] = v
+		}
+		swapped[t.keys[0]], swapped[t.keys[1]] = row[t.keys[1]], row[t.keys[0]]
+
+		swappedWhere, swappedArgs := t.rowWhere(swapped, false)
+		where += " OR " + swappedWhere
+		args = append(args, swappedArgs...)
+	}
+
+	return where, args
+}
+
+func (t snapshotTable) rowKey(row map[string]interface{}) string {
+	var values []string
+	for _, k := range t.keys {
+		values = append(values, fmt.Sprint(row[k]))
+	}
+	if t.unordered {
+		sort.Strings(values[:2])
+	}
+	return strings.Join(values, "|")
+}
+
+func decodeSnapshotRows(data json.RawMessage) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if len(data) == 0 {
+		return rows, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
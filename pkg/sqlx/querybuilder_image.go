@@ -15,6 +15,10 @@ var (
 	imageDBTable = newTable(imageTable, func() interface{} {
 		return &models.Image{}
 	})
+
+	imageVariantTable = newTableJoin(imageTable, "image_variants", "image_id", func() interface{} {
+		return &models.ImageVariant{}
+	})
 )
 
 type imageQueryBuilder struct {
@@ -83,22 +87,43 @@ func (qb *imageQueryBuilder) FindByChecksum(checksum string) (*models.Image, err
 	return nil, nil
 }
 
+// editImagesQuery selects the ids of images referenced by edits, which must
+// not be destroyed: images added by pending edits, images removed by applied
+// edits and images in the join rows of edit snapshots, which are restored
+// when the edit is reverted.
+const editImagesQuery = `
+	SELECT DISTINCT image_id FROM (
+		SELECT jsonb_array_elements_text(data#>'{new_data,added_images}')::uuid AS image_id
+		FROM edits
+		WHERE status = 'PENDING' AND jsonb_typeof(data#>'{new_data,added_images}') = 'array'
+		UNION ALL
+		SELECT jsonb_array_elements_text(data#>'{new_data,removed_images}')::uuid
+		FROM edits
+		WHERE applied AND jsonb_typeof(data#>'{new_data,removed_images}') = 'array'
+		UNION ALL
+		SELECT (snapshot_row->>'image_id')::uuid
+		FROM edit_snapshots
+		CROSS JOIN jsonb_array_elements(edit_snapshots.data->'tables') snapshot_table
+		CROSS JOIN jsonb_array_elements(
+			CASE WHEN jsonb_typeof(snapshot_table->'rows') = 'array' THEN snapshot_table->'rows' ELSE '[]' END ||
+			CASE WHEN jsonb_typeof(snapshot_table->'target_rows') = 'array' THEN snapshot_table->'target_rows' ELSE '[]' END
+		) snapshot_row
+		WHERE snapshot_table->>'table' IN ('performer_images', 'studio_images', 'scene_images')
+	) referenced
+`
+
 func (qb *imageQueryBuilder) FindUnused() ([]*models.Image, error) {
 	query := `
 		SELECT images.* from images
 		LEFT JOIN scene_images ON scene_images.image_id = images.id
 		LEFT JOIN performer_images ON performer_images.image_id = images.id
 		LEFT JOIN studio_images ON studio_images.image_id = images.id
-		LEFT JOIN (
-			SELECT (jsonb_array_elements(data#>'{new_data,added_images}')->>0)::uuid AS image_id
-			FROM edits
-			WHERE status = 'PENDING'
-		) edit_images ON edit_images.image_id = images.id
+		LEFT JOIN (` + editImagesQuery + `) edit_images ON edit_images.image_id = images.id
 		WHERE 
 		scene_images.scene_id IS NULL AND 
 		performer_images.performer_id IS NULL AND
 		studio_images IS NULL AND
-		edit_images IS NULL LIMIT 1000
+		edit_images.image_id IS NULL LIMIT 1000
 	`
 	args := []interface{}{}
 
@@ -112,11 +137,7 @@ func (qb *imageQueryBuilder) IsUnused(imageID uuid.UUID) (bool, error) {
 		LEFT JOIN scene_images ON scene_images.image_id = images.id
 		LEFT JOIN performer_images ON performer_images.image_id = images.id
 		LEFT JOIN studio_images ON studio_images.image_id = images.id
-		LEFT JOIN (
-			SELECT (jsonb_array_elements(data#>'{new_data,added_images}')->>0)::uuid AS image_id
-			FROM edits
-			WHERE status = 'PENDING'
-		) edit_images ON edit_images.image_id = images.id`
+		LEFT JOIN (` + editImagesQuery + `) edit_images ON edit_images.image_id = images.id`
 
 	query.AddWhere("images.id = ?")
 	query.AddArg(imageID)
@@ -241,3 +262,119 @@ func (qb *imageQueryBuilder) queryImages(query string, args []interface{}) (mode
 	err := qb.dbi.RawQuery(imageDBTable, query, args, &output)
 	return output, err
 }
+
+func (qb *imageQueryBuilder) CreateVariant(newVariant models.ImageVariant) error {
+	conflictHandling := `
+		ON CONFLICT(image_id, name)
+		DO UPDATE SET (format, width, height) = (EXCLUDED.format, EXCLUDED.width, EXCLUDED.height)
+	`
+	return qb.dbi.InsertJoin(imageVariantTable, newVariant, &conflictHandling)
+}
+
+func (qb *imageQueryBuilder) FindVariant(imageID uuid.UUID, name string) (*models.ImageVariant, error) {
+	query := selectStatement(imageVariantTable.table) + ` WHERE image_id = ? AND name = ?`
+
+	var output models.ImageVariants
+	if err := qb.dbi.RawQuery(imageVariantTable.table, query, []interface{}{imageID, name}, &output); err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+	return output[0], nil
+}
+
+func (qb *imageQueryBuilder) FindVariants(imageID uuid.UUID) (models.ImageVariants, error) {
+	var output models.ImageVariants
+	err := qb.dbi.FindJoins(imageVariantTable, imageID, &output)
+	return output, err
+}
+
+func (qb *imageQueryBuilder) FindVariantsByImageIDs(imageIDs []uuid.UUID) (models.ImageVariants, error) {
+	query, args, err := sqlx.In(selectStatement(imageVariantTable.table)+` WHERE image_id IN (?)`, imageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var output models.ImageVariants
+	err = qb.dbi.RawQuery(imageVariantTable.table, query, args, &output)
+	return output, err
+}
+
+func (qb *imageQueryBuilder) FindMissingVariants(names []string, afterID uuid.UUID, limit int) ([]*models.Image, error) {
+	query := `
+		SELECT images.* FROM images
+		WHERE images.id > ? AND images.checksum <> '' AND images.width > 0
+		AND (
+			SELECT COUNT(*) FROM image_variants
+			WHERE image_variants.image_id = images.id AND image_variants.name IN (?)
+		) < ?
+		ORDER BY images.id
+		LIMIT ?
+	`
+	query, args, err := sqlx.In(query, afterID, names, len(names), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.queryImages(query, args)
+}
+
+func (qb *imageQueryBuilder) FindSimilar(phash int64, distance int, performerID *uuid.UUID, studioID *uuid.UUID, limit int) (models.Images, error) {
+	query := `SELECT images.* FROM images`
+	var args []interface{}
+
+	if performerID != nil {
+		query += ` JOIN performer_images ON performer_images.image_id = images.id AND performer_images.performer_id = ?`
+		args = append(args, *performerID)
+	}
+	if studioID != nil {
+		query += ` JOIN studio_images ON studio_images.image_id = images.id AND studio_images.studio_id = ?`
+		args = append(args, *studioID)
+	}
+
+	// distance matching requires the bktree extension. The closest images
+	// are kept when there are more matches than the limit.
+	if distance > 0 {
+		query += ` WHERE images.phash <@ (?::BIGINT, ?) ORDER BY images.phash <-> ?::BIGINT, images.id`
+		args = append(args, phash, distance, phash)
+	} else {
+		query += ` WHERE images.phash = ? ORDER BY images.id`
+		args = append(args, phash)
+	}
+
+	query += ` LIMIT ?`
+	args = append(args, limit)
+
+	return qb.queryImages(query, args)
+}
+
+func (qb *imageQueryBuilder) FindMissingPHashes(afterID uuid.UUID, limit int) ([]*models.Image, error) {
+	query := `
+		SELECT images.* FROM images
+		WHERE images.id > ? AND images.checksum <> '' AND images.width > 0 AND images.phash IS NULL
+		ORDER BY images.id
+		LIMIT ?
+	`
+	return qb.queryImages(query, []interface{}{afterID, limit})
+}
+
+func (qb *imageQueryBuilder) FindURLOnly(afterID uuid.UUID, limit int) ([]*models.Image, error) {
+	query := `
+		SELECT images.* FROM images
+		WHERE images.id > ? AND images.checksum = '' AND images.url IS NOT NULL
+		ORDER BY images.id
+		LIMIT ?
+	`
+	return qb.queryImages(query, []interface{}{afterID, limit})
+}
+
+func (qb *imageQueryBuilder) FindStored(afterID uuid.UUID, limit int) ([]*models.Image, error) {
+	query := `
+		SELECT images.* FROM images
+		WHERE images.id > ? AND images.checksum <> ''
+		ORDER BY images.id
+		LIMIT ?
+	`
+	return qb.queryImages(query, []interface{}{afterID, limit})
+}
@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var jobRunDBTable = newTable("job_runs", func() interface{} {
+	return &models.JobRun{}
+})
+
+type jobQueryBuilder struct {
+	dbi *dbi
+}
+
+func newJobQueryBuilder(txn *txnState) models.JobRepo {
+	return &jobQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *jobQueryBuilder) toModel(ro interface{}) *models.JobRun {
+	if ro != nil {
+		return ro.(*models.JobRun)
+	}
+
+	return nil
+}
+
+func (qb *jobQueryBuilder) CreateRun(newRun models.JobRun) (*models.JobRun, error) {
+	ret, err := qb.dbi.Insert(jobRunDBTable, newRun)
+	return qb.toModel(ret), err
+}
+
+func (qb *jobQueryBuilder) UpdateRun(updatedRun models.JobRun) (*models.JobRun, error) {
+	ret, err := qb.dbi.Update(jobRunDBTable, updatedRun, true)
+	return qb.toModel(ret), err
+}
+
+func (qb *jobQueryBuilder) FindRun(id uuid.UUID) (*models.JobRun, error) {
+	ret, err := qb.dbi.Find(id, jobRunDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *jobQueryBuilder) FindRuns(job *string, limit int) (models.JobRuns, error) {
+	query := `SELECT * FROM job_runs`
+	var args []interface{}
+	if job != nil {
+		query += ` WHERE job = ?`
+		args = append(args, *job)
+	}
+	query += ` ORDER BY started_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	var output models.JobRuns
+	err := qb.dbi.RawQuery(jobRunDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *jobQueryBuilder) FindLastRun(job string) (*models.JobRun, error) {
+	runs, err := qb.FindRuns(&job, 1)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return runs[0], nil
+}
+
+func (qb *jobQueryBuilder) FailRunning(reason string) error {
+	query := `UPDATE job_runs SET status = ?, error = ?, finished_at = ? WHERE status = ?`
+	args := []interface{}{
+		models.JobStatusEnumFailed.String(),
+		reason,
+		models.SQLiteTimestamp{Timestamp: time.Now()},
+		models.JobStatusEnumRunning.String(),
+	}
+	return qb.dbi.RawExec(query, args)
+}
+
+func (qb *jobQueryBuilder) PruneRuns(job string, keep int) error {
+	query := `
+		DELETE FROM job_runs WHERE job = ? AND id NOT IN (
+			SELECT id FROM job_runs WHERE job = ? ORDER BY started_at DESC LIMIT ?
+		)
+	`
+	return qb.dbi.RawExec(query, []interface{}{job, job, keep})
+}
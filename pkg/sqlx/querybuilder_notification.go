@@ -0,0 +1,91 @@
+package sqlx
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var notificationDBTable = newTable("notifications", func() interface{} {
+	return &models.Notification{}
+})
+
+type notificationQueryBuilder struct {
+	dbi *dbi
+}
+
+func newNotificationQueryBuilder(txn *txnState) models.NotificationRepo {
+	return &notificationQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *notificationQueryBuilder) toModel(ro interface{}) *models.Notification {
+	if ro != nil {
+		return ro.(*models.Notification)
+	}
+
+	return nil
+}
+
+func (qb *notificationQueryBuilder) Create(newNotification models.Notification) (*models.Notification, error) {
+	ret, err := qb.dbi.Insert(notificationDBTable, newNotification)
+	return qb.toModel(ret), err
+}
+
+func (qb *notificationQueryBuilder) FindByUser(userID uuid.UUID, unreadOnly bool, limit int) (models.Notifications, error) {
+	query := `SELECT * FROM notifications WHERE user_id = ?`
+	if unreadOnly {
+		query += ` AND NOT read`
+	}
+	query += ` ORDER BY created_at DESC LIMIT ?`
+	args := []interface{}{userID, limit}
+
+	var output models.Notifications
+	err := qb.dbi.RawQuery(notificationDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *notificationQueryBuilder) CountUnread(userID uuid.UUID) (int, error) {
+	var count int
+	err := qb.dbi.queryFunc(`SELECT COUNT(*) FROM notifications WHERE user_id = ? AND NOT read`, []interface{}{userID}, func(rows *sqlx.Rows) error {
+		return rows.Scan(&count)
+	})
+	return count, err
+}
+
+func (qb *notificationQueryBuilder) MarkRead(userID uuid.UUID, ids []uuid.UUID) error {
+	if ids == nil {
+		query := `UPDATE notifications SET read = TRUE WHERE user_id = ? AND NOT read`
+		return qb.dbi.RawExec(query, []interface{}{userID})
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`UPDATE notifications SET read = TRUE WHERE user_id = ? AND id IN (?)`, userID, ids)
+	if err != nil {
+		return err
+	}
+	return qb.dbi.RawExec(query, args)
+}
+
+func (qb *notificationQueryBuilder) FindUnemailed() (models.Notifications, error) {
+	query := `SELECT * FROM notifications WHERE NOT read AND NOT emailed ORDER BY user_id, created_at`
+
+	var output models.Notifications
+	err := qb.dbi.RawQuery(notificationDBTable, query, nil, &output)
+	return output, err
+}
+
+func (qb *notificationQueryBuilder) MarkEmailed(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`UPDATE notifications SET emailed = TRUE WHERE id IN (?)`, ids)
+	if err != nil {
+		return err
+	}
+	return qb.dbi.RawExec(query, args)
+}
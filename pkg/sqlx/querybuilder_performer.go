@@ -3,6 +3,7 @@ package sqlx
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -38,6 +39,10 @@ var (
 		return &models.PerformerBodyMod{}
 	})
 
+	performerRelationTable = newTableJoin(performerTable, "performer_relationships", performerJoinKey, func() interface{} {
+		return &models.PerformerRelation{}
+	})
+
 	performerSourceRedirectTable = newTableJoin(performerTable, "performer_redirects", "source_id", func() interface{} {
 		return &models.Redirect{}
 	})
@@ -202,13 +207,54 @@ func (qb *performerQueryBuilder) Count() (int, error) {
 }
 
 func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterType, findFilter *models.QuerySpec) ([]*models.Performer, int) {
-	if performerFilter == nil {
-		performerFilter = &models.PerformerFilterType{}
-	}
 	if findFilter == nil {
 		findFilter = &models.QuerySpec{}
 	}
 
+	query := qb.buildQuery(performerFilter)
+
+	if findFilter.GetSort("") == "debut" {
+		query.Body += `
+			JOIN (SELECT performer_id, MIN(date) as debut FROM scene_performers JOIN scenes ON scene_id = id GROUP BY performer_id) D
+			ON performers.id = D.performer_id
+		`
+		direction := findFilter.GetDirection() + qb.dbi.txn.dialect.NullsLast()
+		query.SortAndPagination = "ORDER BY debut " + direction + ", name " + direction + getPagination(findFilter)
+	} else if findFilter.GetSort("") == "scene_count" {
+		query.Body += `
+			JOIN (SELECT performer_id, COUNT(*) as scene_count FROM scene_performers GROUP BY performer_id) D
+			ON performers.id = D.performer_id
+		`
+		direction := findFilter.GetDirection() + qb.dbi.txn.dialect.NullsLast()
+		query.SortAndPagination = " ORDER BY scene_count " + direction + ", name " + direction + getPagination(findFilter)
+	} else {
+		query.SortAndPagination = qb.getPerformerSort(findFilter) + getPagination(findFilter)
+	}
+
+	var performers models.Performers
+	countResult, err := qb.dbi.Query(*query, &performers)
+
+	if err != nil {
+		// TODO
+		panic(err)
+	}
+
+	return performers, countResult
+}
+
+func (qb *performerQueryBuilder) QueryPage(performerFilter *models.PerformerFilterType, findFilter *models.CursorQuerySpec) ([]*models.Performer, *models.Page, error) {
+	query := qb.buildQuery(performerFilter)
+
+	var performers models.Performers
+	page, err := qb.dbi.queryPage(query, findFilter, "name", []string{"name", "birthdate", "created_at", "updated_at"}, &performers)
+	return performers, page, err
+}
+
+func (qb *performerQueryBuilder) buildQuery(performerFilter *models.PerformerFilterType) *queryBuilder {
+	if performerFilter == nil {
+		performerFilter = &models.PerformerFilterType{}
+	}
+
 	query := newQueryBuilder(performerDBTable)
 	query.Eq("deleted", false)
 
@@ -247,6 +293,12 @@ func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterTy
 		}
 	}
 
+	if q := performerFilter.Relationship; q != nil {
+		clause, thisArgs := getRelationshipFilterClause(q)
+		query.AddWhere(clause)
+		query.AddArg(thisArgs...)
+	}
+
 	handleStringCriterion("country", performerFilter.Country, query)
 	//handleStringCriterion("eye_color", performerFilter.EyeColor, &query)
 	//handleStringCriterion("height", performerFilter.Height, &query)
@@ -257,33 +309,49 @@ func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterTy
 	//handleStringCriterion("piercings", performerFilter.Piercings, &query)
 	//handleStringCriterion("aliases", performerFilter.Aliases, &query)
 
-	if findFilter != nil && findFilter.GetSort("") == "debut" {
-		query.Body += `
-			JOIN (SELECT performer_id, MIN(date) as debut FROM scene_performers JOIN scenes ON scene_id = id GROUP BY performer_id) D
-			ON performers.id = D.performer_id
-		`
-		direction := findFilter.GetDirection() + qb.dbi.txn.dialect.NullsLast()
-		query.SortAndPagination = "ORDER BY debut " + direction + ", name " + direction + getPagination(findFilter)
-	} else if findFilter != nil && findFilter.GetSort("") == "scene_count" {
-		query.Body += `
-			JOIN (SELECT performer_id, COUNT(*) as scene_count FROM scene_performers GROUP BY performer_id) D
-			ON performers.id = D.performer_id
-		`
-		direction := findFilter.GetDirection() + qb.dbi.txn.dialect.NullsLast()
-		query.SortAndPagination = " ORDER BY scene_count " + direction + ", name " + direction + getPagination(findFilter)
-	} else {
-		query.SortAndPagination = qb.getPerformerSort(findFilter) + getPagination(findFilter)
+	return query
+}
+
+// getRelationshipFilterClause matches performers with a relationship of
+// the criterion type, to the criterion performer if set.
+func getRelationshipFilterClause(criterion *models.PerformerRelationshipCriterionInput) (string, []interface{}) {
+	var outgoing, incoming []string
+	if criterion.Type != nil {
+		outgoing, incoming = criterion.Type.StoredTypes()
 	}
 
-	var performers models.Performers
-	countResult, err := qb.dbi.Query(*query, &performers)
+	var related *uuid.UUID
+	if criterion.PerformerID != nil {
+		id, _ := uuid.FromString(*criterion.PerformerID)
+		related = &id
+	}
 
-	if err != nil {
-		// TODO
-		panic(err)
+	var clauses []string
+	var args []interface{}
+	addClause := func(column string, relatedColumn string, types []string) {
+		clause := "R." + column + " = performers.id"
+		if criterion.Type != nil {
+			clause += " AND R.type IN (?" + strings.Repeat(", ?", len(types)-1) + ")"
+			for _, t := range types {
+				args = append(args, t)
+			}
+		}
+		if related != nil {
+			clause += " AND R." + relatedColumn + " = ?"
+			args = append(args, *related)
+		}
+		clauses = append(clauses, "("+clause+")")
 	}
 
-	return performers, countResult
+	if criterion.Type == nil || len(outgoing) > 0 {
+		addClause("performer_id", "related_performer_id", outgoing)
+	}
+	if criterion.Type == nil || len(incoming) > 0 {
+		addClause("related_performer_id", "performer_id", incoming)
+	}
+
+	clause := "EXISTS (SELECT 1 FROM performer_relationships R WHERE " + strings.Join(clauses, " OR ") + ")"
+	return clause, args
 }
 
 func getBirthYearFilterClause(criterionModifier models.CriterionModifier, value int) ([]string, []interface{}) {
@@ -521,6 +589,91 @@ func (qb *performerQueryBuilder) GetAllPiercings(ids []uuid.UUID) ([][]*models.B
 	return result, nil
 }
 
+func (qb *performerQueryBuilder) GetRelations(id uuid.UUID) (models.PerformerRelations, error) {
+	query := selectStatement(performerRelationTable.table) + " WHERE performer_id = ? OR related_performer_id = ?"
+	args := []interface{}{id, id}
+
+	joins := models.PerformerRelations{}
+	err := qb.dbi.RawQuery(performerRelationTable.table, query, args, &joins)
+	return joins, err
+}
+
+func (qb *performerQueryBuilder) GetAllRelations(ids []uuid.UUID) ([][]*models.PerformerRelation, []error) {
+	query := selectStatement(performerRelationTable.table) + " WHERE performer_id IN (?) OR related_performer_id IN (?)"
+	query, args, _ := sqlx.In(query, ids, ids)
+
+	joins := models.PerformerRelations{}
+	if err := qb.dbi.RawQuery(performerRelationTable.table, query, args, &joins); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]*models.PerformerRelation)
+	for _, join := range joins {
+		m[join.PerformerID] = append(m[join.PerformerID], join)
+		m[join.RelatedPerformerID] = append(m[join.RelatedPerformerID], join)
+	}
+
+	result := make([][]*models.PerformerRelation, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *performerQueryBuilder) updateRelations(performerID uuid.UUID, added models.PerformerRelations, removed models.PerformerRelations) error {
+	current, err := qb.GetRelations(performerID)
+	if err != nil {
+		return err
+	}
+
+	// validate the changes against the current relationships
+	if err := models.ProcessSlice(&current, &added, &removed); err != nil {
+		return err
+	}
+
+	for _, r := range removed {
+		query := `DELETE FROM performer_relationships WHERE performer_id = ? AND related_performer_id = ? AND type = ?`
+		args := []interface{}{r.PerformerID, r.RelatedPerformerID, r.Type}
+		if err := qb.dbi.RawQuery(performerRelationTable.table, query, args, nil); err != nil {
+			return err
+		}
+	}
+
+	return qb.dbi.InsertJoins(performerRelationTable, &added)
+}
+
+func (qb *performerQueryBuilder) DeleteRelations(id uuid.UUID) error {
+	query := `DELETE FROM performer_relationships WHERE performer_id = ? OR related_performer_id = ?`
+	args := []interface{}{id, id}
+	return qb.dbi.RawQuery(performerRelationTable.table, query, args, nil)
+}
+
+// UpdateRelations moves the relationships of the merge source to the target.
+// Relationships between the two are dropped.
+func (qb *performerQueryBuilder) UpdateRelations(sourceID uuid.UUID, targetID uuid.UUID) error {
+	query := `INSERT INTO performer_relationships (performer_id, related_performer_id, type)
+						SELECT
+							CASE WHEN type = 'GROUP_MEMBER' THEN A ELSE LEAST(A, B) END,
+							CASE WHEN type = 'GROUP_MEMBER' THEN B ELSE GREATEST(A, B) END,
+							type
+						FROM (
+							SELECT
+								CASE WHEN performer_id = ? THEN ?::UUID ELSE performer_id END AS A,
+								CASE WHEN related_performer_id = ? THEN ?::UUID ELSE related_performer_id END AS B,
+								type
+							FROM performer_relationships
+							WHERE performer_id = ? OR related_performer_id = ?
+						) R
+						WHERE A <> B
+						ON CONFLICT DO NOTHING`
+	args := []interface{}{sourceID, targetID, sourceID, targetID, sourceID, sourceID}
+	if err := qb.dbi.RawQuery(performerRelationTable.table, query, args, nil); err != nil {
+		return err
+	}
+
+	return qb.DeleteRelations(sourceID)
+}
+
 func (qb *performerQueryBuilder) SearchPerformers(term string, limit int) (models.Performers, error) {
 	query := `
         SELECT * FROM performers
@@ -535,10 +688,26 @@ func (qb *performerQueryBuilder) SearchPerformers(term string, limit int) (model
 
 func (qb *performerQueryBuilder) DeleteScenePerformers(id uuid.UUID) error {
 	// Delete scene_performers joins
-	return qb.dbi.DeleteJoins(performerSceneTable, id)
+	if err := qb.dbi.DeleteJoins(performerSceneTable, id); err != nil {
+		return err
+	}
+
+	// Delete scene marker performer joins
+	query := `DELETE FROM scene_marker_performers WHERE performer_id = ?`
+	args := []interface{}{id}
+	return qb.dbi.RawQuery(sceneMarkerPerformerTable.table, query, args, nil)
 }
 
 func (qb *performerQueryBuilder) SoftDelete(performer models.Performer) (*models.Performer, error) {
+	ret, err := qb.softDelete(performer)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, logChange(qb.dbi.txn, models.TargetTypeEnumPerformer, models.OperationEnumDestroy, performer.ID, nil)
+}
+
+func (qb *performerQueryBuilder) softDelete(performer models.Performer) (*models.Performer, error) {
 	// Delete joins
 	if err := qb.dbi.DeleteJoins(performerAliasTable, performer.ID); err != nil {
 		return nil, err
@@ -592,7 +761,23 @@ func (qb *performerQueryBuilder) UpdateScenePerformers(oldPerformer *models.Perf
 	// Delete any remaining joins with the old performer
 	query = `DELETE FROM scene_performers WHERE performer_id = ?`
 	args = []interface{}{oldPerformer.ID}
-	return qb.dbi.RawQuery(scenePerformerTable.table, query, args, nil)
+	if err := qb.dbi.RawQuery(scenePerformerTable.table, query, args, nil); err != nil {
+		return err
+	}
+
+	// Reassign scene markers to the new performer in the same way
+	query = `UPDATE scene_marker_performers
+					 SET performer_id = ?
+					 WHERE performer_id = ?
+					 AND marker_id NOT IN (SELECT marker_id from scene_marker_performers WHERE performer_id = ?)`
+	args = []interface{}{newTargetID, oldPerformer.ID, newTargetID}
+	if err := qb.dbi.RawQuery(sceneMarkerPerformerTable.table, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_marker_performers WHERE performer_id = ?`
+	args = []interface{}{oldPerformer.ID}
+	return qb.dbi.RawQuery(sceneMarkerPerformerTable.table, query, args, nil)
 }
 
 func (qb *performerQueryBuilder) UpdateScenePerformerAlias(performerID uuid.UUID, name string) error {
@@ -619,16 +804,22 @@ func (qb *performerQueryBuilder) mergeInto(sourceID uuid.UUID, targetID uuid.UUI
 	if performer.Deleted {
 		return errors.New("Merge source performer is deleted: " + sourceID.String())
 	}
-	_, err = qb.SoftDelete(*performer)
+	_, err = qb.softDelete(*performer)
 	if err != nil {
 		return err
 	}
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumPerformer, models.OperationEnumMerge, sourceID, &targetID); err != nil {
+		return err
+	}
 	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
 		return err
 	}
 	if err := qb.UpdateScenePerformers(performer, targetID, setAliases); err != nil {
 		return err
 	}
+	if err := qb.UpdateRelations(sourceID, targetID); err != nil {
+		return err
+	}
 	redirect := models.Redirect{SourceID: sourceID, TargetID: targetID}
 	return qb.CreateRedirect(redirect)
 }
@@ -661,6 +852,10 @@ func (qb *performerQueryBuilder) ApplyEdit(edit models.Edit, operation models.Op
 			return nil, err
 		}
 
+		if err := logChange(qb.dbi.txn, models.TargetTypeEnumPerformer, models.OperationEnumCreate, UUID, nil); err != nil {
+			return nil, err
+		}
+
 		if len(data.New.AddedAliases) > 0 {
 			aliases := models.CreatePerformerAliases(UUID, data.New.AddedAliases)
 			if err := qb.CreateAliases(aliases); err != nil {
@@ -696,13 +891,23 @@ func (qb *performerQueryBuilder) ApplyEdit(edit models.Edit, operation models.Op
 			}
 		}
 
+		if len(data.New.AddedRelationships) > 0 {
+			relations := models.CreatePerformerRelations(UUID, data.New.AddedRelationships)
+			if err := qb.dbi.InsertJoins(performerRelationTable, &relations); err != nil {
+				return nil, err
+			}
+		}
+
 		return performer, nil
 	case models.OperationEnumDestroy:
 		updatedPerformer, err := qb.SoftDelete(*performer)
 		if err != nil {
 			return nil, err
 		}
-		err = qb.DeleteScenePerformers(performer.ID)
+		if err := qb.DeleteScenePerformers(performer.ID); err != nil {
+			return nil, err
+		}
+		err = qb.DeleteRelations(performer.ID)
 
 		// TODO: Delete images
 
@@ -739,6 +944,10 @@ func (qb *performerQueryBuilder) applyModifyEdit(performer *models.Performer, da
 		return nil, err
 	}
 
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumPerformer, models.OperationEnumModify, updatedPerformer.ID, nil); err != nil {
+		return nil, err
+	}
+
 	currentAliases, err := qb.GetAliases(updatedPerformer.ID)
 	if err != nil {
 		return nil, err
@@ -811,6 +1020,12 @@ func (qb *performerQueryBuilder) applyModifyEdit(performer *models.Performer, da
 		return nil, err
 	}
 
+	newRelations := models.CreatePerformerRelations(updatedPerformer.ID, data.New.AddedRelationships)
+	oldRelations := models.CreatePerformerRelations(updatedPerformer.ID, data.New.RemovedRelationships)
+	if err := qb.updateRelations(updatedPerformer.ID, newRelations, oldRelations); err != nil {
+		return nil, err
+	}
+
 	if data.New.Name != nil && data.SetModifyAliases {
 		if err = qb.UpdateScenePerformerAlias(updatedPerformer.ID, *data.Old.Name); err != nil {
 			return nil, err
@@ -0,0 +1,69 @@
+package sqlx
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var revisionDBTable = newTable("revisions", func() interface{} {
+	return &models.Revision{}
+})
+
+type revisionQueryBuilder struct {
+	dbi *dbi
+}
+
+func newRevisionQueryBuilder(txn *txnState) models.RevisionRepo {
+	return &revisionQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *revisionQueryBuilder) toModel(ro interface{}) *models.Revision {
+	if ro != nil {
+		return ro.(*models.Revision)
+	}
+
+	return nil
+}
+
+func (qb *revisionQueryBuilder) Create(newRevision models.Revision) (*models.Revision, error) {
+	ret, err := qb.dbi.Insert(revisionDBTable, newRevision)
+	return qb.toModel(ret), err
+}
+
+func (qb *revisionQueryBuilder) Find(id uuid.UUID) (*models.Revision, error) {
+	ret, err := qb.dbi.Find(id, revisionDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *revisionQueryBuilder) FindByTarget(targetType models.TargetTypeEnum, targetID uuid.UUID) (models.Revisions, error) {
+	query := `SELECT * FROM revisions WHERE target_type = ? AND target_id = ? ORDER BY created_at DESC`
+	args := []interface{}{targetType.String(), targetID}
+
+	var output models.Revisions
+	err := qb.dbi.RawQuery(revisionDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *revisionQueryBuilder) FindAt(targetType models.TargetTypeEnum, targetID uuid.UUID, at time.Time) (*models.Revision, error) {
+	query := `
+		SELECT * FROM revisions
+		WHERE target_type = ? AND target_id = ? AND created_at <= ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	args := []interface{}{targetType.String(), targetID, models.SQLiteTimestamp{Timestamp: at}}
+
+	var output models.Revisions
+	if err := qb.dbi.RawQuery(revisionDBTable, query, args, &output); err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+	return output[0], nil
+}
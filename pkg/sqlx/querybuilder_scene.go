@@ -30,6 +30,14 @@ var (
 		return &models.SceneURL{}
 	})
 
+	sceneMarkerTable = newTableJoin(sceneTable, "scene_markers", sceneJoinKey, func() interface{} {
+		return &models.SceneMarker{}
+	})
+
+	sceneMarkerPerformerTable = newTableJoin("scene_markers", "scene_marker_performers", "marker_id", func() interface{} {
+		return &models.SceneMarkerPerformer{}
+	})
+
 	sceneRedirectTable = newTableJoin(sceneTable, "scene_redirects", "source_id", func() interface{} {
 		return &models.Redirect{}
 	})
@@ -104,6 +112,26 @@ func (qb *sceneQueryBuilder) Find(id uuid.UUID) (*models.Scene, error) {
 	return qb.toModel(ret), err
 }
 
+func (qb *sceneQueryBuilder) FindByIds(ids []uuid.UUID) ([]*models.Scene, []error) {
+	query := "SELECT scenes.* FROM scenes WHERE id IN (?)"
+	query, args, _ := sqlx.In(query, ids)
+	scenes, err := qb.queryScenes(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*models.Scene)
+	for _, scene := range scenes {
+		m[scene.ID] = scene
+	}
+
+	result := make([]*models.Scene, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
 func (qb *sceneQueryBuilder) FindByFingerprint(algorithm models.FingerprintAlgorithm, hash string) ([]*models.Scene, error) {
 	query := `
 		SELECT scenes.* FROM scenes
@@ -189,6 +217,60 @@ func (qb *sceneQueryBuilder) FindByFullFingerprints(fingerprints []*models.Finge
 	return qb.queryScenes(query, args)
 }
 
+func (qb *sceneQueryBuilder) FindDuplicatePairs(distance int, studioID *uuid.UUID) ([]*models.SceneDuplicatePair, error) {
+	studioClause := ""
+	if studioID != nil {
+		studioClause = "AND sa.studio_id = :studioid AND sb.studio_id = :studioid"
+	}
+
+	// equal hashes are matched using the hash index
+	query := `
+		SELECT a.scene_id, b.scene_id AS duplicate_id
+		FROM scene_fingerprints a
+		JOIN scene_fingerprints b ON b.algorithm = a.algorithm AND b.hash = a.hash AND b.scene_id > a.scene_id
+		JOIN scenes sa ON sa.id = a.scene_id
+		JOIN scenes sb ON sb.id = b.scene_id
+		WHERE sa.deleted = FALSE AND sb.deleted = FALSE ` + studioClause
+
+	// phashes within the distance are looked up in the phash index
+	if distance > 0 {
+		query += `
+		UNION
+		SELECT a.scene_id, b.scene_id AS duplicate_id
+		FROM scene_fingerprints a
+		JOIN scene_fingerprints b ON b.algorithm = 'PHASH'
+			AND ('x' || b.hash)::::bit(64)::::bigint <@ (('x' || a.hash)::::bit(64)::::bigint, :distance)
+			AND b.scene_id > a.scene_id
+		JOIN scenes sa ON sa.id = a.scene_id
+		JOIN scenes sb ON sb.id = b.scene_id
+		WHERE a.algorithm = 'PHASH' AND sa.deleted = FALSE AND sb.deleted = FALSE ` + studioClause
+	} else {
+		query = `SELECT DISTINCT * FROM (` + query + `) pairs`
+	}
+
+	arg := map[string]interface{}{
+		"studioid": studioID,
+		"distance": distance,
+	}
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.SceneDuplicatePair
+	err = qb.dbi.queryFunc(query, args, func(rows *sqlx.Rows) error {
+		var pair models.SceneDuplicatePair
+		if err := rows.StructScan(&pair); err != nil {
+			return err
+		}
+
+		ret = append(ret, &pair)
+		return nil
+	})
+
+	return ret, err
+}
+
 // func (qb *SceneQueryBuilder) FindByStudioID(sceneID int) ([]*models.Scene, error) {
 // 	query := `
 // 		SELECT scenes.* FROM scenes
@@ -240,13 +322,37 @@ func (qb *sceneQueryBuilder) Count() (int, error) {
 }
 
 func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilter *models.QuerySpec) ([]*models.Scene, int) {
-	if sceneFilter == nil {
-		sceneFilter = &models.SceneFilterType{}
-	}
 	if findFilter == nil {
 		findFilter = &models.QuerySpec{}
 	}
 
+	query := qb.buildQuery(sceneFilter)
+	query.SortAndPagination = qb.getSceneSort(findFilter) + getPagination(findFilter)
+
+	var scenes models.Scenes
+	countResult, err := qb.dbi.Query(*query, &scenes)
+
+	if err != nil {
+		// TODO
+		panic(err)
+	}
+
+	return scenes, countResult
+}
+
+func (qb *sceneQueryBuilder) QueryPage(sceneFilter *models.SceneFilterType, findFilter *models.CursorQuerySpec) ([]*models.Scene, *models.Page, error) {
+	query := qb.buildQuery(sceneFilter)
+
+	var scenes models.Scenes
+	page, err := qb.dbi.queryPage(query, findFilter, "date", []string{"date", "title", "created_at", "updated_at"}, &scenes)
+	return scenes, page, err
+}
+
+func (qb *sceneQueryBuilder) buildQuery(sceneFilter *models.SceneFilterType) *queryBuilder {
+	if sceneFilter == nil {
+		sceneFilter = &models.SceneFilterType{}
+	}
+
 	query := newQueryBuilder(sceneDBTable)
 	query.Eq("scenes.deleted", false)
 
@@ -337,17 +443,7 @@ func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilt
 
 	// TODO - other filters
 
-	query.SortAndPagination = qb.getSceneSort(findFilter) + getPagination(findFilter)
-
-	var scenes models.Scenes
-	countResult, err := qb.dbi.Query(*query, &scenes)
-
-	if err != nil {
-		// TODO
-		panic(err)
-	}
-
-	return scenes, countResult
+	return query
 }
 
 func getMultiCriterionClause(joinTable tableJoin, joinTableField string, criterion *models.MultiIDCriterionInput) (string, string) {
@@ -565,6 +661,71 @@ func (qb *sceneQueryBuilder) GetAllURLs(ids []uuid.UUID) ([][]*models.URL, []err
 	return result, nil
 }
 
+func (qb *sceneQueryBuilder) GetMarkers(id uuid.UUID) (models.SceneMarkers, error) {
+	query := selectStatement(sceneMarkerTable.table) + " WHERE scene_id = ? ORDER BY start_seconds, end_seconds NULLS FIRST"
+	args := []interface{}{id}
+
+	markers := models.SceneMarkers{}
+	err := qb.dbi.RawQuery(sceneMarkerTable.table, query, args, &markers)
+	return markers, err
+}
+
+func (qb *sceneQueryBuilder) GetAllMarkers(ids []uuid.UUID) ([][]*models.SceneMarker, []error) {
+	query := selectStatement(sceneMarkerTable.table) + " WHERE scene_id IN (?) ORDER BY start_seconds, end_seconds NULLS FIRST"
+	query, args, _ := sqlx.In(query, ids)
+
+	markers := models.SceneMarkers{}
+	if err := qb.dbi.RawQuery(sceneMarkerTable.table, query, args, &markers); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID][]*models.SceneMarker)
+	for _, marker := range markers {
+		m[marker.SceneID] = append(m[marker.SceneID], marker)
+	}
+
+	result := make([][]*models.SceneMarker, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *sceneQueryBuilder) GetMarkerPerformers(id uuid.UUID) (models.SceneMarkerPerformers, error) {
+	query := `
+		SELECT scene_marker_performers.* FROM scene_marker_performers
+		JOIN scene_markers ON scene_markers.id = scene_marker_performers.marker_id
+		WHERE scene_markers.scene_id = ?`
+	args := []interface{}{id}
+
+	joins := models.SceneMarkerPerformers{}
+	err := qb.dbi.RawQuery(sceneMarkerPerformerTable.table, query, args, &joins)
+	return joins, err
+}
+
+func (qb *sceneQueryBuilder) GetAllMarkerPerformerIDs(markerIDs []uuid.UUID) ([][]uuid.UUID, []error) {
+	joins := models.SceneMarkerPerformers{}
+	if err := qb.dbi.FindAllJoins(sceneMarkerPerformerTable, markerIDs, &joins); err != nil {
+		return nil, utils.DuplicateError(err, len(markerIDs))
+	}
+
+	m := make(map[uuid.UUID][]uuid.UUID)
+	for _, join := range joins {
+		m[join.MarkerID] = append(m[join.MarkerID], join.PerformerID)
+	}
+
+	result := make([][]uuid.UUID, len(markerIDs))
+	for i, id := range markerIDs {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
+func (qb *sceneQueryBuilder) DeleteMarkers(id uuid.UUID) error {
+	// marker performers are deleted by cascade
+	return qb.dbi.DeleteJoins(sceneMarkerTable, id)
+}
+
 func (qb *sceneQueryBuilder) SearchScenes(term string, limit int) ([]*models.Scene, error) {
 	query := `
         SELECT S.* FROM scenes S
@@ -589,6 +750,15 @@ func (qb *sceneQueryBuilder) CountByPerformer(id uuid.UUID) (int, error) {
 }
 
 func (qb *sceneQueryBuilder) SoftDelete(scene models.Scene) (*models.Scene, error) {
+	ret, err := qb.softDelete(scene)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, logChange(qb.dbi.txn, models.TargetTypeEnumScene, models.OperationEnumDestroy, scene.ID, nil)
+}
+
+func (qb *sceneQueryBuilder) softDelete(scene models.Scene) (*models.Scene, error) {
 	// Delete joins
 	if err := qb.dbi.DeleteJoins(sceneFingerprintTable, scene.ID); err != nil {
 		return nil, err
@@ -605,6 +775,9 @@ func (qb *sceneQueryBuilder) SoftDelete(scene models.Scene) (*models.Scene, erro
 	if err := qb.dbi.DeleteJoins(sceneTagTable, scene.ID); err != nil {
 		return nil, err
 	}
+	if err := qb.DeleteMarkers(scene.ID); err != nil {
+		return nil, err
+	}
 
 	ret, err := qb.dbi.SoftDelete(sceneDBTable, scene)
 	return qb.toModel(ret), err
@@ -650,6 +823,14 @@ func (qb *sceneQueryBuilder) ApplyEdit(scene *models.Scene, create bool, data *m
 		return nil, err
 	}
 
+	operation := models.OperationEnumModify
+	if create {
+		operation = models.OperationEnumCreate
+	}
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumScene, operation, updatedScene.ID, nil); err != nil {
+		return nil, err
+	}
+
 	if err := qb.updateURLsFromEdit(scene, data); err != nil {
 		return nil, err
 	}
@@ -666,6 +847,10 @@ func (qb *sceneQueryBuilder) ApplyEdit(scene *models.Scene, create bool, data *m
 		return nil, err
 	}
 
+	if err := qb.updateMarkersFromEdit(scene, data); err != nil {
+		return nil, err
+	}
+
 	return updatedScene, err
 }
 
@@ -729,6 +914,64 @@ func (qb *sceneQueryBuilder) updatePerformersFromEdit(scene *models.Scene, data
 	return qb.UpdatePerformers(scene.ID, currentPerformers)
 }
 
+// updateMarkersFromEdit deletes the removed markers and creates the added
+// ones. Markers are matched by value, so unchanged markers keep their ids.
+func (qb *sceneQueryBuilder) updateMarkersFromEdit(scene *models.Scene, data *models.SceneEditData) error {
+	if len(data.New.AddedMarkers) == 0 && len(data.New.RemovedMarkers) == 0 {
+		return nil
+	}
+
+	markers, err := qb.GetMarkers(scene.ID)
+	if err != nil {
+		return err
+	}
+	performers, err := qb.GetMarkerPerformers(scene.ID)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string][]uuid.UUID)
+	for _, marker := range markers {
+		key := marker.ToInput(performers).Key()
+		current[key] = append(current[key], marker.ID)
+	}
+
+	for _, removed := range data.New.RemovedMarkers {
+		key := removed.Key()
+		ids := current[key]
+		if len(ids) == 0 {
+			return fmt.Errorf("invalid removal, marker does not exist: '%s'", key)
+		}
+
+		if err := qb.dbi.Delete(ids[0], sceneMarkerTable.table); err != nil {
+			return err
+		}
+		current[key] = ids[1:]
+	}
+
+	for _, added := range data.New.AddedMarkers {
+		key := added.Key()
+		if len(current[key]) > 0 {
+			return fmt.Errorf("invalid addition, marker already exists: '%s'", key)
+		}
+
+		marker, markerPerformers, err := models.CreateSceneMarker(scene.ID, *added)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.dbi.InsertJoin(sceneMarkerTable, *marker, nil); err != nil {
+			return err
+		}
+		if err := qb.dbi.InsertJoins(sceneMarkerPerformerTable, &markerPerformers); err != nil {
+			return err
+		}
+		current[key] = append(current[key], marker.ID)
+	}
+
+	return nil
+}
+
 func (qb *sceneQueryBuilder) MergeInto(source *models.Scene, target *models.Scene) error {
 	if source.Deleted {
 		return fmt.Errorf("merge source scene is deleted: %s", source.ID.String())
@@ -737,7 +980,11 @@ func (qb *sceneQueryBuilder) MergeInto(source *models.Scene, target *models.Scen
 		return fmt.Errorf("merge target scene is deleted: %s", target.ID.String())
 	}
 
-	if _, err := qb.SoftDelete(*source); err != nil {
+	if _, err := qb.softDelete(*source); err != nil {
+		return err
+	}
+
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumScene, models.OperationEnumMerge, source.ID, &target.ID); err != nil {
 		return err
 	}
 
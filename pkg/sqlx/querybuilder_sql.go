@@ -32,6 +32,27 @@ func handleStringCriterion(column string, value *models.StringCriterionInput, qu
 	}
 }
 
+func handleIntCriterion(column string, value *models.IntCriterionInput, query *queryBuilder) {
+	if value != nil {
+		switch value.Modifier {
+		case models.CriterionModifierEquals:
+			query.Eq(column, value.Value)
+		case models.CriterionModifierNotEquals:
+			query.NotEq(column, value.Value)
+		case models.CriterionModifierGreaterThan:
+			query.AddWhere(column + " > ?")
+			query.AddArg(value.Value)
+		case models.CriterionModifierLessThan:
+			query.AddWhere(column + " < ?")
+			query.AddArg(value.Value)
+		case models.CriterionModifierIsNull:
+			query.IsNull(column)
+		case models.CriterionModifierNotNull:
+			query.IsNotNull(column)
+		}
+	}
+}
+
 func buildCountQuery(query string) string {
 	return "SELECT COUNT(*) as count FROM (" + query + ") as temp"
 }
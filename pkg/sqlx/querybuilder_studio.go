@@ -125,13 +125,36 @@ func (qb *studioQueryBuilder) Count() (int, error) {
 }
 
 func (qb *studioQueryBuilder) Query(studioFilter *models.StudioFilterType, findFilter *models.QuerySpec) (models.Studios, int) {
-	if studioFilter == nil {
-		studioFilter = &models.StudioFilterType{}
-	}
 	if findFilter == nil {
 		findFilter = &models.QuerySpec{}
 	}
 
+	query := qb.buildQuery(studioFilter)
+	query.SortAndPagination = qb.getStudioSort(findFilter) + getPagination(findFilter)
+	var studios models.Studios
+	countResult, err := qb.dbi.Query(*query, &studios)
+
+	if err != nil {
+		// TODO
+		panic(err)
+	}
+
+	return studios, countResult
+}
+
+func (qb *studioQueryBuilder) QueryPage(studioFilter *models.StudioFilterType, findFilter *models.CursorQuerySpec) (models.Studios, *models.Page, error) {
+	query := qb.buildQuery(studioFilter)
+
+	var studios models.Studios
+	page, err := qb.dbi.queryPage(query, findFilter, "name", []string{"name", "created_at", "updated_at"}, &studios)
+	return studios, page, err
+}
+
+func (qb *studioQueryBuilder) buildQuery(studioFilter *models.StudioFilterType) *queryBuilder {
+	if studioFilter == nil {
+		studioFilter = &models.StudioFilterType{}
+	}
+
 	query := newQueryBuilder(studioDBTable)
 	query.Body += "LEFT JOIN studios as parent_studio ON studios.parent_studio_id = parent_studio.id"
 
@@ -159,16 +182,7 @@ func (qb *studioQueryBuilder) Query(studioFilter *models.StudioFilterType, findF
 		}
 	}
 
-	query.SortAndPagination = qb.getStudioSort(findFilter) + getPagination(findFilter)
-	var studios models.Studios
-	countResult, err := qb.dbi.Query(*query, &studios)
-
-	if err != nil {
-		// TODO
-		panic(err)
-	}
-
-	return studios, countResult
+	return query
 }
 
 func (qb *studioQueryBuilder) getStudioSort(findFilter *models.QuerySpec) string {
@@ -284,6 +298,10 @@ func (qb *studioQueryBuilder) ApplyEdit(edit models.Edit, operation models.Opera
 			return nil, err
 		}
 
+		if err := logChange(qb.dbi.txn, models.TargetTypeEnumStudio, models.OperationEnumCreate, UUID, nil); err != nil {
+			return nil, err
+		}
+
 		if len(data.New.AddedUrls) > 0 {
 			urls := models.CreateStudioURLs(UUID, data.New.AddedUrls)
 			if err := qb.CreateURLs(urls); err != nil {
@@ -339,6 +357,10 @@ func (qb *studioQueryBuilder) applyModifyEdit(studio *models.Studio, data *model
 		return nil, err
 	}
 
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumStudio, models.OperationEnumModify, updatedStudio.ID, nil); err != nil {
+		return nil, err
+	}
+
 	urls, err := qb.GetURLs(updatedStudio.ID)
 	currentUrls := models.CreateStudioURLs(updatedStudio.ID, urls)
 	if err != nil {
@@ -384,10 +406,13 @@ func (qb *studioQueryBuilder) mergeInto(sourceID uuid.UUID, targetID uuid.UUID)
 	if studio.Deleted {
 		return errors.New("Merge source studio is deleted: " + sourceID.String())
 	}
-	_, err = qb.SoftDelete(*studio)
+	_, err = qb.softDelete(*studio)
 	if err != nil {
 		return err
 	}
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumStudio, models.OperationEnumMerge, sourceID, &targetID); err != nil {
+		return err
+	}
 	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
 		return err
 	}
@@ -409,6 +434,15 @@ func (qb *studioQueryBuilder) UpdateRedirects(oldTargetID uuid.UUID, newTargetID
 }
 
 func (qb *studioQueryBuilder) SoftDelete(studio models.Studio) (*models.Studio, error) {
+	ret, err := qb.softDelete(studio)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, logChange(qb.dbi.txn, models.TargetTypeEnumStudio, models.OperationEnumDestroy, studio.ID, nil)
+}
+
+func (qb *studioQueryBuilder) softDelete(studio models.Studio) (*models.Studio, error) {
 	ret, err := qb.dbi.SoftDelete(studioDBTable, studio)
 	return qb.toModel(ret), err
 }
@@ -68,10 +68,26 @@ func (qb *tagQueryBuilder) Destroy(id uuid.UUID) error {
 
 func (qb *tagQueryBuilder) DeleteSceneTags(id uuid.UUID) error {
 	// Delete scene_tags joins
-	return qb.dbi.DeleteJoins(tagSceneTable, id)
+	if err := qb.dbi.DeleteJoins(tagSceneTable, id); err != nil {
+		return err
+	}
+
+	// Delete scene markers with the tag
+	query := `DELETE FROM scene_markers WHERE tag_id = ?`
+	args := []interface{}{id}
+	return qb.dbi.RawQuery(sceneMarkerTable.table, query, args, nil)
 }
 
 func (qb *tagQueryBuilder) SoftDelete(tag models.Tag) (*models.Tag, error) {
+	ret, err := qb.softDelete(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, logChange(qb.dbi.txn, models.TargetTypeEnumTag, models.OperationEnumDestroy, tag.ID, nil)
+}
+
+func (qb *tagQueryBuilder) softDelete(tag models.Tag) (*models.Tag, error) {
 	// Delete tag aliases
 	if err := qb.dbi.DeleteJoins(tagAliasTable, tag.ID); err != nil {
 		return nil, err
@@ -105,7 +121,14 @@ func (qb *tagQueryBuilder) UpdateSceneTags(oldTargetID uuid.UUID, newTargetID uu
 	// Delete any joins with the old tag
 	query = `DELETE FROM scene_tags WHERE tag_id = ?`
 	args = []interface{}{oldTargetID}
-	return qb.dbi.RawQuery(sceneTagTable.table, query, args, nil)
+	if err := qb.dbi.RawQuery(sceneTagTable.table, query, args, nil); err != nil {
+		return err
+	}
+
+	// Reassign scene markers to the new tag
+	query = `UPDATE scene_markers SET tag_id = ? WHERE tag_id = ?`
+	args = []interface{}{newTargetID, oldTargetID}
+	return qb.dbi.RawQuery(sceneMarkerTable.table, query, args, nil)
 }
 
 func (qb *tagQueryBuilder) CreateAliases(newJoins models.TagAliases) error {
@@ -234,13 +257,36 @@ func (qb *tagQueryBuilder) Count() (int, error) {
 }
 
 func (qb *tagQueryBuilder) Query(tagFilter *models.TagFilterType, findFilter *models.QuerySpec) ([]*models.Tag, int, error) {
-	if tagFilter == nil {
-		tagFilter = &models.TagFilterType{}
-	}
 	if findFilter == nil {
 		findFilter = &models.QuerySpec{}
 	}
 
+	query := qb.buildQuery(tagFilter)
+	query.SortAndPagination = qb.getTagSort(findFilter) + getPagination(findFilter)
+	var tags models.Tags
+
+	countResult, err := qb.dbi.Query(*query, &tags)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tags, countResult, nil
+}
+
+func (qb *tagQueryBuilder) QueryPage(tagFilter *models.TagFilterType, findFilter *models.CursorQuerySpec) ([]*models.Tag, *models.Page, error) {
+	query := qb.buildQuery(tagFilter)
+
+	var tags models.Tags
+	page, err := qb.dbi.queryPage(query, findFilter, "name", []string{"name", "created_at", "updated_at"}, &tags)
+	return tags, page, err
+}
+
+func (qb *tagQueryBuilder) buildQuery(tagFilter *models.TagFilterType) *queryBuilder {
+	if tagFilter == nil {
+		tagFilter = &models.TagFilterType{}
+	}
+
 	query := newQueryBuilder(tagDBTable)
 	query.Eq("deleted", false)
 
@@ -255,16 +301,7 @@ func (qb *tagQueryBuilder) Query(tagFilter *models.TagFilterType, findFilter *mo
 		query.Eq("tags.category_id", catID)
 	}
 
-	query.SortAndPagination = qb.getTagSort(findFilter) + getPagination(findFilter)
-	var tags models.Tags
-
-	countResult, err := qb.dbi.Query(*query, &tags)
-
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return tags, countResult, nil
+	return query
 }
 
 func (qb *tagQueryBuilder) getTagSort(findFilter *models.QuerySpec) string {
@@ -309,10 +346,13 @@ func (qb *tagQueryBuilder) mergeInto(sourceID uuid.UUID, targetID uuid.UUID) err
 	if tag.Deleted {
 		return errors.New("Merge source tag is deleted: " + sourceID.String())
 	}
-	_, err = qb.SoftDelete(*tag)
+	_, err = qb.softDelete(*tag)
 	if err != nil {
 		return err
 	}
+	if err := logChange(qb.dbi.txn, models.TargetTypeEnumTag, models.OperationEnumMerge, sourceID, &targetID); err != nil {
+		return err
+	}
 	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
 		return err
 	}
@@ -351,6 +391,10 @@ func (qb *tagQueryBuilder) ApplyEdit(edit models.Edit, operation models.Operatio
 			return nil, err
 		}
 
+		if err := logChange(qb.dbi.txn, models.TargetTypeEnumTag, models.OperationEnumCreate, UUID, nil); err != nil {
+			return nil, err
+		}
+
 		if len(data.New.AddedAliases) > 0 {
 			aliases := models.CreateTagAliases(UUID, data.New.AddedAliases)
 			if err := qb.CreateAliases(aliases); err != nil {
@@ -377,6 +421,10 @@ func (qb *tagQueryBuilder) ApplyEdit(edit models.Edit, operation models.Operatio
 			return nil, err
 		}
 
+		if err := logChange(qb.dbi.txn, models.TargetTypeEnumTag, models.OperationEnumModify, updatedTag.ID, nil); err != nil {
+			return nil, err
+		}
+
 		currentAliases, err := qb.GetRawAliases(updatedTag.ID)
 		if err != nil {
 			return nil, err
@@ -404,6 +452,10 @@ func (qb *tagQueryBuilder) ApplyEdit(edit models.Edit, operation models.Operatio
 			return nil, err
 		}
 
+		if err := logChange(qb.dbi.txn, models.TargetTypeEnumTag, models.OperationEnumModify, updatedTag.ID, nil); err != nil {
+			return nil, err
+		}
+
 		for _, v := range data.MergeSources {
 			sourceUUID, _ := uuid.FromString(v)
 			if err := qb.mergeInto(sourceUUID, tag.ID); err != nil {
@@ -1,13 +1,16 @@
 package sqlx
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/stashapp/stash-box/pkg/models"
 )
 
 const (
-	userTable   = "users"
-	userJoinKey = "user_id"
+	userTable         = "users"
+	userJoinKey       = "user_id"
+	userAPICallsTable = "user_api_calls"
 )
 
 var (
@@ -18,6 +21,14 @@ var (
 	userRolesTable = newTableJoin(userTable, "user_roles", userJoinKey, func() interface{} {
 		return &models.UserRole{}
 	})
+
+	userRecoveryCodesTable = newTableJoin(userTable, "user_recovery_codes", userJoinKey, func() interface{} {
+		return &models.UserRecoveryCode{}
+	})
+
+	userIdentitiesTable = newTableJoin(userTable, "user_identities", userJoinKey, func() interface{} {
+		return &models.UserIdentity{}
+	})
 )
 
 type userQueryBuilder struct {
@@ -65,6 +76,28 @@ func (qb *userQueryBuilder) UpdateRoles(studioID uuid.UUID, updatedJoins models.
 	return qb.dbi.ReplaceJoins(userRolesTable, studioID, &updatedJoins)
 }
 
+func (qb *userQueryBuilder) CreateIdentities(newJoins models.UserIdentities) error {
+	return qb.dbi.InsertJoins(userIdentitiesTable, &newJoins)
+}
+
+func (qb *userQueryBuilder) GetIdentities(id uuid.UUID) (models.UserIdentities, error) {
+	joins := models.UserIdentities{}
+	err := qb.dbi.FindJoins(userIdentitiesTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *userQueryBuilder) GetRecoveryCodes(id uuid.UUID) (models.UserRecoveryCodes, error) {
+	joins := models.UserRecoveryCodes{}
+	err := qb.dbi.FindJoins(userRecoveryCodesTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *userQueryBuilder) UpdateRecoveryCodes(id uuid.UUID, updatedJoins models.UserRecoveryCodes) error {
+	return qb.dbi.ReplaceJoins(userRecoveryCodesTable, id, &updatedJoins)
+}
+
 func (qb *userQueryBuilder) Find(id uuid.UUID) (*models.User, error) {
 	ret, err := qb.dbi.Find(id, userDBTable)
 	return qb.toModel(ret), err
@@ -92,6 +125,18 @@ func (qb *userQueryBuilder) FindByEmail(email string) (*models.User, error) {
 	return results[0], nil
 }
 
+func (qb *userQueryBuilder) FindByIdentity(issuer string, subject string) (*models.User, error) {
+	query := `SELECT users.* FROM users
+		JOIN user_identities ON user_identities.user_id = users.id
+		WHERE user_identities.issuer = ? AND user_identities.subject = ?`
+	args := []interface{}{issuer, subject}
+	results, err := qb.queryUsers(query, args)
+	if err != nil || len(results) < 1 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
 func (qb *userQueryBuilder) Count() (int, error) {
 	return runCountQuery(qb.dbi.db(), buildCountQuery("SELECT users.id FROM users"), nil)
 }
@@ -113,6 +158,8 @@ func (qb *userQueryBuilder) Query(userFilter *models.UserFilterType, findFilter
 		query.AddArg(thisArgs...)
 	}
 
+	handleIntCriterion("users.api_calls", userFilter.APICalls, query)
+
 	query.SortAndPagination = qb.getUserSort(findFilter) + getPagination(findFilter)
 	var studios models.Users
 	countResult, err := qb.dbi.Query(*query, &studios)
@@ -216,3 +263,65 @@ func (qb *userQueryBuilder) CountVotesByType(id uuid.UUID) (*models.UserVoteCoun
 
 	return &res, nil
 }
+
+func (qb *userQueryBuilder) CountVoteOutcomes(id uuid.UUID) (int, int, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE
+				(V.vote = 'ACCEPT' AND E.status IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED'))
+				OR (V.vote = 'REJECT' AND E.status IN ('REJECTED', 'IMMEDIATE_REJECTED'))
+			),
+			COUNT(*) FILTER (WHERE
+				(V.vote = 'ACCEPT' AND E.status IN ('REJECTED', 'IMMEDIATE_REJECTED'))
+				OR (V.vote = 'REJECT' AND E.status IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED'))
+			)
+		FROM edit_votes V
+		JOIN edits E ON E.id = V.edit_id
+		WHERE V.user_id = ?
+	`
+	rows, err := qb.dbi.queryx(query, id)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var matching, opposing int
+	if rows.Next() {
+		if err := rows.Scan(&matching, &opposing); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return matching, opposing, rows.Err()
+}
+
+func (qb *userQueryBuilder) AddAPICalls(id uuid.UUID, bucket time.Time, calls int, lastCall time.Time) error {
+	// users may have been deleted since the calls were made
+	query := `INSERT INTO ` + userAPICallsTable + ` (user_id, bucket, calls)
+		SELECT id, ?, ? FROM ` + userTable + ` WHERE id = ?
+		ON CONFLICT (user_id, bucket) DO UPDATE SET calls = ` + userAPICallsTable + `.calls + EXCLUDED.calls`
+	args := []interface{}{models.SQLiteTimestamp{Timestamp: bucket}, calls, id}
+	if err := qb.dbi.RawExec(query, args); err != nil {
+		return err
+	}
+
+	query = `UPDATE ` + userTable + ` SET last_api_call = ? WHERE id = ? AND last_api_call < ?`
+	last := models.SQLiteTimestamp{Timestamp: lastCall}
+	return qb.dbi.RawExec(query, []interface{}{last, id, last})
+}
+
+func (qb *userQueryBuilder) UpdateAPICallTotals(since time.Time) error {
+	query := `DELETE FROM ` + userAPICallsTable + ` WHERE bucket < ?`
+	if err := qb.dbi.RawExec(query, []interface{}{models.SQLiteTimestamp{Timestamp: since}}); err != nil {
+		return err
+	}
+
+	query = `UPDATE ` + userTable + ` SET api_calls = T.calls FROM (
+		SELECT U.id, COALESCE(SUM(C.calls), 0) AS calls FROM ` + userTable + ` U
+		LEFT JOIN ` + userAPICallsTable + ` C ON C.user_id = U.id
+		GROUP BY U.id
+	) T WHERE ` + userTable + `.id = T.id AND ` + userTable + `.api_calls IS DISTINCT FROM T.calls`
+	return qb.dbi.RawExec(query, nil)
+}
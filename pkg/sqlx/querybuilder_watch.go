@@ -0,0 +1,135 @@
+package sqlx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+var userWatchTable = newTableJoin(userTable, "user_watches", userJoinKey, func() interface{} {
+	return &models.Watch{}
+})
+
+// watchedEditTables are the edit join tables, with the target type of the
+// entity they join.
+var watchedEditTables = []struct {
+	table      tableJoin
+	column     string
+	targetType models.TargetTypeEnum
+}{
+	{editPerformerTable, performerJoinKey, models.TargetTypeEnumPerformer},
+	{editStudioTable, studioJoinKey, models.TargetTypeEnumStudio},
+	{editTagTable, tagJoinKey, models.TargetTypeEnumTag},
+	{editSceneTable, sceneJoinKey, models.TargetTypeEnumScene},
+}
+
+type watchQueryBuilder struct {
+	dbi *dbi
+}
+
+func newWatchQueryBuilder(txn *txnState) models.WatchRepo {
+	return &watchQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *watchQueryBuilder) Create(newWatch models.Watch) error {
+	conflictHandling := `ON CONFLICT DO NOTHING`
+	return qb.dbi.InsertJoin(userWatchTable, newWatch, &conflictHandling)
+}
+
+func (qb *watchQueryBuilder) Destroy(userID uuid.UUID, targetType models.TargetTypeEnum, targetID uuid.UUID) error {
+	query := `DELETE FROM user_watches WHERE user_id = ? AND target_type = ? AND target_id = ?`
+	return qb.dbi.RawExec(query, []interface{}{userID, targetType.String(), targetID})
+}
+
+func (qb *watchQueryBuilder) FindByUser(userID uuid.UUID) (models.Watches, error) {
+	query := selectStatement(userWatchTable.table) + ` WHERE user_id = ? ORDER BY created_at DESC`
+
+	var output models.Watches
+	err := qb.dbi.RawQuery(userWatchTable.table, query, []interface{}{userID}, &output)
+	return output, err
+}
+
+func (qb *watchQueryBuilder) FindWatchers(targetType models.TargetTypeEnum, targetID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT user_id FROM user_watches WHERE target_type = ? AND target_id = ?`
+	args := []interface{}{targetType.String(), targetID}
+
+	var ret []uuid.UUID
+	err := qb.dbi.queryFunc(query, args, func(rows *sqlx.Rows) error {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ret = append(ret, id)
+		return nil
+	})
+	return ret, err
+}
+
+func (qb *watchQueryBuilder) FindWatchedScenes(userID uuid.UUID, since *time.Time, limit int) (models.Scenes, error) {
+	query := `
+		SELECT scenes.* FROM scenes
+		WHERE NOT scenes.deleted
+		AND (
+			scenes.studio_id IN (
+				SELECT target_id FROM user_watches WHERE user_id = ? AND target_type = ?
+			)
+			OR EXISTS (
+				SELECT 1 FROM scene_performers J
+				JOIN user_watches W ON W.target_id = J.performer_id AND W.target_type = ?
+				WHERE J.scene_id = scenes.id AND W.user_id = ?
+			)
+			OR EXISTS (
+				SELECT 1 FROM scene_tags J
+				JOIN user_watches W ON W.target_id = J.tag_id AND W.target_type = ?
+				WHERE J.scene_id = scenes.id AND W.user_id = ?
+			)
+		)
+	`
+	args := []interface{}{
+		userID, models.TargetTypeEnumStudio.String(),
+		models.TargetTypeEnumPerformer.String(), userID,
+		models.TargetTypeEnumTag.String(), userID,
+	}
+	if since != nil {
+		query += ` AND scenes.created_at > ?`
+		args = append(args, *since)
+	}
+	query += ` ORDER BY scenes.created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	var output models.Scenes
+	err := qb.dbi.RawQuery(sceneDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *watchQueryBuilder) FindWatchedEdits(userID uuid.UUID, since *time.Time, limit int) (models.Edits, error) {
+	var clauses []string
+	var args []interface{}
+	for _, t := range watchedEditTables {
+		clauses = append(clauses, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %[1]s J
+			JOIN user_watches W ON W.target_id = J.%[2]s AND W.target_type = ?
+			WHERE J.edit_id = edits.id AND W.user_id = ?
+		)`, t.table.Name(), t.column))
+		args = append(args, t.targetType.String(), userID)
+	}
+
+	query := `SELECT edits.* FROM edits WHERE edits.status = ? AND (` + strings.Join(clauses, " OR ") + `)`
+	args = append([]interface{}{models.VoteStatusEnumPending.String()}, args...)
+	if since != nil {
+		query += ` AND edits.created_at > ?`
+		args = append(args, *since)
+	}
+	query += ` ORDER BY edits.created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	var output models.Edits
+	err := qb.dbi.RawQuery(editDBTable, query, args, &output)
+	return output, err
+}
@@ -0,0 +1,211 @@
+package sqlx
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const (
+	webhookTable   = "webhooks"
+	webhookJoinKey = "webhook_id"
+)
+
+var (
+	webhookDBTable = newTable(webhookTable, func() interface{} {
+		return &models.Webhook{}
+	})
+
+	webhookTargetTypesTable = newTableJoin(webhookTable, "webhook_target_types", webhookJoinKey, func() interface{} {
+		return &models.WebhookTargetType{}
+	})
+
+	webhookOperationsTable = newTableJoin(webhookTable, "webhook_operations", webhookJoinKey, func() interface{} {
+		return &models.WebhookOperation{}
+	})
+
+	webhookDeliveryDBTable = newTable("webhook_deliveries", func() interface{} {
+		return &models.WebhookDelivery{}
+	})
+)
+
+type webhookQueryBuilder struct {
+	dbi *dbi
+}
+
+func newWebhookQueryBuilder(txn *txnState) models.WebhookRepo {
+	return &webhookQueryBuilder{
+		dbi: newDBI(txn),
+	}
+}
+
+func (qb *webhookQueryBuilder) toModel(ro interface{}) *models.Webhook {
+	if ro != nil {
+		return ro.(*models.Webhook)
+	}
+
+	return nil
+}
+
+func (qb *webhookQueryBuilder) Create(newWebhook models.Webhook) (*models.Webhook, error) {
+	ret, err := qb.dbi.Insert(webhookDBTable, newWebhook)
+	return qb.toModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) Update(updatedWebhook models.Webhook) (*models.Webhook, error) {
+	ret, err := qb.dbi.Update(webhookDBTable, updatedWebhook, false)
+	return qb.toModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) Destroy(id uuid.UUID) error {
+	return qb.dbi.Delete(id, webhookDBTable)
+}
+
+func (qb *webhookQueryBuilder) Find(id uuid.UUID) (*models.Webhook, error) {
+	ret, err := qb.dbi.Find(id, webhookDBTable)
+	return qb.toModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) FindAll() (models.Webhooks, error) {
+	query := "SELECT * FROM webhooks ORDER BY created_at ASC"
+	var output models.Webhooks
+	err := qb.dbi.RawQuery(webhookDBTable, query, nil, &output)
+	return output, err
+}
+
+func (qb *webhookQueryBuilder) FindActiveByEvent(targetType models.TargetTypeEnum, operation models.OperationEnum) (models.Webhooks, error) {
+	// a webhook without any target type or operation filters matches all events
+	query := `
+		SELECT webhooks.* FROM webhooks
+		WHERE webhooks.active = TRUE
+		AND (
+			NOT EXISTS (SELECT 1 FROM webhook_target_types T WHERE T.webhook_id = webhooks.id)
+			OR EXISTS (SELECT 1 FROM webhook_target_types T WHERE T.webhook_id = webhooks.id AND T.target_type = ?)
+		)
+		AND (
+			NOT EXISTS (SELECT 1 FROM webhook_operations O WHERE O.webhook_id = webhooks.id)
+			OR EXISTS (SELECT 1 FROM webhook_operations O WHERE O.webhook_id = webhooks.id AND O.operation = ?)
+		)
+	`
+	args := []interface{}{targetType.String(), operation.String()}
+
+	var output models.Webhooks
+	err := qb.dbi.RawQuery(webhookDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *webhookQueryBuilder) CreateTargetTypes(newJoins models.WebhookTargetTypes) error {
+	return qb.dbi.InsertJoins(webhookTargetTypesTable, &newJoins)
+}
+
+func (qb *webhookQueryBuilder) UpdateTargetTypes(webhookID uuid.UUID, updatedJoins models.WebhookTargetTypes) error {
+	return qb.dbi.ReplaceJoins(webhookTargetTypesTable, webhookID, &updatedJoins)
+}
+
+func (qb *webhookQueryBuilder) GetTargetTypes(id uuid.UUID) (models.WebhookTargetTypes, error) {
+	joins := models.WebhookTargetTypes{}
+	err := qb.dbi.FindJoins(webhookTargetTypesTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *webhookQueryBuilder) CreateOperations(newJoins models.WebhookOperations) error {
+	return qb.dbi.InsertJoins(webhookOperationsTable, &newJoins)
+}
+
+func (qb *webhookQueryBuilder) UpdateOperations(webhookID uuid.UUID, updatedJoins models.WebhookOperations) error {
+	return qb.dbi.ReplaceJoins(webhookOperationsTable, webhookID, &updatedJoins)
+}
+
+func (qb *webhookQueryBuilder) GetOperations(id uuid.UUID) (models.WebhookOperations, error) {
+	joins := models.WebhookOperations{}
+	err := qb.dbi.FindJoins(webhookOperationsTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *webhookQueryBuilder) toDeliveryModel(ro interface{}) *models.WebhookDelivery {
+	if ro != nil {
+		return ro.(*models.WebhookDelivery)
+	}
+
+	return nil
+}
+
+func (qb *webhookQueryBuilder) CreateDelivery(newDelivery models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	ret, err := qb.dbi.Insert(webhookDeliveryDBTable, newDelivery)
+	return qb.toDeliveryModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) UpdateDelivery(updatedDelivery models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	ret, err := qb.dbi.Update(webhookDeliveryDBTable, updatedDelivery, true)
+	return qb.toDeliveryModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) FindDelivery(id uuid.UUID) (*models.WebhookDelivery, error) {
+	ret, err := qb.dbi.Find(id, webhookDeliveryDBTable)
+	return qb.toDeliveryModel(ret), err
+}
+
+func (qb *webhookQueryBuilder) ClaimDueDeliveries(limit int, claimUntil time.Time) (models.WebhookDeliveries, error) {
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	args := []interface{}{
+		models.SQLiteTimestamp{Timestamp: claimUntil},
+		models.WebhookDeliveryStatusEnumPending.String(),
+		models.SQLiteTimestamp{Timestamp: time.Now()},
+		limit,
+	}
+
+	var output models.WebhookDeliveries
+	err := qb.dbi.RawQuery(webhookDeliveryDBTable, query, args, &output)
+	return output, err
+}
+
+func (qb *webhookQueryBuilder) QueryDeliveries(filter *models.WebhookDeliveryFilterType, findFilter *models.QuerySpec) (models.WebhookDeliveries, int, error) {
+	if filter == nil {
+		filter = &models.WebhookDeliveryFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &models.QuerySpec{}
+	}
+
+	query := newQueryBuilder(webhookDeliveryDBTable)
+
+	if q := filter.WebhookID; q != nil && *q != "" {
+		query.Eq(webhookDeliveryDBTable.Name()+".webhook_id", *q)
+	}
+	if q := filter.TargetID; q != nil && *q != "" {
+		query.Eq(webhookDeliveryDBTable.Name()+".target_id", *q)
+	}
+	if q := filter.TargetType; q != nil {
+		query.Eq(webhookDeliveryDBTable.Name()+".target_type", q.String())
+	}
+	if q := filter.Operation; q != nil {
+		query.Eq(webhookDeliveryDBTable.Name()+".operation", q.String())
+	}
+	if q := filter.Status; q != nil {
+		query.Eq(webhookDeliveryDBTable.Name()+".status", q.String())
+	}
+
+	direction := findFilter.GetDirection()
+	if findFilter.Direction == nil {
+		direction = "DESC"
+	}
+	query.SortAndPagination = getSort(qb.dbi.txn.dialect, findFilter.GetSort("created_at"), direction, webhookDeliveryDBTable.Name(), nil) + getPagination(findFilter)
+
+	var deliveries models.WebhookDeliveries
+	countResult, err := qb.dbi.Query(*query, &deliveries)
+
+	return deliveries, countResult, err
+}
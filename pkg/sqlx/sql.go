@@ -232,6 +232,10 @@ func sqlGenKeysCreate(dialect Dialect, i interface{}) (string, string) {
 			if t.Valid {
 				addPlaceholder(key)
 			}
+		case sql.NullTime:
+			if t.Valid {
+				addPlaceholder(key)
+			}
 		default:
 			reflectValue := reflect.ValueOf(t)
 			isNil := reflectValue.IsNil()
@@ -297,6 +301,10 @@ func sqlGenKeys(dialect Dialect, i interface{}, partial bool) string {
 			if partial || t.Valid {
 				addKey(key)
 			}
+		case sql.NullTime:
+			if partial || t.Valid {
+				addKey(key)
+			}
 		default:
 			reflectValue := reflect.ValueOf(t)
 			isNil := reflectValue.IsNil()
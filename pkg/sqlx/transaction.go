@@ -23,8 +23,15 @@ type txnState struct {
 	rootDB  *sqlx.DB
 	tx      *sqlx.Tx
 	dialect Dialect
+
+	postCommitHooks []func()
 }
 
+// WithTxn runs fn in a transaction, or in the current transaction if one is
+// open. The transaction is rolled back if fn returns an error or panics, and
+// committed otherwise, so changes made before an error are discarded. Callers
+// that need to record a failure must do so in a separate transaction.
+// Post-commit hooks run only after a successful commit.
 func (m *txnState) WithTxn(fn func() error) (txErr error) {
 	if !m.InTxn() {
 		tx, err := m.rootDB.Beginx()
@@ -34,14 +41,27 @@ func (m *txnState) WithTxn(fn func() error) (txErr error) {
 
 		m.tx = tx
 
-		var txErr error
 		defer func() {
+			hooks := m.postCommitHooks
 			m.tx = nil
+			m.postCommitHooks = nil
+
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+
 			if txErr != nil {
 				// ignore rollback errors
 				_ = tx.Rollback()
-			} else {
-				txErr = tx.Commit()
+				return
+			}
+
+			txErr = tx.Commit()
+			if txErr == nil {
+				for _, hook := range hooks {
+					hook()
+				}
 			}
 		}()
 
@@ -51,6 +71,15 @@ func (m *txnState) WithTxn(fn func() error) (txErr error) {
 	return fn()
 }
 
+func (m *txnState) AddPostCommitHook(fn func()) {
+	if !m.InTxn() {
+		fn()
+		return
+	}
+
+	m.postCommitHooks = append(m.postCommitHooks, fn)
+}
+
 func (m *txnState) InTxn() bool {
 	return m.tx != nil
 }
@@ -14,6 +14,11 @@ type Mgr interface {
 type State interface {
 	WithTxn(fn func() error) error
 	InTxn() bool
+
+	// AddPostCommitHook registers a function to be called once the current
+	// transaction has been successfully committed. If there is no current
+	// transaction, the function is called immediately.
+	AddPostCommitHook(fn func())
 }
 
 func MustBeIn(m State) {
@@ -0,0 +1,102 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// API calls are counted per hour
+const apiCallBucketSize = time.Hour
+
+type apiCallCount struct {
+	calls    int
+	lastCall time.Time
+}
+
+// API calls are counted in memory and periodically written to the database
+// by FlushAPICalls, rather than writing on every request.
+var (
+	apiCallMutex    sync.Mutex
+	apiCallCounts   = make(map[uuid.UUID]*apiCallCount)
+	apiKeysLastUsed = make(map[uuid.UUID]time.Time)
+)
+
+// RecordAPICall counts an API call made by the user. key is the named api
+// key used for the call, if any.
+func RecordAPICall(userID uuid.UUID, key *models.APIKey) {
+	apiCallMutex.Lock()
+	defer apiCallMutex.Unlock()
+
+	now := time.Now()
+	c := apiCallCounts[userID]
+	if c == nil {
+		c = &apiCallCount{}
+		apiCallCounts[userID] = c
+	}
+	c.calls++
+	c.lastCall = now
+
+	if key != nil {
+		apiKeysLastUsed[key.ID] = now
+	}
+}
+
+// FlushAPICalls writes the API calls recorded since the last flush to the
+// database, and updates the API call count of each user to the number of
+// calls made within the configured window.
+func FlushAPICalls(fac models.Repo) error {
+	apiCallMutex.Lock()
+	counts := apiCallCounts
+	keysLastUsed := apiKeysLastUsed
+	apiCallCounts = make(map[uuid.UUID]*apiCallCount)
+	apiKeysLastUsed = make(map[uuid.UUID]time.Time)
+	apiCallMutex.Unlock()
+
+	now := time.Now()
+	bucket := now.Truncate(apiCallBucketSize)
+	since := now.Add(-config.GetAPICallWindow()).Truncate(apiCallBucketSize)
+
+	err := fac.WithTxn(func() error {
+		qb := fac.User()
+		for id, c := range counts {
+			if err := qb.AddAPICalls(id, bucket, c.calls, c.lastCall); err != nil {
+				return err
+			}
+		}
+
+		kqb := fac.APIKey()
+		for id, lastUsed := range keysLastUsed {
+			if err := kqb.UpdateLastUsed(id, lastUsed); err != nil {
+				return err
+			}
+		}
+
+		return qb.UpdateAPICallTotals(since)
+	})
+
+	if err != nil {
+		// add the counts back so they are written on the next flush
+		apiCallMutex.Lock()
+		for id, c := range counts {
+			existing := apiCallCounts[id]
+			if existing == nil {
+				apiCallCounts[id] = c
+				continue
+			}
+			existing.calls += c.calls
+		}
+		for id, lastUsed := range keysLastUsed {
+			if _, found := apiKeysLastUsed[id]; !found {
+				apiKeysLastUsed[id] = lastUsed
+			}
+		}
+		apiCallMutex.Unlock()
+	}
+
+	return err
+}
@@ -1,12 +1,15 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
 )
 
 var ErrInvalidToken = errors.New("invalid apikey")
@@ -15,6 +18,9 @@ const APIKeySubject = "APIKey"
 
 type APIKeyClaims struct {
 	UserID string `json:"uid"`
+	// KeyID is set for named api keys, and is empty for the user's primary
+	// api key.
+	KeyID string `json:"kid,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -27,6 +33,10 @@ func GenerateAPIKey(userID string) (string, error) {
 		},
 	}
 
+	return signAPIKey(claims)
+}
+
+func signAPIKey(claims *APIKeyClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	ss, err := token.SignedString(config.GetJWTSignKey())
@@ -37,20 +47,167 @@ func GenerateAPIKey(userID string) (string, error) {
 	return ss, nil
 }
 
-// GetUserIDFromAPIKey validates the provided api key and returns the user ID
-func GetUserIDFromAPIKey(apiKey string) (string, error) {
+func parseAPIKey(apiKey string) (*APIKeyClaims, error) {
 	claims := &APIKeyClaims{}
 	token, err := jwt.ParseWithClaims(apiKey, claims, func(t *jwt.Token) (interface{}, error) {
 		return config.GetJWTSignKey(), nil
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// GetUserIDFromAPIKey validates the provided api key and returns the user ID
+func GetUserIDFromAPIKey(apiKey string) (string, error) {
+	claims, err := parseAPIKey(apiKey)
+	if err != nil {
+		return "", err
 	}
 
 	return claims.UserID, nil
 }
+
+// CreateAPIKey creates a named api key for the user, returning the key and
+// its value.
+func CreateAPIKey(fac models.Repo, userID uuid.UUID, input models.APIKeyCreateInput) (*models.APIKey, string, error) {
+	if !input.Scope.IsValid() {
+		return nil, "", errors.New("invalid api key scope")
+	}
+
+	now := time.Now()
+	if input.Expires != nil && !input.Expires.After(now) {
+		return nil, "", errors.New("api key expiry must be in the future")
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, "", err
+	}
+
+	newKey := models.APIKey{
+		ID:        UUID,
+		UserID:    userID,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: now},
+	}
+	newKey.CopyFromCreateInput(input)
+
+	key, err := fac.APIKey().Create(newKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims := &APIKeyClaims{
+		UserID: userID.String(),
+		KeyID:  key.ID.String(),
+		StandardClaims: jwt.StandardClaims{
+			Subject:  APIKeySubject,
+			IssuedAt: now.Unix(),
+		},
+	}
+	if input.Expires != nil {
+		claims.ExpiresAt = input.Expires.Unix()
+	}
+
+	value, err := signAPIKey(claims)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, value, nil
+}
+
+// ValidateAPIKey checks that apiKey belongs to the user. It returns the
+// named api key, or nil if apiKey is the user's primary api key.
+func ValidateAPIKey(fac models.Repo, u *models.User, apiKey string) (*models.APIKey, error) {
+	claims, err := parseAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.UserID != u.ID.String() {
+		return nil, ErrUnauthorized
+	}
+
+	if claims.KeyID == "" {
+		if u.APIKey != apiKey {
+			return nil, ErrUnauthorized
+		}
+		return nil, nil
+	}
+
+	keyID, err := uuid.FromString(claims.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// revoked keys are deleted
+	key, err := fac.APIKey().Find(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil || key.UserID != u.ID || key.IsExpired(time.Now()) {
+		return nil, ErrUnauthorized
+	}
+
+	return key, nil
+}
+
+// ScopeRoles returns the roles that a named api key with the provided scope
+// may use, given the roles of its user.
+func ScopeRoles(roles []models.RoleEnum, scope models.APIKeyScopeEnum) []models.RoleEnum {
+	var ret []models.RoleEnum
+	for _, allowed := range scope.Roles() {
+		for _, role := range roles {
+			if role.Implies(allowed) {
+				ret = append(ret, allowed)
+				break
+			}
+		}
+	}
+
+	return ret
+}
+
+// GetContextAPIKey returns the named api key used to authenticate the
+// request, or nil if the request was not authenticated with one.
+func GetContextAPIKey(ctx context.Context) *models.APIKey {
+	if key, ok := ctx.Value(ContextAPIKey).(*models.APIKey); ok {
+		return key
+	}
+
+	return nil
+}
+
+// ValidateScope returns ErrUnauthorized if the request was authenticated
+// with a named api key whose scope does not imply the required scope.
+func ValidateScope(ctx context.Context, requiredScope models.APIKeyScopeEnum) error {
+	key := GetContextAPIKey(ctx)
+	if key == nil {
+		return nil
+	}
+
+	if !models.APIKeyScopeEnum(key.Scope).Implies(requiredScope) {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+// ValidateUnscoped returns ErrUnauthorized if the request was authenticated
+// with a named api key. Managing the account requires a session or the
+// user's primary api key.
+func ValidateUnscoped(ctx context.Context) error {
+	if GetContextAPIKey(ctx) != nil {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
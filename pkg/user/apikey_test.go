@@ -0,0 +1,78 @@
+package user_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func TestScopeRoles(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []models.RoleEnum
+		scope models.APIKeyScopeEnum
+		want  []models.RoleEnum
+	}{
+		{
+			"admin read",
+			[]models.RoleEnum{models.RoleEnumAdmin},
+			models.APIKeyScopeEnumRead,
+			[]models.RoleEnum{models.RoleEnumRead},
+		},
+		{
+			"admin edit",
+			[]models.RoleEnum{models.RoleEnumAdmin},
+			models.APIKeyScopeEnumEdit,
+			[]models.RoleEnum{models.RoleEnumRead, models.RoleEnumVote, models.RoleEnumEdit},
+		},
+		{
+			"read edit",
+			[]models.RoleEnum{models.RoleEnumRead},
+			models.APIKeyScopeEnumEdit,
+			[]models.RoleEnum{models.RoleEnumRead},
+		},
+		{
+			"none",
+			nil,
+			models.APIKeyScopeEnumSubmitFingerprints,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := user.ScopeRoles(tt.roles, tt.scope); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScopeRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScope(t *testing.T) {
+	ctx := context.Background()
+	if err := user.ValidateScope(ctx, models.APIKeyScopeEnumEdit); err != nil {
+		t.Errorf("unscoped request: unexpected error %v", err)
+	}
+	if err := user.ValidateUnscoped(ctx); err != nil {
+		t.Errorf("unscoped request: unexpected error %v", err)
+	}
+
+	key := &models.APIKey{Scope: models.APIKeyScopeEnumSubmitFingerprints.String()}
+	ctx = context.WithValue(ctx, user.ContextAPIKey, key)
+
+	if err := user.ValidateScope(ctx, models.APIKeyScopeEnumRead); err != nil {
+		t.Errorf("read: unexpected error %v", err)
+	}
+	if err := user.ValidateScope(ctx, models.APIKeyScopeEnumSubmitFingerprints); err != nil {
+		t.Errorf("submit fingerprints: unexpected error %v", err)
+	}
+	if err := user.ValidateScope(ctx, models.APIKeyScopeEnumEdit); err != user.ErrUnauthorized {
+		t.Errorf("edit: got %v, want %v", err, user.ErrUnauthorized)
+	}
+	if err := user.ValidateUnscoped(ctx); err != user.ErrUnauthorized {
+		t.Errorf("scoped request: got %v, want %v", err, user.ErrUnauthorized)
+	}
+}
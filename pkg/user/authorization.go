@@ -13,6 +13,7 @@ type key int
 const (
 	ContextUser key = iota
 	ContextRoles
+	ContextAPIKey
 )
 
 const APIKeyHeader = "ApiKey"
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/utils"
+)
+
+var (
+	ErrIdentityNotLinked     = errors.New("external identity is not linked to a user")
+	ErrIdentityAlreadyLinked = errors.New("external identity is already linked to a user")
+	ErrIdentityEmailUsed     = errors.New("a user with the external identity's email already exists, log in and link the identity instead")
+	ErrIdentityNameUsed      = errors.New("a user with the external identity's username already exists")
+)
+
+// ExternalIdentity is a user authenticated by an external identity provider.
+type ExternalIdentity struct {
+	Issuer  string
+	Subject string
+	// Username and Email are used when provisioning a new user. Email should
+	// only be set if it has been verified by the provider.
+	Username string
+	Email    string
+}
+
+// AuthenticateExternal returns the id of the user linked to the external
+// identity. If no user is linked and autoProvision is true, a new user is
+// created with the default user roles.
+func AuthenticateExternal(fac models.Repo, identity ExternalIdentity, autoProvision bool) (string, error) {
+	qb := fac.User()
+
+	u, err := qb.FindByIdentity(identity.Issuer, identity.Subject)
+	if err != nil {
+		return "", err
+	}
+
+	if u != nil {
+		return u.ID.String(), nil
+	}
+
+	if !autoProvision {
+		return "", ErrIdentityNotLinked
+	}
+
+	u, err = provisionUser(fac, identity)
+	if err != nil {
+		return "", err
+	}
+
+	if err := linkIdentity(qb, u.ID, identity); err != nil {
+		return "", err
+	}
+
+	return u.ID.String(), nil
+}
+
+func provisionUser(fac models.Repo, identity ExternalIdentity) (*models.User, error) {
+	qb := fac.User()
+
+	if err := validateUserName(identity.Username); err != nil {
+		return nil, err
+	}
+
+	existing, err := qb.FindByName(identity.Username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrIdentityNameUsed
+	}
+
+	email := unsetEmail
+	if identity.Email != "" {
+		if err := validateUserEmail(identity.Email); err != nil {
+			return nil, err
+		}
+
+		existing, err := qb.FindByEmail(identity.Email)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrIdentityEmailUsed
+		}
+
+		email = identity.Email
+	}
+
+	// the user logs in with the identity provider, so the password is
+	// never used unless it is reset
+	const passwordLength = 16
+	password, err := utils.GenerateRandomPassword(passwordLength)
+	if err != nil {
+		return nil, err
+	}
+
+	return Create(fac, models.UserCreateInput{
+		Name:     identity.Username,
+		Password: password,
+		Email:    email,
+		Roles:    getDefaultUserRoles(),
+	})
+}
+
+// LinkIdentity links the external identity to an existing user, so that
+// they may log in with it.
+func LinkIdentity(fac models.Repo, userID uuid.UUID, identity ExternalIdentity) error {
+	qb := fac.User()
+
+	u, err := qb.FindByIdentity(identity.Issuer, identity.Subject)
+	if err != nil {
+		return err
+	}
+
+	if u != nil {
+		if u.ID == userID {
+			return nil
+		}
+		return ErrIdentityAlreadyLinked
+	}
+
+	return linkIdentity(qb, userID, identity)
+}
+
+func linkIdentity(qb models.UserRepo, userID uuid.UUID, identity ExternalIdentity) error {
+	return qb.CreateIdentities(models.UserIdentities{
+		&models.UserIdentity{
+			UserID:    userID,
+			Issuer:    identity.Issuer,
+			Subject:   identity.Subject,
+			CreatedAt: models.SQLiteTimestamp{Timestamp: time.Now()},
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// buckets idle for longer than this are full, and can be discarded
+const rateLimitPruneInterval = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiter limits the number of requests per minute made by each user,
+// using a token bucket that holds up to a minute's worth of requests.
+type RateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[uuid.UUID]*tokenBucket
+	lastPrune time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[uuid.UUID]*tokenBucket),
+	}
+}
+
+// Allow takes a token from the user's bucket, given a limit of requests per
+// minute. If no token is available it returns false, along with the time
+// until one will be. A limit of 0 or less is unlimited.
+func (l *RateLimiter) Allow(userID uuid.UUID, limit int, now time.Time) (bool, time.Duration) {
+	if limit <= 0 {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.prune(now)
+
+	capacity := float64(limit)
+	perSecond := capacity / 60
+
+	b := l.buckets[userID]
+	if b == nil {
+		b = &tokenBucket{
+			tokens:  capacity,
+			updated: now,
+		}
+		l.buckets[userID] = b
+	}
+
+	b.tokens += now.Sub(b.updated).Seconds() * perSecond
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / perSecond
+		return false, time.Duration(wait * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimitPruneInterval {
+		return
+	}
+
+	for id, b := range l.buckets {
+		// buckets refill completely within a minute
+		if now.Sub(b.updated) > time.Minute {
+			delete(l.buckets, id)
+		}
+	}
+	l.lastPrune = now
+}
+
+// GetAPIRateLimit returns the number of API requests per minute allowed for
+// a user with the provided roles. Users get the highest limit of their
+// roles, or the default limit if none of their roles have a limit set.
+// A limit of 0 is unlimited.
+func GetAPIRateLimit(roles []models.RoleEnum) int {
+	roleLimits := config.GetAPIRateLimitRoles()
+
+	found := false
+	ret := 0
+	for _, role := range roles {
+		limit, ok := roleLimits[role.String()]
+		if !ok {
+			continue
+		}
+
+		if limit <= 0 {
+			return 0
+		}
+		if !found || limit > ret {
+			ret = limit
+		}
+		found = true
+	}
+
+	if !found {
+		return config.GetAPIRateLimit()
+	}
+
+	return ret
+}
@@ -0,0 +1,90 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+	"github.com/stashapp/stash-box/pkg/user"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	const limit = 60
+	l := user.NewRateLimiter()
+	id := uuid.Must(uuid.NewV4())
+	now := time.Now()
+
+	// the bucket starts full
+	for i := 0; i < limit; i++ {
+		if allowed, _ := l.Allow(id, limit, now); !allowed {
+			t.Fatalf("request %d: expected request to be allowed", i)
+		}
+	}
+
+	allowed, retryAfter := l.Allow(id, limit, now)
+	if allowed {
+		t.Fatal("expected request over the limit to be denied")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("retryAfter: got %s want %s", retryAfter, time.Second)
+	}
+
+	// a token is added every second
+	if allowed, _ := l.Allow(id, limit, now.Add(time.Second)); !allowed {
+		t.Error("expected request to be allowed after refill")
+	}
+
+	// other users have their own bucket
+	if allowed, _ := l.Allow(uuid.Must(uuid.NewV4()), limit, now); !allowed {
+		t.Error("expected request from other user to be allowed")
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	l := user.NewRateLimiter()
+	id := uuid.Must(uuid.NewV4())
+	now := time.Now()
+
+	for i := 0; i < 1000; i++ {
+		if allowed, _ := l.Allow(id, 0, now); !allowed {
+			t.Fatal("expected unlimited requests to be allowed")
+		}
+	}
+}
+
+func TestGetAPIRateLimit(t *testing.T) {
+	defaultLimit := config.C.APIRateLimit
+	defaultRoles := config.C.APIRateLimitRoles
+	defer func() {
+		config.C.APIRateLimit = defaultLimit
+		config.C.APIRateLimitRoles = defaultRoles
+	}()
+
+	config.C.APIRateLimit = 60
+	config.C.APIRateLimitRoles = map[string]int{
+		// viper lowercases keys
+		"edit":  120,
+		"vote":  90,
+		"admin": 0,
+	}
+
+	tests := []struct {
+		roles []models.RoleEnum
+		want  int
+	}{
+		{nil, 60},
+		{[]models.RoleEnum{models.RoleEnumRead}, 60},
+		{[]models.RoleEnum{models.RoleEnumVote}, 90},
+		{[]models.RoleEnum{models.RoleEnumRead, models.RoleEnumVote, models.RoleEnumEdit}, 120},
+		{[]models.RoleEnum{models.RoleEnumEdit, models.RoleEnumAdmin}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := user.GetAPIRateLimit(tt.roles); got != tt.want {
+			t.Errorf("GetAPIRateLimit(%v): got %d want %d", tt.roles, got, tt.want)
+		}
+	}
+}
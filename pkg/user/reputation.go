@@ -0,0 +1,53 @@
+package user
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// Reputation summarises how often the votes and edits of a user matched the
+// final outcome of edits.
+type Reputation struct {
+	// Accept and reject votes on closed edits matching the outcome
+	MatchingVotes int
+	// Accept and reject votes on closed edits opposing the outcome
+	OpposingVotes int
+	AcceptedEdits int
+	RejectedEdits int
+}
+
+// Score returns the share of outcomes agreeing with the user, between 0 and
+// 1. It is smoothed so that users without any history score 0.5.
+func (r Reputation) Score() float64 {
+	agreeing := r.MatchingVotes + r.AcceptedEdits
+	total := agreeing + r.OpposingVotes + r.RejectedEdits
+	return float64(agreeing+1) / float64(total+2)
+}
+
+// Weight returns the weight of the votes of the user in weighted tallies,
+// between 0 and 2. Users without any history have a weight of 1.
+func (r Reputation) Weight() float64 {
+	return 2 * r.Score()
+}
+
+// GetReputation returns the reputation of the user with the provided id.
+func GetReputation(fac models.Repo, userID uuid.UUID) (*Reputation, error) {
+	qb := fac.User()
+	matching, opposing, err := qb.CountVoteOutcomes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := qb.CountEditsByStatus(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reputation{
+		MatchingVotes: matching,
+		OpposingVotes: opposing,
+		AcceptedEdits: edits.Accepted + edits.ImmediateAccepted,
+		RejectedEdits: edits.Rejected + edits.ImmediateRejected,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package user
+
+import (
+	"testing"
+)
+
+func TestReputation(t *testing.T) {
+	tests := []struct {
+		name       string
+		reputation Reputation
+		wantScore  float64
+		wantWeight float64
+	}{
+		{"no history", Reputation{}, 0.5, 1},
+		{"all matching", Reputation{MatchingVotes: 6, AcceptedEdits: 2}, 0.9, 1.8},
+		{"all opposing", Reputation{OpposingVotes: 3, RejectedEdits: 5}, 0.1, 0.2},
+		{"mixed", Reputation{MatchingVotes: 3, OpposingVotes: 3}, 0.5, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reputation.Score(); got != tt.wantScore {
+				t.Errorf("Score() = %v, want %v", got, tt.wantScore)
+			}
+			if got := tt.reputation.Weight(); got != tt.wantWeight {
+				t.Errorf("Weight() = %v, want %v", got, tt.wantWeight)
+			}
+		})
+	}
+}
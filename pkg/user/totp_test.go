@@ -0,0 +1,105 @@
+package user
+
+import (
+	"encoding/base32"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// test vectors from RFC 6238, truncated to 6 digits
+var totpTestSecret = []byte("12345678901234567890")
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(totpTestSecret, totpStep(time.Unix(tt.time, 0))); got != tt.code {
+			t.Errorf("totpCode(%d) = %s, want %s", tt.time, got, tt.code)
+		}
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(totpTestSecret)
+	now := time.Unix(1111111111, 0)
+	step := totpStep(now)
+
+	if got, ok := validateTOTPCode(secret, "050471", now, 0); !ok || got != step {
+		t.Errorf("current code: got (%d, %v), want (%d, true)", got, ok, step)
+	}
+
+	// the code from the previous period is accepted
+	if got, ok := validateTOTPCode(secret, "081804", now, 0); !ok || got != step-1 {
+		t.Errorf("previous code: got (%d, %v), want (%d, true)", got, ok, step-1)
+	}
+
+	// lower case secrets are accepted
+	if _, ok := validateTOTPCode(strings.ToLower(secret), "050471", now, 0); !ok {
+		t.Error("lower case secret: expected code to be valid")
+	}
+
+	// codes cannot be reused
+	if _, ok := validateTOTPCode(secret, "050471", now, step); ok {
+		t.Error("reused code: expected code to be invalid")
+	}
+
+	for _, code := range []string{"", "000000", "05047", "0504710"} {
+		if _, ok := validateTOTPCode(secret, code, now, 0); ok {
+			t.Errorf("code %q: expected code to be invalid", code)
+		}
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	code, err := generateRecoveryCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(code) != recoveryCodeLength*2+1 {
+		t.Errorf("unexpected recovery code length: %s", code)
+	}
+
+	if hashRecoveryCode(code) != hashRecoveryCode(" "+strings.ToUpper(code)+" ") {
+		t.Error("expected recovery code hash to ignore case and whitespace")
+	}
+}
+
+func TestApplyTOTPPolicy(t *testing.T) {
+	defer func(roles []string) {
+		config.C.RequireTOTPRoles = roles
+	}(config.C.RequireTOTPRoles)
+	config.C.RequireTOTPRoles = []string{"MODIFY"}
+
+	roles := []models.RoleEnum{models.RoleEnumAdmin, models.RoleEnumModify, models.RoleEnumEdit}
+
+	enabled := &models.User{TOTPEnabled: true}
+	if got := ApplyTOTPPolicy(enabled, roles); !reflect.DeepEqual(got, roles) {
+		t.Errorf("enabled: got %v, want %v", got, roles)
+	}
+
+	want := []models.RoleEnum{models.RoleEnumEdit}
+	if got := ApplyTOTPPolicy(&models.User{}, roles); !reflect.DeepEqual(got, want) {
+		t.Errorf("not enabled: got %v, want %v", got, want)
+	}
+
+	config.C.RequireTOTPRoles = nil
+	if got := ApplyTOTPPolicy(&models.User{}, roles); !reflect.DeepEqual(got, roles) {
+		t.Errorf("no required roles: got %v, want %v", got, roles)
+	}
+}
@@ -0,0 +1,274 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// attempts to enter a two-factor code are limited per user, since codes are
+// short enough to guess
+const totpAttemptsPerMinute = 5
+
+var (
+	ErrTOTPRequired        = errors.New("two-factor authentication code required")
+	ErrTOTPInvalidCode     = errors.New("invalid two-factor authentication code")
+	ErrTOTPAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrTOTPNotEnrolled     = errors.New("two-factor authentication enrolment has not been started")
+	ErrTOTPTooManyAttempts = errors.New("too many two-factor authentication attempts, try again later")
+)
+
+var totpAttempts = NewRateLimiter()
+
+func findUser(qb models.UserRepo, userID uuid.UUID) (*models.User, error) {
+	u, err := qb.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrUserNotExist
+	}
+
+	return u, nil
+}
+
+// EnrollTOTP starts two-factor enrolment for the user, returning the new
+// secret and its otpauth URI. Enrolment is completed by VerifyTOTP.
+func EnrollTOTP(fac models.Repo, userID uuid.UUID) (string, string, error) {
+	qb := fac.User()
+	u, err := findUser(qb, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.TOTPEnabled {
+		return "", "", ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	u.TOTPSecret.String = secret
+	u.TOTPSecret.Valid = true
+	u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.Update(*u); err != nil {
+		return "", "", err
+	}
+
+	return secret, totpURI(config.GetTitle(), u.Name, secret), nil
+}
+
+// VerifyTOTP completes two-factor enrolment with a code from the user's
+// authenticator app, and returns the user's recovery codes.
+func VerifyTOTP(fac models.Repo, userID uuid.UUID, code string) ([]string, error) {
+	qb := fac.User()
+	u, err := findUser(qb, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if !u.TOTPSecret.Valid {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	if err := validateTOTPAttempt(qb, u, code); err != nil {
+		return nil, err
+	}
+
+	u.TOTPEnabled = true
+	u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.Update(*u); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(qb, userID)
+}
+
+// DisableTOTP disables two-factor authentication for the user. If
+// requireCode is true, code must be a valid two-factor or recovery code.
+func DisableTOTP(fac models.Repo, userID uuid.UUID, code *string, requireCode bool) error {
+	qb := fac.User()
+	u, err := findUser(qb, userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.TOTPEnabled && !u.TOTPSecret.Valid {
+		return ErrTOTPNotEnabled
+	}
+
+	if requireCode && u.TOTPEnabled {
+		if code == nil {
+			return ErrTOTPRequired
+		}
+		if err := validateSecondFactor(qb, u, *code); err != nil {
+			return err
+		}
+	}
+
+	u.TOTPSecret = sql.NullString{}
+	u.TOTPEnabled = false
+	u.TOTPLastStep = 0
+	u.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.UpdateFull(*u); err != nil {
+		return err
+	}
+
+	return qb.UpdateRecoveryCodes(userID, nil)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, given a valid
+// code from their authenticator app.
+func RegenerateRecoveryCodes(fac models.Repo, userID uuid.UUID, code string) ([]string, error) {
+	qb := fac.User()
+	u, err := findUser(qb, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.TOTPEnabled {
+		return nil, ErrTOTPNotEnabled
+	}
+
+	if err := validateTOTPAttempt(qb, u, code); err != nil {
+		return nil, err
+	}
+
+	return generateRecoveryCodes(qb, userID)
+}
+
+// AuthenticateSecondFactor validates the second login step for the user.
+// Users without two-factor authentication enabled pass without a code.
+// Otherwise, code must be a valid two-factor or recovery code.
+func AuthenticateSecondFactor(fac models.Repo, userID string, code string) error {
+	qb := fac.User()
+	userUUID, _ := uuid.FromString(userID)
+	u, err := findUser(qb, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if !u.TOTPEnabled {
+		return nil
+	}
+
+	if code == "" {
+		return ErrTOTPRequired
+	}
+
+	return validateSecondFactor(qb, u, code)
+}
+
+// validateSecondFactor accepts either a code from the user's authenticator
+// app or one of their recovery codes, which is then used up.
+func validateSecondFactor(qb models.UserRepo, u *models.User, code string) error {
+	err := validateTOTPAttempt(qb, u, code)
+	if err != ErrTOTPInvalidCode {
+		return err
+	}
+
+	codes, err := qb.GetRecoveryCodes(u.ID)
+	if err != nil {
+		return err
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, c := range codes {
+		if c.CodeHash == hash {
+			remaining := append(codes[:i:i], codes[i+1:]...)
+			return qb.UpdateRecoveryCodes(u.ID, remaining)
+		}
+	}
+
+	return ErrTOTPInvalidCode
+}
+
+func validateTOTPAttempt(qb models.UserRepo, u *models.User, code string) error {
+	now := time.Now()
+	if allowed, _ := totpAttempts.Allow(u.ID, totpAttemptsPerMinute, now); !allowed {
+		return ErrTOTPTooManyAttempts
+	}
+
+	step, ok := validateTOTPCode(u.TOTPSecret.String, code, now, u.TOTPLastStep)
+	if !ok {
+		return ErrTOTPInvalidCode
+	}
+
+	// prevent the code from being reused
+	u.TOTPLastStep = step
+	_, err := qb.Update(*u)
+	return err
+}
+
+func generateRecoveryCodes(qb models.UserRepo, userID uuid.UUID) ([]string, error) {
+	var ret []string
+	var codes models.UserRecoveryCodes
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, code)
+		codes = append(codes, &models.UserRecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	if err := qb.UpdateRecoveryCodes(userID, codes); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// ApplyTOTPPolicy removes the roles that require two-factor authentication
+// from the roles of a user that has not enabled it. Roles that imply a
+// required role are removed too.
+func ApplyTOTPPolicy(u *models.User, roles []models.RoleEnum) []models.RoleEnum {
+	if u == nil || u.TOTPEnabled {
+		return roles
+	}
+
+	var required []models.RoleEnum
+	for _, v := range config.GetRequireTOTPRoles() {
+		role := models.RoleEnum(v)
+		if role.IsValid() {
+			required = append(required, role)
+		}
+	}
+
+	if len(required) == 0 {
+		return roles
+	}
+
+	var ret []models.RoleEnum
+	for _, role := range roles {
+		allowed := true
+		for _, r := range required {
+			if role.Implies(r) {
+				allowed = false
+				break
+			}
+		}
+
+		if allowed {
+			ret = append(ret, role)
+		}
+	}
+
+	return ret
+}
@@ -48,6 +48,12 @@ var rootUserRoles []models.RoleEnum = []models.RoleEnum{
 	models.RoleEnumAdmin,
 }
 
+// HasEmail returns true if the user has an email address that can be
+// emailed.
+func HasEmail(u *models.User) bool {
+	return u.Email != "" && u.Email != unsetEmail
+}
+
 func ValidateCreate(input models.UserCreateInput) error {
 	// username must be set
 	err := validateUserName(input.Name)
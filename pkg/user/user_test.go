@@ -205,3 +205,15 @@ func TestDestroyUser(t *testing.T) {
 		}
 	}
 }
+
+func TestHasEmail(t *testing.T) {
+	for email, expected := range map[string]bool{
+		"test@example.com": true,
+		"":                 false,
+		"none":             false,
+	} {
+		if got := user.HasEmail(&models.User{Email: email}); got != expected {
+			t.Errorf("email: %q - got %v; want %v", email, got, expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/stashapp/stash-box/pkg/logger"
+	"github.com/stashapp/stash-box/pkg/manager/config"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+const (
+	SignatureHeader = "X-StashBox-Signature"
+	EventHeader     = "X-StashBox-Event"
+	DeliveryHeader  = "X-StashBox-Delivery"
+
+	// number of due deliveries processed per batch
+	batchSize = 50
+
+	minBackoff = 30 * time.Second
+	maxBackoff = 6 * time.Hour
+)
+
+type RepoProvider interface {
+	Repo() models.Repo
+}
+
+var triggerChan = make(chan struct{}, 1)
+
+// trigger wakes the dispatcher without waiting for the retry interval.
+func trigger() {
+	select {
+	case triggerChan <- struct{}{}:
+	default:
+	}
+}
+
+type dispatcher struct {
+	rfp    RepoProvider
+	client *http.Client
+}
+
+// Start runs the delivery dispatcher in the background. Pending deliveries
+// are processed when new deliveries are committed, and at the configured
+// retry interval.
+func Start(rfp RepoProvider) {
+	interval, err := time.ParseDuration(config.GetWebhookRetryInterval())
+	if err != nil {
+		panic(err.Error())
+	}
+
+	d := dispatcher{
+		rfp: rfp,
+		client: &http.Client{
+			Timeout: config.GetWebhookTimeout(),
+		},
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// process anything left over from a previous run
+		d.processDeliveries()
+
+		for {
+			select {
+			case <-triggerChan:
+			case <-ticker.C:
+			}
+			d.processDeliveries()
+		}
+	}()
+
+	logger.Debugf("Webhook dispatcher initialized to retry every %s", interval)
+}
+
+func (d dispatcher) processDeliveries() {
+	for {
+		// deliveries are claimed for long enough to send the whole batch, so
+		// that other instances do not send them too. Deliveries that are not
+		// updated, such as when the server stops, are retried once the claim
+		// expires.
+		claimUntil := time.Now().Add(batchSize * d.client.Timeout)
+
+		fac := d.rfp.Repo()
+		var deliveries models.WebhookDeliveries
+		err := fac.WithTxn(func() error {
+			var err error
+			deliveries, err = fac.Webhook().ClaimDueDeliveries(batchSize, claimUntil)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("Webhook dispatcher failed to fetch pending deliveries: %s", err.Error())
+			return
+		}
+
+		for _, delivery := range deliveries {
+			if err := d.processDelivery(delivery); err != nil {
+				logger.Errorf("Webhook dispatcher failed to process delivery %s: %s", delivery.ID.String(), err.Error())
+				return
+			}
+		}
+
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+func (d dispatcher) processDelivery(delivery *models.WebhookDelivery) error {
+	fac := d.rfp.Repo()
+	wqb := fac.Webhook()
+
+	webhook, err := wqb.Find(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		// the webhook was destroyed after the delivery was fetched, which
+		// also destroys its deliveries
+		logger.Debugf("Dropping webhook delivery %s of destroyed webhook %s", delivery.ID.String(), delivery.WebhookID.String())
+		return nil
+	}
+
+	currentTime := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt.Time = currentTime
+	delivery.LastAttemptAt.Valid = true
+
+	statusCode, err := send(d.client, webhook, delivery)
+	if err == nil {
+		delivery.Succeed(statusCode)
+	} else {
+		delivery.Retry(statusCode, err.Error(), config.GetWebhookMaxAttempts(), currentTime.Add(backoff(delivery.Attempts)))
+		logger.Debugf("Webhook delivery %s attempt %d failed: %s", delivery.ID.String(), delivery.Attempts, err.Error())
+	}
+
+	return fac.WithTxn(func() error {
+		_, err := fac.Webhook().UpdateDelivery(*delivery)
+		return err
+	})
+}
+
+// send posts the delivery payload to the webhook URL. It returns the
+// response status code, and an error if the delivery was unsuccessful.
+func send(client *http.Client, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+	req.Header.Set(EventHeader, eventName(delivery.TargetType, delivery.Operation))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before retrying a delivery that has failed the
+// provided number of attempts.
+func backoff(attempts int) time.Duration {
+	delay := time.Duration(float64(minBackoff) * math.Pow(2, float64(attempts-1)))
+	if delay > maxBackoff || delay <= 0 {
+		return maxBackoff
+	}
+	return delay
+}
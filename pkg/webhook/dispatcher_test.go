@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+func newTestDelivery(t *testing.T, webhook *models.Webhook) *models.WebhookDelivery {
+	event := Event{
+		TargetType: models.TargetTypeEnumPerformer,
+		Operation:  models.OperationEnumModify,
+		TargetID:   uuid.Must(uuid.NewV4()),
+	}
+
+	body, err := event.payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return models.NewWebhookDelivery(uuid.Must(uuid.NewV4()), webhook, event.TargetType, event.Operation, event.TargetID, body)
+}
+
+func TestSend(t *testing.T) {
+	var gotBody []byte
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		gotHeader = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{
+		ID:     uuid.Must(uuid.NewV4()),
+		URL:    server.URL,
+		Secret: "secret",
+	}
+	delivery := newTestDelivery(t, webhook)
+
+	status, err := send(server.Client(), webhook, delivery)
+	if err != nil {
+		t.Fatalf("send returned error: %s", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", status, http.StatusNoContent)
+	}
+
+	if string(gotBody) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", gotBody, delivery.Payload)
+	}
+	if got, want := gotHeader.Get(SignatureHeader), Sign("secret", gotBody); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if got := gotHeader.Get(EventHeader); got != "performer.modify" {
+		t.Errorf("event = %s, want performer.modify", got)
+	}
+	if got := gotHeader.Get(DeliveryHeader); got != delivery.ID.String() {
+		t.Errorf("delivery = %s, want %s", got, delivery.ID.String())
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{
+		ID:  uuid.Must(uuid.NewV4()),
+		URL: server.URL,
+	}
+	delivery := newTestDelivery(t, webhook)
+
+	status, err := send(server.Client(), webhook, delivery)
+	if err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+	if status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", status, http.StatusInternalServerError)
+	}
+
+	const maxAttempts = 2
+	delivery.Attempts = 1
+	delivery.Retry(status, err.Error(), maxAttempts, time.Now().Add(backoff(delivery.Attempts)))
+	if delivery.Status != models.WebhookDeliveryStatusEnumPending.String() {
+		t.Errorf("status after first failure = %s, want PENDING", delivery.Status)
+	}
+
+	delivery.Attempts = 2
+	delivery.Retry(status, err.Error(), maxAttempts, time.Now().Add(backoff(delivery.Attempts)))
+	if delivery.Status != models.WebhookDeliveryStatusEnumFailed.String() {
+		t.Errorf("status after final failure = %s, want FAILED", delivery.Status)
+	}
+}
+
+type testRepoProvider struct {
+	repo models.Repo
+}
+
+func (p testRepoProvider) Repo() models.Repo {
+	return p.repo
+}
+
+type testRepo struct {
+	models.Repo
+	webhooks models.WebhookRepo
+}
+
+func (r testRepo) Webhook() models.WebhookRepo {
+	return r.webhooks
+}
+
+// testWebhookRepo finds no webhooks, and fails the test if a delivery is
+// updated.
+type testWebhookRepo struct {
+	models.WebhookRepo
+	t *testing.T
+}
+
+func (r testWebhookRepo) Find(id uuid.UUID) (*models.Webhook, error) {
+	return nil, nil
+}
+
+func (r testWebhookRepo) UpdateDelivery(delivery models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	r.t.Error("UpdateDelivery called for delivery of missing webhook")
+	return &delivery, nil
+}
+
+func TestProcessDeliveryMissingWebhook(t *testing.T) {
+	d := dispatcher{
+		rfp: testRepoProvider{
+			repo: testRepo{webhooks: testWebhookRepo{t: t}},
+		},
+		client: http.DefaultClient,
+	}
+
+	webhook := &models.Webhook{ID: uuid.Must(uuid.NewV4())}
+	if err := d.processDelivery(newTestDelivery(t, webhook)); err != nil {
+		t.Errorf("processDelivery returned error: %s", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{100, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package webhook delivers signed notifications of entity changes to
+// externally registered endpoints.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stash-box/pkg/models"
+)
+
+// Event describes a change to an entity that webhooks may subscribe to.
+type Event struct {
+	TargetType models.TargetTypeEnum
+	Operation  models.OperationEnum
+	TargetID   uuid.UUID
+	// EditID is set when the change was made by applying an edit.
+	EditID *uuid.UUID
+	// MergeSourceIDs is set for merge operations.
+	MergeSourceIDs []string
+}
+
+// Name returns the event name sent to receivers, such as "performer.modify".
+func (e Event) Name() string {
+	return eventName(e.TargetType.String(), e.Operation.String())
+}
+
+func eventName(targetType string, operation string) string {
+	return strings.ToLower(targetType) + "." + strings.ToLower(operation)
+}
+
+type payload struct {
+	Event          string     `json:"event"`
+	TargetType     string     `json:"target_type"`
+	Operation      string     `json:"operation"`
+	TargetID       uuid.UUID  `json:"target_id"`
+	EditID         *uuid.UUID `json:"edit_id,omitempty"`
+	MergeSourceIDs []string   `json:"merge_source_ids,omitempty"`
+	Timestamp      time.Time  `json:"timestamp"`
+}
+
+func (e Event) payload() ([]byte, error) {
+	return json.Marshal(payload{
+		Event:          e.Name(),
+		TargetType:     e.TargetType.String(),
+		Operation:      e.Operation.String(),
+		TargetID:       e.TargetID,
+		EditID:         e.EditID,
+		MergeSourceIDs: e.MergeSourceIDs,
+		Timestamp:      time.Now().UTC(),
+	})
+}
+
+// Enqueue queues a delivery of the event for every active webhook whose
+// filters match it. Deliveries are written in the current transaction and
+// dispatched once it has been committed.
+func Enqueue(fac models.Repo, event Event) error {
+	wqb := fac.Webhook()
+	webhooks, err := wqb.FindActiveByEvent(event.TargetType, event.Operation)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	body, err := event.payload()
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+
+		delivery := models.NewWebhookDelivery(UUID, webhook, event.TargetType, event.Operation, event.TargetID, body)
+		if event.EditID != nil {
+			delivery.EditID = uuid.NullUUID{UUID: *event.EditID, Valid: true}
+		}
+
+		if _, err := wqb.CreateDelivery(*delivery); err != nil {
+			return err
+		}
+	}
+
+	Trigger(fac)
+
+	return nil
+}
+
+// Trigger wakes the dispatcher to process pending deliveries once the
+// current transaction has been committed.
+func Trigger(fac models.Repo) {
+	fac.AddPostCommitHook(trigger)
+}
+
+// Sign returns the value of the signature header for the provided body,
+// which is the hex encoded HMAC-SHA256 of the body keyed with the webhook
+// secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateURL returns an error if the provided webhook URL is not an
+// absolute http or https URL.
+func ValidateURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+
+	return nil
+}